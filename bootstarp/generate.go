@@ -20,6 +20,18 @@ import (
 )
 
 func main() {
+	// 子命令
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "validate":
+			os.Exit(runValidate(os.Args[2:]))
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "tags":
+			os.Exit(runTags(os.Args[2:]))
+		}
+	}
+
 	// 定义命令行参数
 	// del /S /Q pkg\*.yaml pkg\*.json
 	outputDir := flag.String("output", ".", "输出目录,配置文件将生成到该目录下的pkg文件夹")
@@ -27,6 +39,10 @@ func main() {
 	listModules := flag.Bool("list", false, "列出所有可用的模块")
 	help := flag.Bool("help", false, "显示帮助信息")
 	backup := flag.Bool("backup", false, "是否备份已有的配置文件")
+	genTOML := flag.Bool("toml", false, "同时生成TOML配置文件")
+	genProperties := flag.Bool("properties", false, "同时生成properties配置文件")
+	schemaOnly := flag.Bool("schema", false, "只导出JSON Schema文件")
+	verify := flag.Bool("verify", false, "执行往返校验(生成→加载→与默认值比较)而不写入配置文件")
 
 	flag.Parse()
 
@@ -37,7 +53,10 @@ func main() {
 	}
 
 	// 创建生成器
-	generator := goconfig.NewSmartConfigGenerator(*outputDir).WithBackupExisting(*backup)
+	generator := goconfig.NewSmartConfigGenerator(*outputDir).
+		WithBackupExisting(*backup).
+		WithGenerateTOML(*genTOML).
+		WithGenerateProperties(*genProperties)
 
 	// 列出所有模块
 	if *listModules {
@@ -45,6 +64,16 @@ func main() {
 		return
 	}
 
+	// 只导出 JSON Schema
+	if *schemaOnly {
+		os.Exit(runSchemaExport(generator, *modules))
+	}
+
+	// 往返校验
+	if *verify {
+		os.Exit(runVerify(generator, *modules))
+	}
+
 	// 生成配置文件
 	var err error
 	if *modules == "" {
@@ -74,6 +103,9 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("用法:")
 	fmt.Println("  generate-configs [选项]")
+	fmt.Println("  generate-configs validate -module <模块> [-format text|json] [-report 文件] [-strict] <配置文件>...")
+	fmt.Println("  generate-configs migrate -module <模块> [-to 版本] [--dry-run] [-backup=false] <配置文件>...")
+	fmt.Println("  generate-configs tags [-root 源码目录] [-format text|json] [-fix]")
 	fmt.Println()
 	fmt.Println("选项:")
 	fmt.Println("  -output string")
@@ -82,6 +114,14 @@ func showHelp() {
 	fmt.Println("        指定要生成的模块,多个模块用逗号分隔 (为空则生成所有模块)")
 	fmt.Println("  -list")
 	fmt.Println("        列出所有可用的模块")
+	fmt.Println("  -toml")
+	fmt.Println("        同时生成TOML配置文件")
+	fmt.Println("  -properties")
+	fmt.Println("        同时生成properties配置文件")
+	fmt.Println("  -schema")
+	fmt.Println("        只导出JSON Schema文件")
+	fmt.Println("  -verify")
+	fmt.Println("        执行往返校验,发现格式漂移时退出码为1")
 	fmt.Println("  -help")
 	fmt.Println("        显示此帮助信息")
 	fmt.Println()
@@ -97,18 +137,100 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("  # 列出所有可用模块")
 	fmt.Println("  generate-configs -list")
+	fmt.Println()
+	fmt.Println("  # 同时生成TOML与properties模板")
+	fmt.Println("  generate-configs -modules cache -toml -properties")
+	fmt.Println()
+	fmt.Println("  # 往返校验所有模块(yaml/toml/properties)")
+	fmt.Println("  generate-configs -verify")
+	fmt.Println()
+	fmt.Println("  # 校验配置文件(存在错误时退出码为1,可用于CI)")
+	fmt.Println("  generate-configs validate -module gateway config-prod.yaml")
+	fmt.Println()
+	fmt.Println("  # 以JSON格式输出报告,便于生成CI注解")
+	fmt.Println("  generate-configs validate -module gateway -format json -report report.json config-prod.yaml")
+	fmt.Println()
+	fmt.Println("  # 预览配置迁移差异(保留注释,不写入文件)")
+	fmt.Println("  generate-configs migrate -module gateway --dry-run config-prod.yaml")
+	fmt.Println()
+	fmt.Println("  # 检查并自动修复配置结构体的 mapstructure/yaml/json 标签")
+	fmt.Println("  generate-configs tags -fix")
+}
+
+// enableModules 按逗号分隔的模块列表只启用指定模块，为空时保持全部启用
+func enableModules(generator *goconfig.SmartConfigGenerator, modules string) bool {
+	if modules == "" {
+		return true
+	}
+	moduleList := strings.Split(modules, ",")
+	for i, m := range moduleList {
+		moduleList[i] = strings.TrimSpace(m)
+	}
+	if err := generator.EnableOnlyModules(moduleList...); err != nil {
+		fmt.Fprintf(os.Stderr, "启用模块失败: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runSchemaExport 导出启用模块的 JSON Schema
+func runSchemaExport(generator *goconfig.SmartConfigGenerator, modules string) int {
+	if !enableModules(generator, modules) {
+		return 1
+	}
+	if err := generator.GenerateAllSchemas(); err != nil {
+		fmt.Fprintf(os.Stderr, "导出JSON Schema失败: %v\n", err)
+		return 1
+	}
+	fmt.Println("✓ JSON Schema导出成功!")
+	return 0
+}
+
+// runVerify 执行往返校验并输出结果
+func runVerify(generator *goconfig.SmartConfigGenerator, modules string) int {
+	if !enableModules(generator, modules) {
+		return 1
+	}
+
+	results, err := generator.VerifyAllRoundTrips()
+	for _, result := range results {
+		if result.OK() {
+			fmt.Printf("✓ %s\n", result)
+		} else {
+			fmt.Printf("✗ %s\n", result)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "往返校验失败: %v\n", err)
+		return 1
+	}
+	fmt.Println("✓ 往返校验全部通过!")
+	return 0
 }
 
 func listAllModules(generator *goconfig.SmartConfigGenerator) {
 	fmt.Println("可用的模块列表:")
 	fmt.Println()
 
-	modules := generator.GetModuleList()
+	// 遍历全局模块注册表（包含通过 goconfig.RegisterModule 注册的自定义模块）
+	modules := make([]goconfig.ModuleConfig, 0)
+	for _, name := range generator.GetModuleList() {
+		if module, err := generator.GetModuleInfo(name); err == nil {
+			modules = append(modules, *module)
+		}
+	}
 
-	// 直接显示模块名列表
-	fmt.Println("  可用模块:")
-	for _, name := range modules {
-		fmt.Printf("    - %s\n", name)
+	for _, module := range modules {
+		fmt.Printf("  - %-16s %s\n", module.Name, module.Description)
+		if module.Team != "" {
+			fmt.Printf("      团队: %s\n", module.Team)
+		}
+		if len(module.Tags) > 0 {
+			fmt.Printf("      标签: %s\n", strings.Join(module.Tags, ", "))
+		}
+		if len(module.DependsOn) > 0 {
+			fmt.Printf("      依赖: %s\n", strings.Join(module.DependsOn, ", "))
+		}
 	}
 
 	fmt.Println()
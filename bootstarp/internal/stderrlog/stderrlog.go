@@ -0,0 +1,35 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\bootstarp\internal\stderrlog\stderrlog.go
+ * @Description: 将全局日志重定向到标准错误，保证子命令的标准输出只包含报告
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+// Package stderrlog 在 go-config 各包初始化之前把全局日志改写到标准错误。
+// 包初始化按导入路径排序，本包只依赖 go-logger，路径排在 go-config/internal 与 pkg/* 之前，
+// 因此先于 go-config 的 init（环境初始化等日志）执行
+package stderrlog
+
+import (
+	"os"
+
+	"github.com/kamalyes/go-logger"
+)
+
+// subcommands 标准输出需要保持可解析的子命令
+var subcommands = map[string]bool{"validate": true}
+
+func init() {
+	if len(os.Args) > 1 && subcommands[os.Args[1]] {
+		Redirect()
+	}
+}
+
+// Redirect 将全局日志输出改为标准错误
+func Redirect() {
+	logger.GetGlobalLogger().WithOutput(os.Stderr)
+}
@@ -0,0 +1,69 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\bootstarp\migrate.go
+ * @Description: migrate 子命令 - 按注册的迁移链升级配置文件，保留注释
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	goconfig "github.com/kamalyes/go-config"
+)
+
+// runMigrate 执行 migrate 子命令，返回进程退出码
+//
+//	generate-configs migrate -module gateway [-to 2] [--dry-run] [-backup=false] config-prod.yaml ...
+func runMigrate(args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	module := fs.String("module", "", "目标模块名称,如 gateway、database、wsc (必填)")
+	target := fs.String("to", "", "目标版本(为空则迁移到最新版本)")
+	dryRun := fs.Bool("dry-run", false, "只输出差异,不修改文件")
+	backup := fs.Bool("backup", true, "写入前备份原文件")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	files := fs.Args()
+	if *module == "" || len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "用法: generate-configs migrate -module <模块> [-to 版本] [--dry-run] [-backup=false] <配置文件>...")
+		return exitUsage
+	}
+
+	options := goconfig.MigrateOptions{TargetVersion: *target, DryRun: *dryRun, Backup: *backup}
+	for _, path := range files {
+		result, err := goconfig.MigrateConfigFile(path, *module, options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "迁移 %s 失败: %v\n", path, err)
+			return exitInvalid
+		}
+
+		if !result.Changed() {
+			fmt.Printf("✓ %s 已是版本 %s,无需迁移\n", path, result.ToVersion)
+			continue
+		}
+		if *dryRun {
+			fmt.Print(result.Diff())
+		}
+		for _, m := range result.Applied {
+			fmt.Printf("  %s -> %s %s\n", m.From, m.To, m.Description)
+		}
+		if *dryRun {
+			fmt.Printf("○ %s 将从版本 %s 迁移到 %s (dry-run,未写入)\n", path, result.FromVersion, result.ToVersion)
+			continue
+		}
+		fmt.Printf("✓ %s 已从版本 %s 迁移到 %s\n", path, result.FromVersion, result.ToVersion)
+		if result.BackupPath != "" {
+			fmt.Printf("  备份: %s\n", result.BackupPath)
+		}
+	}
+	return exitOK
+}
@@ -0,0 +1,83 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\bootstarp\tags.go
+ * @Description: tags 子命令 - 检查所有已注册模块配置结构体的标签一致性，可自动修复
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	goconfig "github.com/kamalyes/go-config"
+)
+
+// runTags 执行 tags 子命令，返回进程退出码
+//
+//	generate-configs tags [-root .] [-format json] [-fix]
+func runTags(args []string) int {
+	fs := flag.NewFlagSet("tags", flag.ContinueOnError)
+	root := fs.String("root", ".", "源码根目录(go.mod 所在目录),用于定位与修复字段标签")
+	format := fs.String("format", "text", "输出格式: text 或 json")
+	fix := fs.Bool("fix", false, "将建议标签写回源文件")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "不支持的输出格式: %s\n", *format)
+		return exitUsage
+	}
+
+	checker := goconfig.NewTagChecker(*root)
+	issues := checker.CheckModules()
+
+	if *fix {
+		files, err := checker.Fix(issues)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "修复标签失败: %v\n", err)
+			return exitUsage
+		}
+		for _, file := range files {
+			fmt.Fprintf(os.Stderr, "已修复: %s\n", file)
+		}
+		// 当前进程中的类型仍是修复前的标签，只保留无法自动修复的问题
+		remaining := issues[:0]
+		for _, issue := range issues {
+			if !issue.Fixable() {
+				remaining = append(remaining, issue)
+			}
+		}
+		issues = remaining
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(issues); err != nil {
+			fmt.Fprintf(os.Stderr, "输出报告失败: %v\n", err)
+			return exitUsage
+		}
+	} else {
+		fixable := 0
+		for _, issue := range issues {
+			fmt.Println(issue.String())
+			if issue.Fixable() {
+				fixable++
+			}
+		}
+		fmt.Printf("共 %d 个标签问题, 其中 %d 个可通过 -fix 自动修复\n", len(issues), fixable)
+	}
+
+	if len(issues) > 0 {
+		return exitInvalid
+	}
+	return exitOK
+}
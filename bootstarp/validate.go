@@ -0,0 +1,122 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\bootstarp\validate.go
+ * @Description: validate 子命令 - 离线校验配置文件，供 CI 使用
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	goconfig "github.com/kamalyes/go-config"
+	"github.com/kamalyes/go-config/bootstarp/internal/stderrlog"
+)
+
+// 退出码
+const (
+	exitOK      = 0 // 校验通过
+	exitInvalid = 1 // 存在校验错误
+	exitUsage   = 2 // 参数错误或文件无法读取
+)
+
+// runValidate 执行 validate 子命令，返回进程退出码
+//
+//	generate-configs validate -module gateway [-format json] [-report out.json] [-strict] config-prod.yaml ...
+//
+// 日志一律写入标准错误，标准输出只包含报告
+func runValidate(args []string) int {
+	stderrlog.Redirect()
+	return validateFiles(args, os.Stdout, os.Stderr)
+}
+
+// validateFiles 校验配置文件，报告写入 stdout（或 -report 指定的文件），提示信息写入 stderr
+func validateFiles(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	module := fs.String("module", "", "目标模块名称,如 gateway、database、wsc (必填)")
+	file := fs.String("file", "", "要校验的配置文件,也可以作为位置参数传入多个文件")
+	format := fs.String("format", "text", "输出格式: text 或 json")
+	reportPath := fs.String("report", "", "将报告写入指定文件(默认输出到标准输出)")
+	strict := fs.Bool("strict", false, "存在警告(如未知配置项)或已禁用模块内的错误时也返回非零退出码")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	files := fs.Args()
+	if *file != "" {
+		files = append([]string{*file}, files...)
+	}
+	if *module == "" || len(files) == 0 {
+		fmt.Fprintln(stderr, "用法: generate-configs validate -module <模块> [-format text|json] [-report 文件] [-strict] <配置文件>...")
+		return exitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "不支持的输出格式: %s\n", *format)
+		return exitUsage
+	}
+
+	reports := make([]*goconfig.ConfigFileReport, 0, len(files))
+	for _, path := range files {
+		report, err := goconfig.ValidateModuleConfigFile(path, *module)
+		if err != nil {
+			fmt.Fprintf(stderr, "校验 %s 失败: %v\n", path, err)
+			return exitUsage
+		}
+		reports = append(reports, report)
+	}
+
+	out := stdout
+	if *reportPath != "" {
+		f, err := os.Create(*reportPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "创建报告文件失败: %v\n", err)
+			return exitUsage
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(stderr, "输出报告失败: %v\n", err)
+			return exitUsage
+		}
+	} else {
+		printTextReports(out, reports)
+	}
+
+	exitCode := exitOK
+	for _, report := range reports {
+		if report.HasErrors() || (*strict && (report.WarningCount() > 0 || report.DisabledErrorCount() > 0)) {
+			exitCode = exitInvalid
+		}
+	}
+	return exitCode
+}
+
+// printTextReports 以 file:line:column 格式输出报告
+func printTextReports(out io.Writer, reports []*goconfig.ConfigFileReport) {
+	for _, report := range reports {
+		for _, issue := range report.Issues {
+			fmt.Fprintln(out, issue.String())
+		}
+		status := "✓"
+		if report.HasErrors() {
+			status = "✗"
+		}
+		fmt.Fprintf(out, "%s %s (模块: %s): %d 个错误, %d 个警告, %d 个位于已禁用模块的错误\n",
+			status, report.File, report.Module, report.ErrorCount(), report.WarningCount(), report.DisabledErrorCount())
+	}
+}
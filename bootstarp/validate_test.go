@@ -0,0 +1,91 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\bootstarp\validate_test.go
+ * @Description: validate 子命令测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goconfig "github.com/kamalyes/go-config"
+	"github.com/kamalyes/go-config/pkg/database"
+	"github.com/kamalyes/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout 执行 fn 并返回其写入标准输出的内容
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	fn()
+	require.NoError(t, w.Close())
+	return <-done
+}
+
+func TestRunValidate_JSONStdout(t *testing.T) {
+	// 模块加载时写日志，模拟注册与环境初始化等日志
+	require.NoError(t, goconfig.RegisterModule(goconfig.ModuleConfig{
+		Name: "validate-logging",
+		DefaultFunc: func() interface{} {
+			logger.GetGlobalLogger().Info("加载默认配置")
+			return database.DefaultDatabaseConfig()
+		},
+	}))
+	defer goconfig.UnregisterModule("validate-logging")
+
+	path := filepath.Join(t.TempDir(), "database.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("mysql:\n  host: db\n  max-idle-conns: abc\n"), 0644))
+
+	var code int
+	out := captureStdout(t, func() {
+		logger.GetGlobalLogger().WithOutput(os.Stdout)
+		code = runValidate([]string{"-module", "validate-logging", "-format", "json", path})
+	})
+
+	var reports []goconfig.ConfigFileReport
+	require.NoError(t, json.Unmarshal(out, &reports), "标准输出只包含 JSON 报告: %s", out)
+	require.Len(t, reports, 1)
+	assert.Equal(t, exitInvalid, code)
+	assert.Equal(t, path, reports[0].File)
+	assert.True(t, reports[0].HasErrors())
+	for _, issue := range reports[0].Issues {
+		if issue.Path == "mysql.max-idle-conns" {
+			assert.False(t, issue.Disabled)
+		}
+	}
+}
+
+func TestRunValidate_DisabledErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("mysql:\n  host: db\n"), 0644))
+	assert.Equal(t, exitInvalid, validateFiles([]string{"-module", "database", path}, io.Discard, io.Discard),
+		"已配置模块内的必填项错误导致失败")
+
+	require.NoError(t, os.WriteFile(path, []byte("enabled: false\nmysql:\n  host: db\n"), 0644))
+	assert.Equal(t, exitOK, validateFiles([]string{"-module", "database", path}, io.Discard, io.Discard),
+		"显式禁用的模块内的错误不导致失败")
+	assert.Equal(t, exitInvalid, validateFiles([]string{"-module", "database", "-strict", path}, io.Discard, io.Discard))
+	assert.Equal(t, exitUsage, validateFiles([]string{path}, io.Discard, io.Discard))
+}
@@ -0,0 +1,460 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_editor.go
+ * @Description: 基于 YAML AST 的配置文件编辑器 - 修改键值时保留注释、锚点与键顺序
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLEditor 基于 yaml.Node 的配置编辑器
+// 与重新生成整个文件不同，只修改目标节点，手写注释、锚点和键顺序都会保留
+// 路径使用点分格式（如 http.tls.enabled、items[0].name），键名按灵活命名规则匹配
+type YAMLEditor struct {
+	filePath string    // 来源文件路径（NewYAMLEditor 创建时为空）
+	doc      yaml.Node // 文档根节点
+	indent   int       // 输出缩进，沿用原文件
+}
+
+// NewYAMLEditor 从 YAML 内容创建编辑器
+func NewYAMLEditor(data []byte) (*YAMLEditor, error) {
+	editor := &YAMLEditor{indent: detectYAMLIndent(data)}
+	if err := yaml.Unmarshal(data, &editor.doc); err != nil {
+		return nil, ErrParseYAMLDocument(err)
+	}
+	if editor.doc.Kind == 0 {
+		editor.doc = yaml.Node{Kind: yaml.DocumentNode}
+	}
+	return editor, nil
+}
+
+// LoadYAMLEditor 从文件创建编辑器
+func LoadYAMLEditor(filePath string) (*YAMLEditor, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, ErrReadConfigFile(err)
+	}
+	editor, err := NewYAMLEditor(data)
+	if err != nil {
+		return nil, err
+	}
+	editor.filePath = filePath
+	return editor, nil
+}
+
+// FilePath 返回来源文件路径
+func (e *YAMLEditor) FilePath() string {
+	return e.filePath
+}
+
+// Get 获取路径对应的值节点（支持别名与 << 合并键）
+func (e *YAMLEditor) Get(path string) (*yaml.Node, bool) {
+	segments := parseConfigPath(path)
+	if len(segments) == 0 || len(e.doc.Content) == 0 {
+		return nil, false
+	}
+
+	node := e.doc.Content[0]
+	for _, seg := range segments {
+		if node == nil {
+			return nil, false
+		}
+		node = resolveAlias(node)
+		switch node.Kind {
+		case yaml.MappingNode:
+			_, node = findMappingEntry(node, seg)
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return nil, false
+			}
+			node = node.Content[idx]
+		default:
+			return nil, false
+		}
+	}
+	if node == nil {
+		return nil, false
+	}
+	return resolveAlias(node), true
+}
+
+// Has 判断路径是否存在
+func (e *YAMLEditor) Has(path string) bool {
+	_, ok := e.Get(path)
+	return ok
+}
+
+// GetString 获取标量值的字符串形式
+func (e *YAMLEditor) GetString(path string) (string, bool) {
+	node, ok := e.Get(path)
+	if !ok || node.Kind != yaml.ScalarNode {
+		return "", false
+	}
+	return node.Value, true
+}
+
+// Set 设置路径对应的值，不存在的中间映射会自动创建
+// 替换已有节点时保留其注释与锚点，标签相同时还会保留引号风格
+func (e *YAMLEditor) Set(path string, value any) error {
+	segments := parseConfigPath(path)
+	if len(segments) == 0 {
+		return ErrInvalidConfigPath(path)
+	}
+
+	newNode, ok := value.(*yaml.Node)
+	if !ok {
+		newNode = &yaml.Node{}
+		if err := newNode.Encode(value); err != nil {
+			return ErrEncodeYAMLDocument(err)
+		}
+	}
+
+	parent, err := e.ensureParent(segments, path)
+	if err != nil {
+		return err
+	}
+
+	last := segments[len(segments)-1]
+	switch parent.Kind {
+	case yaml.MappingNode:
+		if idx := directMappingIndex(parent, last); idx >= 0 {
+			replaceYAMLNode(parent.Content[idx+1], newNode)
+			return nil
+		}
+		parent.Content = append(parent.Content, newYAMLKey(last), newNode)
+	case yaml.SequenceNode:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx > len(parent.Content) {
+			return ErrInvalidConfigPath(path)
+		}
+		if idx == len(parent.Content) {
+			parent.Content = append(parent.Content, newNode)
+			return nil
+		}
+		replaceYAMLNode(parent.Content[idx], newNode)
+	default:
+		return ErrConfigPathConflict(path)
+	}
+	return nil
+}
+
+// Delete 删除路径对应的键（或序列元素），返回是否发生了删除
+func (e *YAMLEditor) Delete(path string) (bool, error) {
+	segments := parseConfigPath(path)
+	if len(segments) == 0 {
+		return false, ErrInvalidConfigPath(path)
+	}
+
+	parent, err := e.lookupParent(segments, path)
+	if err != nil || parent == nil {
+		return false, err
+	}
+
+	last := segments[len(segments)-1]
+	switch parent.Kind {
+	case yaml.MappingNode:
+		idx := directMappingIndex(parent, last)
+		if idx < 0 {
+			return false, nil
+		}
+		parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+	case yaml.SequenceNode:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx >= len(parent.Content) {
+			return false, nil
+		}
+		parent.Content = append(parent.Content[:idx], parent.Content[idx+1:]...)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// Rename 将键移动到新路径，返回是否发生了移动
+// 同一映射内重命名时保持原位置，跨层级移动时追加到目标映射末尾，键上的注释随之移动
+func (e *YAMLEditor) Rename(from, to string) (bool, error) {
+	fromSegments := parseConfigPath(from)
+	toSegments := parseConfigPath(to)
+	if len(fromSegments) == 0 {
+		return false, ErrInvalidConfigPath(from)
+	}
+	if len(toSegments) == 0 {
+		return false, ErrInvalidConfigPath(to)
+	}
+
+	parent, err := e.lookupParent(fromSegments, from)
+	if err != nil || parent == nil || parent.Kind != yaml.MappingNode {
+		return false, err
+	}
+	idx := directMappingIndex(parent, fromSegments[len(fromSegments)-1])
+	if idx < 0 {
+		return false, nil
+	}
+	if e.Has(to) {
+		return false, ErrConfigKeyExists(to)
+	}
+
+	key, value := parent.Content[idx], parent.Content[idx+1]
+	newKey := toSegments[len(toSegments)-1]
+
+	// 同一父级: 原地改名以保持顺序
+	if configPathEqual(fromSegments[:len(fromSegments)-1], toSegments[:len(toSegments)-1]) {
+		key.Value = newKey
+		return true, nil
+	}
+
+	// 不能移动到自身之下
+	if len(toSegments) > len(fromSegments) && configPathEqual(fromSegments, toSegments[:len(fromSegments)]) {
+		return false, ErrConfigPathConflict(to)
+	}
+
+	// 先解析（必要时创建）目标父级，失败时源键保持不变；
+	// 新建的中间映射只会追加到各映射末尾，不影响源键的下标
+	target, err := e.ensureParent(toSegments, to)
+	if err != nil {
+		return false, err
+	}
+	if target.Kind != yaml.MappingNode {
+		return false, ErrConfigPathConflict(to)
+	}
+	parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+	key.Value = newKey
+	target.Content = append(target.Content, key, value)
+	return true, nil
+}
+
+// Bytes 将文档编码为 YAML
+func (e *YAMLEditor) Bytes() ([]byte, error) {
+	if len(e.doc.Content) == 0 {
+		return []byte{}, nil
+	}
+
+	normalizeMergeKeys(&e.doc)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(e.indent)
+	if err := encoder.Encode(&e.doc); err != nil {
+		return nil, ErrEncodeYAMLDocument(err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, ErrEncodeYAMLDocument(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Save 写回来源文件
+func (e *YAMLEditor) Save() error {
+	return e.SaveAs(e.filePath)
+}
+
+// SaveAs 写入指定文件
+func (e *YAMLEditor) SaveAs(filePath string) error {
+	data, err := e.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return ErrWriteConfigFile(err)
+	}
+	return nil
+}
+
+// root 返回文档的顶层节点，空文档时创建映射
+func (e *YAMLEditor) root() *yaml.Node {
+	if len(e.doc.Content) == 0 {
+		e.doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	return e.doc.Content[0]
+}
+
+// lookupParent 查找路径最后一段所在的父节点，路径不存在时返回 nil
+// 编辑只作用于文件中直接书写的键，不穿过别名
+func (e *YAMLEditor) lookupParent(segments []string, path string) (*yaml.Node, error) {
+	node := e.root()
+	for _, seg := range segments[:len(segments)-1] {
+		if node.Kind == yaml.AliasNode {
+			return nil, ErrEditThroughAlias(path)
+		}
+		next := childYAMLNode(node, seg)
+		if next == nil {
+			return nil, nil
+		}
+		node = next
+	}
+	if node.Kind == yaml.AliasNode {
+		return nil, ErrEditThroughAlias(path)
+	}
+	return node, nil
+}
+
+// ensureParent 与 lookupParent 相同，但会创建缺失的中间映射，值为 null 的中间节点改写为空映射
+func (e *YAMLEditor) ensureParent(segments []string, path string) (*yaml.Node, error) {
+	node := e.root()
+	for i, seg := range segments[:len(segments)-1] {
+		if node.Kind == yaml.AliasNode {
+			return nil, ErrEditThroughAlias(path)
+		}
+		next := childYAMLNode(node, seg)
+		if next == nil {
+			if node.Kind != yaml.MappingNode {
+				return nil, ErrConfigPathConflict(formatConfigPath(segments[:i+1]))
+			}
+			next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, newYAMLKey(seg), next)
+		}
+		if next.Kind == yaml.ScalarNode && next.ShortTag() == "!!null" {
+			next.Kind, next.Tag, next.Value, next.Style = yaml.MappingNode, "!!map", "", 0
+		}
+		if next.Kind == yaml.ScalarNode {
+			return nil, ErrConfigPathConflict(formatConfigPath(segments[:i+1]))
+		}
+		node = next
+	}
+	if node.Kind == yaml.AliasNode {
+		return nil, ErrEditThroughAlias(path)
+	}
+	return node, nil
+}
+
+// childYAMLNode 获取映射或序列的直接子节点
+func childYAMLNode(node *yaml.Node, seg string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if idx := directMappingIndex(node, seg); idx >= 0 {
+			return node.Content[idx+1]
+		}
+	case yaml.SequenceNode:
+		if idx, err := strconv.Atoi(seg); err == nil && idx >= 0 && idx < len(node.Content) {
+			return node.Content[idx]
+		}
+	}
+	return nil
+}
+
+// directMappingIndex 返回键在映射中的下标（不查找合并键），未找到返回 -1
+func directMappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if configKeyEqual(mapping.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceYAMLNode 原地替换节点内容，保留注释与锚点，使引用该锚点的别名同步生效
+func replaceYAMLNode(old, replacement *yaml.Node) {
+	head, line, foot, anchor := old.HeadComment, old.LineComment, old.FootComment, old.Anchor
+	style := old.Style
+	sameTag := old.Kind == replacement.Kind && old.Tag == replacement.Tag
+
+	*old = *replacement
+	if old.HeadComment == "" {
+		old.HeadComment = head
+	}
+	if old.LineComment == "" {
+		old.LineComment = line
+	}
+	if old.FootComment == "" {
+		old.FootComment = foot
+	}
+	if old.Anchor == "" {
+		old.Anchor = anchor
+	}
+	if sameTag && old.Kind == yaml.ScalarNode {
+		old.Style = style
+	}
+}
+
+// newYAMLKey 创建映射键节点
+func newYAMLKey(key string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+}
+
+// configPathEqual 按灵活命名规则比较两个路径
+func configPathEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !configKeyEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeMergeKeys 去掉合并键上的显式 !!merge 标签，避免编码后输出 "!!merge <<"
+func normalizeMergeKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if key := node.Content[i]; key.Value == "<<" && key.Tag == "!!merge" {
+				key.Tag = ""
+			}
+		}
+	}
+	for _, child := range node.Content {
+		normalizeMergeKeys(child)
+	}
+}
+
+// detectYAMLIndent 根据首个缩进行推断原文件缩进，默认 2
+func detectYAMLIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if spaces := len(line) - len(trimmed); spaces >= 2 && spaces <= 8 {
+			return spaces
+		}
+	}
+	return 2
+}
+
+// EditModuleConfig 就地编辑模块已生成的 YAML 配置文件
+// 与重新生成不同，文件中手写的注释和键顺序都会保留
+func (sg *SmartConfigGenerator) EditModuleConfig(moduleName string, edit func(editor *YAMLEditor) error) error {
+	module, exists := sg.ModuleRegistry[moduleName]
+	if !exists {
+		return ErrModuleNotFound(moduleName)
+	}
+
+	filePath := filepath.Join(sg.BaseOutputDir, "pkg", module.OutputSubDir, module.Name+".yaml")
+	editor, err := LoadYAMLEditor(filePath)
+	if err != nil {
+		return err
+	}
+	if err := edit(editor); err != nil {
+		return err
+	}
+
+	if sg.BackupExisting {
+		backupPath := filePath + ".backup." + time.Now().Format("20060102_150405")
+		if err := sg.copyFile(filePath, backupPath); err != nil {
+			sg.Logger.WarnKV("备份文件失败", "file", filePath, "backup", backupPath, "error", err.Error())
+		}
+	}
+	if err := editor.Save(); err != nil {
+		return err
+	}
+
+	sg.Logger.DebugKV("模块配置文件已编辑", "module", moduleName, "file", filePath)
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_editor_test.go
+ * @Description: YAML 配置编辑器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const editorTestYAML = `# 服务配置
+defaults: &defaults
+  timeout: 30s # 默认超时
+  retries: 3
+server:
+  <<: *defaults
+  # 监听地址
+  host: "0.0.0.0"
+  port: 8080 # HTTP 端口
+  enable-tls: true
+  hosts:
+    - a
+    - b
+`
+
+func newTestEditor(t *testing.T, content string) *YAMLEditor {
+	t.Helper()
+	editor, err := NewYAMLEditor([]byte(content))
+	require.NoError(t, err)
+	return editor
+}
+
+func editorOutput(t *testing.T, editor *YAMLEditor) string {
+	t.Helper()
+	data, err := editor.Bytes()
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestYAMLEditor_RoundTripPreservesCommentsAndAnchors(t *testing.T) {
+	editor := newTestEditor(t, editorTestYAML)
+	assert.Equal(t, editorTestYAML, editorOutput(t, editor))
+}
+
+func TestYAMLEditor_Get(t *testing.T) {
+	editor := newTestEditor(t, editorTestYAML)
+
+	value, ok := editor.GetString("server.port")
+	assert.True(t, ok)
+	assert.Equal(t, "8080", value)
+
+	// 通过合并键读取
+	value, ok = editor.GetString("server.timeout")
+	assert.True(t, ok)
+	assert.Equal(t, "30s", value)
+
+	// 灵活命名匹配
+	assert.True(t, editor.Has("server.enable_tls"))
+	assert.True(t, editor.Has("server.hosts[1]"))
+	assert.False(t, editor.Has("server.hosts[5]"))
+	assert.False(t, editor.Has("missing.key"))
+}
+
+func TestYAMLEditor_Set(t *testing.T) {
+	editor := newTestEditor(t, editorTestYAML)
+
+	require.NoError(t, editor.Set("server.port", 9090))
+	require.NoError(t, editor.Set("server.host", "127.0.0.1"))
+	require.NoError(t, editor.Set("server.tls.cert-file", "/etc/tls.crt"))
+	require.NoError(t, editor.Set("defaults.retries", 5))
+	require.NoError(t, editor.Set("server.hosts[2]", "c"))
+
+	out := editorOutput(t, editor)
+	assert.Contains(t, out, "  port: 9090 # HTTP 端口\n")
+	assert.Contains(t, out, "  # 监听地址\n  host: \"127.0.0.1\"\n")
+	assert.Contains(t, out, "  tls:\n    cert-file: /etc/tls.crt\n")
+	assert.Contains(t, out, "defaults: &defaults\n  timeout: 30s # 默认超时\n  retries: 5\n")
+	assert.Contains(t, out, "    - c\n")
+
+	// 合并键继承的值在本地覆盖，而不是改动锚点定义
+	require.NoError(t, editor.Set("server.timeout", "10s"))
+	out = editorOutput(t, editor)
+	assert.Contains(t, out, "  timeout: 30s # 默认超时\n")
+	assert.Contains(t, out, "  timeout: 10s\n")
+
+	assert.Error(t, editor.Set("server.port.value", 1), "标量节点下不能继续写入")
+	assert.Error(t, editor.Set("", 1))
+}
+
+func TestYAMLEditor_Delete(t *testing.T) {
+	editor := newTestEditor(t, editorTestYAML)
+
+	deleted, err := editor.Delete("server.port")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = editor.Delete("server.hosts[0]")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = editor.Delete("server.not-exists")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	out := editorOutput(t, editor)
+	assert.NotContains(t, out, "port")
+	assert.NotContains(t, out, "- a")
+	assert.Contains(t, out, "# 监听地址")
+}
+
+func TestYAMLEditor_Rename(t *testing.T) {
+	editor := newTestEditor(t, editorTestYAML)
+
+	// 同级重命名保持位置
+	renamed, err := editor.Rename("server.host", "server.listen")
+	require.NoError(t, err)
+	assert.True(t, renamed)
+
+	// 跨层级移动
+	renamed, err = editor.Rename("server.enable-tls", "server.tls.enabled")
+	require.NoError(t, err)
+	assert.True(t, renamed)
+
+	renamed, err = editor.Rename("server.missing", "server.other")
+	require.NoError(t, err)
+	assert.False(t, renamed)
+
+	_, err = editor.Rename("server.listen", "server.port")
+	assert.Error(t, err, "目标已存在时拒绝覆盖")
+
+	// 目标路径冲突时源键保持不变
+	_, err = editor.Rename("server.listen", "server.port.address")
+	assert.Error(t, err)
+	_, err = editor.Rename("server.hosts", "server.hosts.all")
+	assert.Error(t, err, "不能移动到自身之下")
+	assert.True(t, editor.Has("server.listen"))
+	assert.True(t, editor.Has("server.hosts"))
+
+	out := editorOutput(t, editor)
+	assert.Contains(t, out, "  # 监听地址\n  listen: \"0.0.0.0\"\n  port: 8080")
+	assert.Contains(t, out, "  tls:\n    enabled: true\n")
+	assert.NotContains(t, out, "enable-tls")
+}
+
+func TestYAMLEditor_AliasGuard(t *testing.T) {
+	editor := newTestEditor(t, "base: &b\n  a: 1\nother: *b\n")
+	assert.Error(t, editor.Set("other.a", 2))
+	_, err := editor.Delete("other.a")
+	assert.Error(t, err)
+}
+
+func TestYAMLEditor_EmptyDocumentAndIndent(t *testing.T) {
+	editor := newTestEditor(t, "")
+	require.NoError(t, editor.Set("a.b", true))
+	assert.Equal(t, "a:\n  b: true\n", editorOutput(t, editor))
+
+	editor = newTestEditor(t, "a:\n    b: 1\n")
+	require.NoError(t, editor.Set("a.c", 2))
+	assert.Equal(t, "a:\n    b: 1\n    c: 2\n", editorOutput(t, editor))
+}
+
+func TestYAMLEditor_LoadAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(editorTestYAML), 0644))
+
+	editor, err := LoadYAMLEditor(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, editor.FilePath())
+	require.NoError(t, editor.Set("server.port", 80))
+	require.NoError(t, editor.Save())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "port: 80 # HTTP 端口")
+
+	_, err = LoadYAMLEditor(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+	_, err = NewYAMLEditor([]byte("a: [b"))
+	assert.Error(t, err)
+}
+
+func TestSmartConfigGenerator_EditModuleConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewSmartConfigGenerator(tempDir).WithBackupExisting(false)
+	require.NoError(t, generator.EnableOnlyModules("ratelimit"))
+	require.NoError(t, generator.GenerateAllConfigs())
+
+	yamlPath := filepath.Join(tempDir, "pkg", "ratelimit", "ratelimit.yaml")
+	before, err := os.ReadFile(yamlPath)
+	require.NoError(t, err)
+
+	require.NoError(t, generator.EditModuleConfig("ratelimit", func(editor *YAMLEditor) error {
+		return editor.Set("global-limit.burst-size", 999)
+	}))
+
+	after, err := os.ReadFile(yamlPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(after), "burst-size: 999")
+	assert.Contains(t, string(after), "# yaml-language-server: $schema=./ratelimit.schema.json", "文件头注释应保留")
+	assert.Equal(t, len(splitDiffLines(string(before))), len(splitDiffLines(string(after))))
+
+	assert.Error(t, generator.EditModuleConfig("no-such-module", func(*YAMLEditor) error { return nil }))
+}
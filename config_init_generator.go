@@ -14,9 +14,6 @@ package goconfig
 import (
 	"encoding/json"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -25,53 +22,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/kamalyes/go-config/pkg/alerting"
-	"github.com/kamalyes/go-config/pkg/banner"
-	"github.com/kamalyes/go-config/pkg/breaker"
-	"github.com/kamalyes/go-config/pkg/cache"
-	"github.com/kamalyes/go-config/pkg/captcha"
-	"github.com/kamalyes/go-config/pkg/consul"
-	"github.com/kamalyes/go-config/pkg/cors"
-	"github.com/kamalyes/go-config/pkg/database"
-	"github.com/kamalyes/go-config/pkg/elasticsearch"
-	"github.com/kamalyes/go-config/pkg/email"
-	"github.com/kamalyes/go-config/pkg/etcd"
-	"github.com/kamalyes/go-config/pkg/ftp"
-	"github.com/kamalyes/go-config/pkg/gateway"
-	"github.com/kamalyes/go-config/pkg/grafana"
-	"github.com/kamalyes/go-config/pkg/health"
-	"github.com/kamalyes/go-config/pkg/i18n"
-	"github.com/kamalyes/go-config/pkg/jaeger"
-	"github.com/kamalyes/go-config/pkg/jobs"
-	"github.com/kamalyes/go-config/pkg/jwt"
-	"github.com/kamalyes/go-config/pkg/kafka"
-	"github.com/kamalyes/go-config/pkg/logging"
-	"github.com/kamalyes/go-config/pkg/metrics"
-	"github.com/kamalyes/go-config/pkg/middleware"
-	"github.com/kamalyes/go-config/pkg/monitoring"
-	"github.com/kamalyes/go-config/pkg/oss"
-	"github.com/kamalyes/go-config/pkg/pay"
-	"github.com/kamalyes/go-config/pkg/pprof"
-	"github.com/kamalyes/go-config/pkg/prometheus"
-	"github.com/kamalyes/go-config/pkg/queue"
-	"github.com/kamalyes/go-config/pkg/ratelimit"
-	"github.com/kamalyes/go-config/pkg/recovery"
-	"github.com/kamalyes/go-config/pkg/redis"
-	"github.com/kamalyes/go-config/pkg/restful"
-	"github.com/kamalyes/go-config/pkg/rpcclient"
-	"github.com/kamalyes/go-config/pkg/rpcserver"
-	"github.com/kamalyes/go-config/pkg/security"
-	"github.com/kamalyes/go-config/pkg/signature"
-	"github.com/kamalyes/go-config/pkg/sms"
-	"github.com/kamalyes/go-config/pkg/smtp"
-	"github.com/kamalyes/go-config/pkg/sts"
-	"github.com/kamalyes/go-config/pkg/swagger"
-	"github.com/kamalyes/go-config/pkg/timeout"
-	"github.com/kamalyes/go-config/pkg/tracing"
-	"github.com/kamalyes/go-config/pkg/tsdb"
-	"github.com/kamalyes/go-config/pkg/wsc"
-	"github.com/kamalyes/go-config/pkg/youzan"
-	"github.com/kamalyes/go-config/pkg/zap"
 	gologger "github.com/kamalyes/go-logger"
 	"gopkg.in/yaml.v3"
 )
@@ -85,22 +35,29 @@ type ModuleConfig struct {
 	SourceFile    string             // 源文件路径
 	StructName    string             // 结构体名称
 	Description   string             // 模块描述
+	Team          string             // 负责团队
+	Tags          []string           // 标签，用于分组筛选
+	DependsOn     []string           // 依赖的其他模块名称
 	Enabled       bool               // 是否启用此模块生成
 	LastGenerated time.Time          // 最后生成时间
 }
 
 // SmartConfigGenerator 智能配置生成器
 type SmartConfigGenerator struct {
-	BaseOutputDir     string                       // 基础输出目录
-	Logger            *gologger.Logger             // 日志记录器
-	ModuleRegistry    map[string]ModuleConfig      // 模块注册表
-	ForceRegenerate   bool                         // 是否强制重新生成
-	IncludeComments   bool                         // 是否包含注释
-	GenerateJSON      bool                         // 是否生成JSON
-	GenerateYAML      bool                         // 是否生成YAML
-	BackupExisting    bool                         // 是否备份现有文件
-	OverwriteExisting bool                         // 是否覆盖现有文件
-	commentCache      map[string]map[string]string // 注释缓存: packagePath -> fieldName -> comment
+	BaseOutputDir      string                       // 基础输出目录
+	Logger             *gologger.Logger             // 日志记录器
+	ModuleRegistry     map[string]ModuleConfig      // 模块注册表
+	ForceRegenerate    bool                         // 是否强制重新生成
+	IncludeComments    bool                         // 是否包含注释
+	GenerateJSON       bool                         // 是否生成JSON
+	GenerateYAML       bool                         // 是否生成YAML
+	GenerateTOML       bool                         // 是否生成TOML
+	GenerateProperties bool                         // 是否生成properties
+	GenerateSchema     bool                         // 是否在YAML旁生成 .schema.json
+	BackupExisting     bool                         // 是否备份现有文件
+	OverwriteExisting  bool                         // 是否覆盖现有文件
+	commentCache       map[string]map[string]string // 注释缓存: packagePath -> fieldName -> comment
+	schemaGenerator    *SchemaGenerator             // JSON Schema 生成器
 }
 
 // NewSmartConfigGenerator 创建新的智能配置生成器
@@ -108,16 +65,20 @@ func NewSmartConfigGenerator(baseOutputDir string) *SmartConfigGenerator {
 	logger := gologger.NewLogger()
 
 	generator := &SmartConfigGenerator{
-		BaseOutputDir:     baseOutputDir,
-		Logger:            logger,
-		ModuleRegistry:    make(map[string]ModuleConfig),
-		ForceRegenerate:   false,
-		IncludeComments:   true,
-		GenerateJSON:      true,
-		GenerateYAML:      true,
-		BackupExisting:    true,
-		OverwriteExisting: true,
-		commentCache:      make(map[string]map[string]string),
+		BaseOutputDir:      baseOutputDir,
+		Logger:             logger,
+		ModuleRegistry:     make(map[string]ModuleConfig),
+		ForceRegenerate:    false,
+		IncludeComments:    true,
+		GenerateJSON:       true,
+		GenerateYAML:       true,
+		GenerateTOML:       false,
+		GenerateProperties: false,
+		GenerateSchema:     true,
+		BackupExisting:     true,
+		OverwriteExisting:  true,
+		commentCache:       make(map[string]map[string]string),
+		schemaGenerator:    NewSchemaGenerator(baseOutputDir),
 	}
 
 	// 自动注册所有模块
@@ -149,65 +110,29 @@ func (sg *SmartConfigGenerator) WithBackupExisting(backup bool) *SmartConfigGene
 	return sg
 }
 
-// registerAllModules 自动注册所有模块
-func (sg *SmartConfigGenerator) registerAllModules() {
-	modules := []ModuleConfig{
-		{Name: "alerting", PackageName: "alerting", DefaultFunc: func() interface{} { return alerting.Default() }, OutputSubDir: "alerting", Description: "告警模块", Enabled: true},
-		{Name: "banner", PackageName: "banner", DefaultFunc: func() interface{} { return banner.Default() }, OutputSubDir: "banner", Description: "Banner显示模块", Enabled: true},
-		{Name: "breaker", PackageName: "breaker", DefaultFunc: func() interface{} { return breaker.Default() }, OutputSubDir: "breaker", Description: "熔断器模块", Enabled: true},
-		{Name: "cache", PackageName: "cache", DefaultFunc: func() interface{} { return cache.Default() }, OutputSubDir: "cache", Description: "缓存模块", Enabled: true},
-		{Name: "captcha", PackageName: "captcha", DefaultFunc: func() interface{} { return captcha.Default() }, OutputSubDir: "captcha", Description: "验证码模块", Enabled: true},
-		{Name: "consul", PackageName: "consul", DefaultFunc: func() interface{} { return consul.Default() }, OutputSubDir: "consul", Description: "Consul服务发现模块", Enabled: true},
-		{Name: "cors", PackageName: "cors", DefaultFunc: func() interface{} { return cors.Default() }, OutputSubDir: "cors", Description: "CORS跨域模块", Enabled: true},
-		{Name: "database", PackageName: "database", DefaultFunc: func() interface{} { return database.DefaultDatabaseConfig() }, OutputSubDir: "database", Description: "数据库模块", Enabled: true},
-		{Name: "mysql", PackageName: "database", DefaultFunc: func() interface{} { return database.DefaultMySQL() }, OutputSubDir: "database", Description: "MySQL数据库", Enabled: true},
-		{Name: "postgresql", PackageName: "database", DefaultFunc: func() interface{} { return database.DefaultPostgreSQL() }, OutputSubDir: "database", Description: "PostgreSQL数据库", Enabled: true},
-		{Name: "sqlite", PackageName: "database", DefaultFunc: func() interface{} { return database.DefaultSQLite() }, OutputSubDir: "database", Description: "SQLite数据库", Enabled: true},
-		{Name: "cockroachdb", PackageName: "database", DefaultFunc: func() interface{} { return database.DefaultCockroachDB() }, OutputSubDir: "database", Description: "Cockroachdb数据库", Enabled: true},
-		{Name: "elasticsearch", PackageName: "elasticsearch", DefaultFunc: func() interface{} { return elasticsearch.Default() }, OutputSubDir: "elasticsearch", Description: "Elasticsearch搜索引擎", Enabled: true},
-		{Name: "email", PackageName: "email", DefaultFunc: func() interface{} { return email.Default() }, OutputSubDir: "email", Description: "邮件发送模块", Enabled: true},
-		{Name: "etcd", PackageName: "etcd", DefaultFunc: func() interface{} { return etcd.Default() }, OutputSubDir: "etcd", Description: "Etcd分布式键值存储", Enabled: true},
-		{Name: "ftp", PackageName: "ftp", DefaultFunc: func() interface{} { return ftp.Default() }, OutputSubDir: "ftp", Description: "FTP文件传输模块", Enabled: true},
-		{Name: "gateway", PackageName: "gateway", DefaultFunc: func() interface{} { return gateway.Default() }, OutputSubDir: "gateway", Description: "API网关模块", Enabled: true},
-		{Name: "grafana", PackageName: "grafana", DefaultFunc: func() interface{} { return grafana.Default() }, OutputSubDir: "grafana", Description: "Grafana监控面板", Enabled: true},
-		{Name: "health", PackageName: "health", DefaultFunc: func() interface{} { return health.Default() }, OutputSubDir: "health", Description: "健康检查模块", Enabled: true},
-		{Name: "i18n", PackageName: "i18n", DefaultFunc: func() interface{} { return i18n.Default() }, OutputSubDir: "i18n", Description: "国际化模块", Enabled: true},
-		{Name: "jaeger", PackageName: "jaeger", DefaultFunc: func() interface{} { return jaeger.Default() }, OutputSubDir: "jaeger", Description: "Jaeger链路追踪", Enabled: true},
-		{Name: "jwt", PackageName: "jwt", DefaultFunc: func() interface{} { return jwt.Default() }, OutputSubDir: "jwt", Description: "JWT认证模块", Enabled: true},
-		{Name: "kafka", PackageName: "kafka", DefaultFunc: func() interface{} { return kafka.Default() }, OutputSubDir: "kafka", Description: "Kafka消息队列", Enabled: true},
-		{Name: "logging", PackageName: "logging", DefaultFunc: func() interface{} { return logging.Default() }, OutputSubDir: "logging", Description: "日志记录模块", Enabled: true},
-		{Name: "metrics", PackageName: "metrics", DefaultFunc: func() interface{} { return metrics.Default() }, OutputSubDir: "metrics", Description: "指标收集模块", Enabled: true},
-		{Name: "middleware", PackageName: "middleware", DefaultFunc: func() interface{} { return middleware.Default() }, OutputSubDir: "middleware", Description: "中间件模块", Enabled: true},
-		{Name: "monitoring", PackageName: "monitoring", DefaultFunc: func() interface{} { return monitoring.Default() }, OutputSubDir: "monitoring", Description: "监控模块", Enabled: true},
-		{Name: "oss", PackageName: "oss", DefaultFunc: func() interface{} { return oss.DefaultOSSConfig() }, OutputSubDir: "oss", Description: "对象存储模块", Enabled: true},
-		{Name: "alipay", PackageName: "pay", DefaultFunc: func() interface{} { return pay.DefaultAliPay() }, OutputSubDir: "pay", Description: "支付宝支付", Enabled: true},
-		{Name: "wechatpay", PackageName: "pay", DefaultFunc: func() interface{} { return pay.DefaultWechatPay() }, OutputSubDir: "pay", Description: "微信支付", Enabled: true},
-		{Name: "pprof", PackageName: "pprof", DefaultFunc: func() interface{} { return pprof.Default() }, OutputSubDir: "pprof", Description: "性能分析模块", Enabled: true},
-		{Name: "prometheus", PackageName: "prometheus", DefaultFunc: func() interface{} { return prometheus.Default() }, OutputSubDir: "prometheus", Description: "Prometheus指标", Enabled: true},
-		{Name: "mqtt", PackageName: "queue", DefaultFunc: func() interface{} { return queue.Default() }, OutputSubDir: "queue", Description: "MQTT消息队列", Enabled: true},
-		{Name: "nats", PackageName: "queue", DefaultFunc: func() interface{} { return queue.DefaultNatsPtr() }, OutputSubDir: "queue", Description: "NATS消息队列", Enabled: true},
-		{Name: "ratelimit", PackageName: "ratelimit", DefaultFunc: func() interface{} { return ratelimit.Default() }, OutputSubDir: "ratelimit", Description: "限流模块", Enabled: true},
-		{Name: "recovery", PackageName: "recovery", DefaultFunc: func() interface{} { return recovery.Default() }, OutputSubDir: "recovery", Description: "错误恢复模块", Enabled: true},
-		{Name: "redis", PackageName: "redis", DefaultFunc: func() interface{} { return redis.NewRedis(&redis.Redis{}) }, OutputSubDir: "redis", Description: "Redis缓存", Enabled: true},
-		{Name: "restful", PackageName: "restful", DefaultFunc: func() interface{} { return restful.Default() }, OutputSubDir: "restful", Description: "RESTful API模块", Enabled: true},
-		{Name: "rpcclient", PackageName: "rpcclient", DefaultFunc: func() interface{} { return rpcclient.Default() }, OutputSubDir: "rpcclient", Description: "RPC客户端", Enabled: true},
-		{Name: "rpcserver", PackageName: "rpcserver", DefaultFunc: func() interface{} { return rpcserver.Default() }, OutputSubDir: "rpcserver", Description: "RPC服务端", Enabled: true},
-		{Name: "security", PackageName: "security", DefaultFunc: func() interface{} { return security.Default() }, OutputSubDir: "security", Description: "安全模块", Enabled: true},
-		{Name: "signature", PackageName: "signature", DefaultFunc: func() interface{} { return signature.Default() }, OutputSubDir: "signature", Description: "数字签名模块", Enabled: true},
-		{Name: "sms", PackageName: "sms", DefaultFunc: func() interface{} { return sms.DefaultAliyunSms() }, OutputSubDir: "sms", Description: "短信发送模块", Enabled: true},
-		{Name: "smtp", PackageName: "smtp", DefaultFunc: func() interface{} { return smtp.Default() }, OutputSubDir: "smtp", Description: "SMTP邮件模块", Enabled: true},
-		{Name: "sts", PackageName: "sts", DefaultFunc: func() interface{} { return sts.DefaultAliyunSts() }, OutputSubDir: "sts", Description: "STS临时凭证", Enabled: true},
-		{Name: "swagger", PackageName: "swagger", DefaultFunc: func() interface{} { return swagger.Default() }, OutputSubDir: "swagger", Description: "Swagger API文档", Enabled: true},
-		{Name: "clickhouse", PackageName: "tsdb", DefaultFunc: func() interface{} { return tsdb.DefaultClickHouse() }, OutputSubDir: "clickhouse", Description: "时间序列数据库模块", Enabled: true},
-		{Name: "timeout", PackageName: "timeout", DefaultFunc: func() interface{} { return timeout.Default() }, OutputSubDir: "timeout", Description: "超时控制模块", Enabled: true},
-		{Name: "tracing", PackageName: "tracing", DefaultFunc: func() interface{} { return tracing.Default() }, OutputSubDir: "tracing", Description: "链路追踪模块", Enabled: true},
-		{Name: "wsc", PackageName: "wsc", DefaultFunc: func() interface{} { return wsc.Default() }, OutputSubDir: "wsc", Description: "WebSocket通信模块", Enabled: true},
-		{Name: "youzan", PackageName: "youzan", DefaultFunc: func() interface{} { return youzan.Default() }, OutputSubDir: "youzan", Description: "有赞电商模块", Enabled: true},
-		{Name: "zap", PackageName: "zap", DefaultFunc: func() interface{} { return zap.Default() }, OutputSubDir: "zap", Description: "Zap日志模块", Enabled: true},
-		{Name: "jobs", PackageName: "jobs", DefaultFunc: func() interface{} { return jobs.Default() }, OutputSubDir: "jobs", Description: "任务调度模块", Enabled: true},
-	}
+// WithGenerateSchema 设置是否生成 JSON Schema 文件
+func (sg *SmartConfigGenerator) WithGenerateSchema(generate bool) *SmartConfigGenerator {
+	sg.GenerateSchema = generate
+	return sg
+}
+
+// WithGenerateTOML 设置是否生成TOML配置文件
+func (sg *SmartConfigGenerator) WithGenerateTOML(generate bool) *SmartConfigGenerator {
+	sg.GenerateTOML = generate
+	return sg
+}
+
+// WithGenerateProperties 设置是否生成properties配置文件
+func (sg *SmartConfigGenerator) WithGenerateProperties(generate bool) *SmartConfigGenerator {
+	sg.GenerateProperties = generate
+	return sg
+}
 
+// registerAllModules 从全局模块注册表载入所有模块（内置模块与通过 RegisterModule 注册的模块）
+func (sg *SmartConfigGenerator) registerAllModules() {
+	modules := RegisteredModules()
 	for _, module := range modules {
+		module.Enabled = true
 		sg.ModuleRegistry[module.Name] = module
 	}
 
@@ -284,6 +209,14 @@ func (sg *SmartConfigGenerator) GenerateModuleConfig(module ModuleConfig) error
 		if err := sg.generateYAMLConfig(defaultConfig, yamlPath, module); err != nil {
 			return ErrGenerateYAML(err)
 		}
+
+		// 在YAML旁生成JSON Schema，供编辑器自动补全和校验
+		if sg.GenerateSchema {
+			schemaPath := filepath.Join(outputDir, module.Name+".schema.json")
+			if err := sg.generateSchemaFile(defaultConfig, schemaPath, module); err != nil {
+				return ErrGenerateSchema(err)
+			}
+		}
 	}
 
 	// 生成JSON配置文件
@@ -294,6 +227,22 @@ func (sg *SmartConfigGenerator) GenerateModuleConfig(module ModuleConfig) error
 		}
 	}
 
+	// 生成TOML配置文件
+	if sg.GenerateTOML {
+		tomlPath := filepath.Join(outputDir, module.Name+".toml")
+		if err := sg.generateTOMLConfig(defaultConfig, tomlPath, module); err != nil {
+			return ErrGenerateTOML(err)
+		}
+	}
+
+	// 生成properties配置文件
+	if sg.GenerateProperties {
+		propertiesPath := filepath.Join(outputDir, module.Name+".properties")
+		if err := sg.generatePropertiesConfig(defaultConfig, propertiesPath, module); err != nil {
+			return ErrGenerateProperties(err)
+		}
+	}
+
 	// 更新最后生成时间
 	module.LastGenerated = time.Now()
 	sg.ModuleRegistry[module.Name] = module
@@ -350,45 +299,150 @@ func (sg *SmartConfigGenerator) GenerateModulesByNames(moduleNames ...string) er
 
 // generateYAMLConfig 生成YAML配置文件
 func (sg *SmartConfigGenerator) generateYAMLConfig(config interface{}, filePath string, module ModuleConfig) error {
-	// 备份现有文件
-	if sg.BackupExisting && sg.fileExists(filePath) {
-		backupPath := filePath + ".backup." + time.Now().Format("20060102_150405")
-		if err := sg.copyFile(filePath, backupPath); err != nil {
-			sg.Logger.WarnKV("备份文件失败", "file", filePath, "backup", backupPath, "error", err.Error())
-		}
+	if !sg.prepareOutputFile(filePath) {
+		return nil
 	}
 
-	// 如果文件已存在且不覆盖，则跳过
-	if !sg.OverwriteExisting && sg.fileExists(filePath) {
-		sg.Logger.DebugKV("跳过已存在的配置文件", "file", filePath)
-		return nil
+	content, err := sg.renderYAML(config, module)
+	if err != nil {
+		return err
 	}
 
+	// 写入文件
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return ErrWriteYAML(err)
+	}
+
+	sg.Logger.DebugKV("YAML配置文件生成成功", "file", filePath)
+	return nil
+}
+
+// renderYAML 渲染YAML配置内容
+func (sg *SmartConfigGenerator) renderYAML(config interface{}, module ModuleConfig) (string, error) {
 	// 序列化为YAML（函数类型字段会被自动跳过，因为它们应该有 yaml:"-" 标签）
 	yamlData, err := yaml.Marshal(config)
 	if err != nil {
-		return ErrMarshalYAML(err)
+		return "", ErrMarshalYAML(err)
 	}
 
 	// 添加文件头注释
 	content := sg.generateFileHeader(module, "yaml") + string(yamlData)
 
+	// 关联同目录的 JSON Schema（yaml-language-server 约定）
+	if sg.GenerateSchema {
+		content = "# yaml-language-server: $schema=./" + module.Name + ".schema.json\n" + content
+	}
+
 	// 如果包含注释，添加字段注释
 	if sg.IncludeComments {
 		content = sg.addFieldComments(content, config)
 	}
+	return content, nil
+}
+
+// generateJSONConfig 生成JSON配置文件
+func (sg *SmartConfigGenerator) generateJSONConfig(config interface{}, filePath string, module ModuleConfig) error {
+	if !sg.prepareOutputFile(filePath) {
+		return nil
+	}
+
+	content, err := sg.renderJSON(config, module)
+	if err != nil {
+		return err
+	}
 
 	// 写入文件
 	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return ErrWriteYAML(err)
+		return ErrWriteJSON(err)
 	}
 
-	sg.Logger.DebugKV("YAML配置文件生成成功", "file", filePath)
+	sg.Logger.DebugKV("JSON配置文件生成成功", "file", filePath)
 	return nil
 }
 
-// generateJSONConfig 生成JSON配置文件
-func (sg *SmartConfigGenerator) generateJSONConfig(config interface{}, filePath string, module ModuleConfig) error {
+// renderJSON 渲染JSON配置内容
+func (sg *SmartConfigGenerator) renderJSON(config interface{}, module ModuleConfig) (string, error) {
+	// 序列化为JSON
+	jsonData, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return "", ErrMarshalJSON(err)
+	}
+
+	// 添加文件头注释（JSON格式）
+	return sg.generateFileHeader(module, "json") + string(jsonData), nil
+}
+
+// generateTOMLConfig 生成TOML配置文件
+func (sg *SmartConfigGenerator) generateTOMLConfig(config interface{}, filePath string, module ModuleConfig) error {
+	if !sg.prepareOutputFile(filePath) {
+		return nil
+	}
+
+	content, err := sg.renderTOML(config, module)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return ErrWriteConfigOutput("TOML", err)
+	}
+
+	sg.Logger.DebugKV("TOML配置文件生成成功", "file", filePath)
+	return nil
+}
+
+// renderTOML 渲染TOML配置内容，键名与YAML模板一致
+func (sg *SmartConfigGenerator) renderTOML(config interface{}, module ModuleConfig) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return "", ErrMarshalYAML(err)
+	}
+
+	content := sg.generateFileHeader(module, "toml") + writeTOMLDocument(&node)
+	if sg.IncludeComments {
+		content = sg.addFieldCommentsWith(content, config, tomlCommentKey)
+	}
+	return content, nil
+}
+
+// generatePropertiesConfig 生成properties配置文件
+func (sg *SmartConfigGenerator) generatePropertiesConfig(config interface{}, filePath string, module ModuleConfig) error {
+	if !sg.prepareOutputFile(filePath) {
+		return nil
+	}
+
+	content, err := sg.renderProperties(config, module)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return ErrWriteConfigOutput("properties", err)
+	}
+
+	sg.Logger.DebugKV("properties配置文件生成成功", "file", filePath)
+	return nil
+}
+
+// renderProperties 渲染Java风格properties配置内容
+func (sg *SmartConfigGenerator) renderProperties(config interface{}, module ModuleConfig) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return "", ErrMarshalYAML(err)
+	}
+
+	var sb strings.Builder
+	writePropertiesNode(&sb, "", &node)
+
+	content := sg.generateFileHeader(module, "properties") + sb.String()
+	if sg.IncludeComments {
+		content = sg.addFieldCommentsWith(content, config, newPropertiesCommentKey())
+	}
+	return content, nil
+}
+
+// prepareOutputFile 写入前备份已有文件，返回 false 表示文件已存在且不覆盖
+func (sg *SmartConfigGenerator) prepareOutputFile(filePath string) bool {
 	// 备份现有文件
 	if sg.BackupExisting && sg.fileExists(filePath) {
 		backupPath := filePath + ".backup." + time.Now().Format("20060102_150405")
@@ -400,24 +454,78 @@ func (sg *SmartConfigGenerator) generateJSONConfig(config interface{}, filePath
 	// 如果文件已存在且不覆盖，则跳过
 	if !sg.OverwriteExisting && sg.fileExists(filePath) {
 		sg.Logger.DebugKV("跳过已存在的配置文件", "file", filePath)
+		return false
+	}
+	return true
+}
+
+// generateSchemaFile 生成JSON Schema文件
+func (sg *SmartConfigGenerator) generateSchemaFile(config interface{}, filePath string, module ModuleConfig) error {
+	if !sg.prepareOutputFile(filePath) {
 		return nil
 	}
 
-	// 序列化为JSON
-	jsonData, err := json.MarshalIndent(config, "", "  ")
+	schema, err := sg.schemaGenerator.Generate(config)
+	if err != nil {
+		return err
+	}
+	schema.ID = module.Name + ".schema.json"
+	if module.Description != "" {
+		schema.Title = module.Description
+	}
+
+	data, err := schema.ToJSON()
 	if err != nil {
 		return ErrMarshalJSON(err)
 	}
 
-	// 添加文件头注释（JSON格式）
-	content := sg.generateFileHeader(module, "json") + string(jsonData)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return ErrWriteSchema(err)
+	}
 
-	// 写入文件
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return ErrWriteJSON(err)
+	sg.Logger.DebugKV("JSON Schema文件生成成功", "file", filePath)
+	return nil
+}
+
+// GenerateAllSchemas 只为所有启用的模块导出 JSON Schema 文件（baseOutputDir/pkg/模块子目录/模块.schema.json）
+func (sg *SmartConfigGenerator) GenerateAllSchemas() error {
+	successCount := 0
+	failCount := 0
+
+	for _, name := range sg.GetEnabledModules() {
+		module := sg.ModuleRegistry[name]
+		if err := sg.generateModuleSchema(module); err != nil {
+			sg.Logger.ErrorKV("导出模块Schema失败", "module", name, "error", err.Error())
+			failCount++
+			continue
+		}
+		successCount++
 	}
 
-	sg.Logger.DebugKV("JSON配置文件生成成功", "file", filePath)
+	sg.Logger.InfoKV("模块Schema导出完成", "success", successCount, "failed", failCount)
+	if failCount > 0 {
+		return ErrPartialModuleFailed(successCount, failCount)
+	}
+	return nil
+}
+
+// generateModuleSchema 导出单个模块的 JSON Schema 文件
+func (sg *SmartConfigGenerator) generateModuleSchema(module ModuleConfig) error {
+	if module.DefaultFunc == nil {
+		return ErrModuleDefaultFuncNil(module.Name)
+	}
+	defaultConfig := module.DefaultFunc()
+	if defaultConfig == nil {
+		return ErrModuleConfigEmpty(module.Name)
+	}
+
+	outputDir := filepath.Join(sg.BaseOutputDir, "pkg", module.OutputSubDir)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return ErrCreateOutputDir(err)
+	}
+	if err := sg.generateSchemaFile(defaultConfig, filepath.Join(outputDir, module.Name+".schema.json"), module); err != nil {
+		return ErrGenerateSchema(err)
+	}
 	return nil
 }
 
@@ -448,6 +556,12 @@ func (sg *SmartConfigGenerator) generateFileHeader(module ModuleConfig, format s
 
 // addFieldComments 为YAML字段添加注释(改进版)
 func (sg *SmartConfigGenerator) addFieldComments(content string, config interface{}) string {
+	return sg.addFieldCommentsWith(content, config, yamlCommentKey)
+}
+
+// addFieldCommentsWith 为字段添加注释，keyOf 从去掉缩进的行中提取字段名（返回空串表示不是配置行）
+// YAML、TOML、properties 共用同一套注释来源，仅行格式不同
+func (sg *SmartConfigGenerator) addFieldCommentsWith(content string, config interface{}, keyOf func(trimmed string) string) string {
 	v := reflect.ValueOf(config)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -493,9 +607,7 @@ func (sg *SmartConfigGenerator) addFieldComments(content string, config interfac
 		}
 
 		// 检查是否是配置行
-		if colonIdx := strings.Index(trimmed, ":"); colonIdx > 0 {
-			fieldName := strings.TrimSpace(trimmed[:colonIdx])
-
+		if fieldName := keyOf(trimmed); fieldName != "" {
 			// 查找对应的注释
 			if comment, exists := fieldComments[fieldName]; exists {
 				// 计算缩进
@@ -515,6 +627,46 @@ func (sg *SmartConfigGenerator) addFieldComments(content string, config interfac
 	return strings.Join(result, "\n")
 }
 
+// yamlCommentKey 提取YAML行的键名（key: value）
+func yamlCommentKey(trimmed string) string {
+	if colonIdx := strings.Index(trimmed, ":"); colonIdx > 0 {
+		return strings.TrimSpace(trimmed[:colonIdx])
+	}
+	return ""
+}
+
+// tomlCommentKey 提取TOML行的键名: key = value 取键，[a.b] / [[a.b]] 表头取最后一段
+func tomlCommentKey(trimmed string) string {
+	if strings.HasPrefix(trimmed, "[") {
+		header := strings.Trim(trimmed, "[]")
+		if dot := strings.LastIndex(header, "."); dot >= 0 {
+			header = header[dot+1:]
+		}
+		return strings.Trim(header, `"`)
+	}
+	if eqIdx := strings.Index(trimmed, "="); eqIdx > 0 {
+		return strings.Trim(strings.TrimSpace(trimmed[:eqIdx]), `"`)
+	}
+	return ""
+}
+
+// newPropertiesCommentKey 提取properties行的顶层键名，同一顶层字段只在首行添加注释
+func newPropertiesCommentKey() func(trimmed string) string {
+	previous := ""
+	return func(trimmed string) string {
+		keyEnd, _ := splitPropertiesLine(trimmed)
+		key := trimmed[:keyEnd]
+		if idx := strings.IndexAny(key, ".["); idx >= 0 {
+			key = key[:idx]
+		}
+		if key == previous {
+			return ""
+		}
+		previous = key
+		return key
+	}
+}
+
 // toKebabCase 将驼峰转换为短横线命名
 func (sg *SmartConfigGenerator) toKebabCase(s string) string {
 	var result []rune
@@ -734,7 +886,7 @@ func (sg *SmartConfigGenerator) DisableModule(moduleName string) error {
 	return nil
 }
 
-// EnableOnlyModules 只启用指定的模块，其他模块全部禁用
+// EnableOnlyModules 只启用指定的模块（含其依赖的模块），其他模块全部禁用
 func (sg *SmartConfigGenerator) EnableOnlyModules(moduleNames ...string) error {
 	// 先禁用所有模块
 	for name, module := range sg.ModuleRegistry {
@@ -742,11 +894,20 @@ func (sg *SmartConfigGenerator) EnableOnlyModules(moduleNames ...string) error {
 		sg.ModuleRegistry[name] = module
 	}
 
-	// 再启用指定模块
-	for _, name := range moduleNames {
+	// 再启用指定模块及其依赖的模块
+	pending := append([]string(nil), moduleNames...)
+	enabled := make(map[string]bool, len(moduleNames))
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if enabled[name] {
+			continue
+		}
 		if err := sg.EnableModule(name); err != nil {
 			return err
 		}
+		enabled[name] = true
+		pending = append(pending, sg.ModuleRegistry[name].DependsOn...)
 	}
 
 	sg.Logger.InfoKV("只启用指定模块", "modules", strings.Join(moduleNames, ", "))
@@ -799,6 +960,13 @@ func (sg *SmartConfigGenerator) ValidateModuleConfig(moduleName string) error {
 		return ErrModuleDefaultFuncNil(moduleName)
 	}
 
+	// 检查依赖的模块是否已注册
+	for _, dep := range module.DependsOn {
+		if _, exists := sg.ModuleRegistry[dep]; !exists {
+			return ErrModuleDependencyMissing(moduleName, dep)
+		}
+	}
+
 	// 尝试调用默认函数
 	config := module.DefaultFunc()
 	if config == nil {
@@ -820,7 +988,7 @@ func (sg *SmartConfigGenerator) ValidateAllModules() error {
 
 	failedModules := make([]string, 0)
 
-	for name := range sg.ModuleRegistry {
+	for _, name := range sg.GetModuleList() {
 		if err := sg.ValidateModuleConfig(name); err != nil {
 			sg.Logger.ErrorKV("模块配置验证失败", "module", name, "error", err.Error())
 			failedModules = append(failedModules, name)
@@ -831,6 +999,10 @@ func (sg *SmartConfigGenerator) ValidateAllModules() error {
 		return ErrModulesValidationFailed(strings.Join(failedModules, ", "))
 	}
 
+	if err := checkModuleDependencies(sg.ModuleRegistry); err != nil {
+		return err
+	}
+
 	sg.Logger.InfoKV("所有模块配置验证通过", "count", len(sg.ModuleRegistry))
 	return nil
 }
@@ -919,53 +1091,15 @@ func (sg *SmartConfigGenerator) parseSourceComments(packagePath string, structNa
 
 	comments := make(map[string]string)
 
-	// 查找包的源代码目录
+	// 查找包的源代码目录，与 JSON Schema 生成器共用同一套注释解析
 	pkgDir := filepath.Join(sg.BaseOutputDir, "pkg", packagePath)
-
-	// 解析目录中的所有Go文件
-	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, pkgDir, nil, parser.ParseComments)
+	structs, err := parseStructComments(pkgDir)
 	if err != nil {
 		sg.Logger.WarnKV("解析源代码失败", "package", packagePath, "error", err.Error())
 		return comments
 	}
-
-	// 遍历所有包和文件
-	for _, pkg := range pkgs {
-		for _, file := range pkg.Files {
-			ast.Inspect(file, func(n ast.Node) bool {
-				// 查找结构体定义
-				typeSpec, ok := n.(*ast.TypeSpec)
-				if !ok || typeSpec.Name.Name != structName {
-					return true
-				}
-
-				// 确保是结构体
-				structType, ok := typeSpec.Type.(*ast.StructType)
-				if !ok {
-					return true
-				}
-
-				// 遍历结构体字段
-				for _, field := range structType.Fields.List {
-					if field.Comment == nil || len(field.Names) == 0 {
-						continue
-					}
-
-					// 提取注释文本
-					comment := strings.TrimSpace(field.Comment.Text())
-					comment = strings.TrimPrefix(comment, "//")
-					comment = strings.TrimSpace(comment)
-
-					// 保存到map
-					for _, name := range field.Names {
-						comments[name.Name] = comment
-					}
-				}
-
-				return false
-			})
-		}
+	if info, ok := structs[structName]; ok {
+		comments = info.Trailing
 	}
 
 	// 缓存结果
@@ -0,0 +1,382 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_migration.go
+ * @Description: 配置文件版本迁移 - 按版本链逐步改写配置文件并记录 config_version
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ConfigVersionKey 配置文件中记录已应用迁移版本的键
+	ConfigVersionKey = "config_version"
+	// InitialConfigVersion 未记录 config_version 的配置文件所处的版本
+	InitialConfigVersion = "0"
+)
+
+// Migration 配置迁移步骤，将配置文件从 From 版本改写到 To 版本
+type Migration struct {
+	From        string                         // 起始版本
+	To          string                         // 目标版本
+	Description string                         // 迁移说明
+	Apply       func(editor *YAMLEditor) error // 迁移逻辑，通过编辑器修改文件以保留注释
+}
+
+// RenameKeys 返回按顺序重命名键的迁移逻辑，源键不存在时跳过
+//
+//	Migration{From: "1", To: "2", Apply: RenameKeys("enable-tls", "tls.enabled")}
+func RenameKeys(pairs ...string) func(editor *YAMLEditor) error {
+	return func(editor *YAMLEditor) error {
+		if len(pairs)%2 != 0 {
+			return ErrInvalidConfigPath(pairs[len(pairs)-1])
+		}
+		for i := 0; i < len(pairs); i += 2 {
+			if _, err := editor.Rename(pairs[i], pairs[i+1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// MigrationRegistry 迁移注册表，按模块维护迁移链
+type MigrationRegistry struct {
+	mu         sync.RWMutex
+	migrations map[string]map[string]Migration // module -> from -> migration
+}
+
+// NewMigrationRegistry 创建迁移注册表
+func NewMigrationRegistry() *MigrationRegistry {
+	return &MigrationRegistry{migrations: make(map[string]map[string]Migration)}
+}
+
+var defaultMigrationRegistry = NewMigrationRegistry()
+
+// RegisterMigration 向默认注册表注册模块迁移，通常在 init() 中调用
+func RegisterMigration(module string, migrations ...Migration) error {
+	return defaultMigrationRegistry.Register(module, migrations...)
+}
+
+// MustRegisterMigration 向默认注册表注册模块迁移，失败时 panic，适用于 init()
+func MustRegisterMigration(module string, migrations ...Migration) {
+	if err := RegisterMigration(module, migrations...); err != nil {
+		panic(err)
+	}
+}
+
+// DefaultMigrationRegistry 返回默认迁移注册表
+func DefaultMigrationRegistry() *MigrationRegistry {
+	return defaultMigrationRegistry
+}
+
+// Register 注册模块迁移，同一模块的同一起始版本只能注册一次
+func (r *MigrationRegistry) Register(module string, migrations ...Migration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chain, ok := r.migrations[module]
+	if !ok {
+		chain = make(map[string]Migration)
+		r.migrations[module] = chain
+	}
+	for _, m := range migrations {
+		if m.From == "" || m.To == "" || m.From == m.To || m.Apply == nil {
+			return ErrMigrationInvalid(module, m.From, m.To)
+		}
+		if _, exists := chain[m.From]; exists {
+			return ErrMigrationDuplicate(module, m.From)
+		}
+		chain[m.From] = m
+	}
+	return nil
+}
+
+// Migrations 返回模块已注册的迁移（按起始版本的数值顺序排序）
+func (r *MigrationRegistry) Migrations(module string) []Migration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Migration, 0, len(r.migrations[module]))
+	for _, m := range r.migrations[module] {
+		result = append(result, m)
+	}
+	sort.Slice(result, func(i, j int) bool { return compareConfigVersions(result[i].From, result[j].From) < 0 })
+	return result
+}
+
+// compareConfigVersions 按点分段比较版本号（忽略前缀 v），数字段按数值比较，使 "9" 排在 "10" 之前
+func compareConfigVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		var c int
+		if aErr == nil && bErr == nil {
+			c = cmp.Compare(an, bn)
+		} else {
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// Plan 计算从 from 版本迁移到 to 版本需要依次执行的迁移
+// to 为空时迁移到迁移链末端
+func (r *MigrationRegistry) Plan(module, from, to string) ([]Migration, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chain := r.migrations[module]
+	plan := make([]Migration, 0)
+	visited := map[string]bool{from: true}
+	current := from
+	for current != to {
+		m, ok := chain[current]
+		if !ok {
+			if to == "" {
+				break
+			}
+			return nil, ErrMigrationTargetUnreachable(module, from, to)
+		}
+		if visited[m.To] {
+			return nil, ErrMigrationCycle(module, m.To)
+		}
+		visited[m.To] = true
+		plan = append(plan, m)
+		current = m.To
+	}
+	return plan, nil
+}
+
+// MigrateOptions 迁移选项
+type MigrateOptions struct {
+	TargetVersion string // 目标版本，为空时迁移到最新版本
+	DryRun        bool   // 只计算结果不写文件
+	Backup        bool   // 写入前备份原文件
+}
+
+// MigrationResult 配置文件迁移结果
+type MigrationResult struct {
+	File        string      // 配置文件路径
+	Module      string      // 模块名称
+	FromVersion string      // 迁移前版本
+	ToVersion   string      // 迁移后版本
+	Applied     []Migration // 已执行的迁移
+	Original    []byte      // 原始内容
+	Updated     []byte      // 迁移后内容
+	BackupPath  string      // 备份文件路径（未备份时为空）
+}
+
+// Changed 内容是否发生变化
+func (r *MigrationResult) Changed() bool {
+	return !bytes.Equal(r.Original, r.Updated)
+}
+
+// Diff 返回迁移前后的统一格式差异，文件名后附带版本号
+func (r *MigrationResult) Diff() string {
+	return unifiedDiff(string(r.Original), string(r.Updated), r.File+"@"+r.FromVersion, r.File+"@"+r.ToVersion)
+}
+
+// MigrateConfigFile 使用默认注册表迁移配置文件
+func MigrateConfigFile(filePath, module string, options MigrateOptions) (*MigrationResult, error) {
+	return defaultMigrationRegistry.MigrateFile(filePath, module, options)
+}
+
+// MigrateFile 按注册的迁移链改写配置文件，并将 config_version 更新为最终版本
+func (r *MigrationRegistry) MigrateFile(filePath, module string, options MigrateOptions) (*MigrationResult, error) {
+	editor, err := LoadYAMLEditor(filePath)
+	if err != nil {
+		return nil, err
+	}
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, ErrReadConfigFile(err)
+	}
+
+	current, ok := editor.GetString(ConfigVersionKey)
+	if !ok || current == "" {
+		current = InitialConfigVersion
+	}
+
+	result := &MigrationResult{
+		File:        filePath,
+		Module:      module,
+		FromVersion: current,
+		ToVersion:   current,
+		Original:    original,
+		Updated:     original,
+	}
+
+	plan, err := r.Plan(module, current, options.TargetVersion)
+	if err != nil || len(plan) == 0 {
+		return result, err
+	}
+
+	for _, m := range plan {
+		if err := m.Apply(editor); err != nil {
+			return nil, ErrMigrationApply(module, m.From, m.To, err)
+		}
+		if err := editor.Set(ConfigVersionKey, m.To); err != nil {
+			return nil, ErrMigrationApply(module, m.From, m.To, err)
+		}
+		result.Applied = append(result.Applied, m)
+		result.ToVersion = m.To
+	}
+
+	if result.Updated, err = editor.Bytes(); err != nil {
+		return nil, err
+	}
+
+	if options.DryRun || !result.Changed() {
+		return result, nil
+	}
+
+	if options.Backup {
+		result.BackupPath = filePath + ".backup." + time.Now().Format("20060102_150405")
+		if err := os.WriteFile(result.BackupPath, original, 0644); err != nil {
+			return nil, ErrWriteConfigFile(err)
+		}
+	}
+	if err := os.WriteFile(filePath, result.Updated, 0644); err != nil {
+		return nil, ErrWriteConfigFile(err)
+	}
+	return result, nil
+}
+
+// diffContextLines 差异输出中每个变更块保留的上下文行数
+const diffContextLines = 3
+
+// unifiedDiff 基于最长公共子序列生成统一格式差异，内容相同时返回空串
+func unifiedDiff(a, b, fromName, toName string) string {
+	if a == b {
+		return ""
+	}
+	oldLines := splitDiffLines(a)
+	newLines := splitDiffLines(b)
+
+	// lcs[i][j] 为 oldLines[i:] 与 newLines[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op       byte // ' '、'-'、'+'
+		text     string
+		old, new int // 该行之前已消费的旧/新行数
+	}
+	lines := make([]diffLine, 0, len(oldLines)+len(newLines))
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			lines = append(lines, diffLine{' ', oldLines[i], i, j})
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', oldLines[i], i, j})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', newLines[j], i, j})
+			j++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(lines); {
+		// 找到下一个变更行
+		for start < len(lines) && lines[start].op == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+		hunkStart := max(start-diffContextLines, 0)
+		end := start
+		for end < len(lines) {
+			if lines[end].op != ' ' {
+				end++
+				continue
+			}
+			// 连续相同行超过两倍上下文时结束当前块
+			run := end
+			for run < len(lines) && lines[run].op == ' ' {
+				run++
+			}
+			if run == len(lines) || run-end > 2*diffContextLines {
+				end = min(end+diffContextLines, len(lines))
+				break
+			}
+			end = run
+		}
+
+		oldCount, newCount := 0, 0
+		for _, line := range lines[hunkStart:end] {
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			diffRange(lines[hunkStart].old, oldCount), diffRange(lines[hunkStart].new, newCount))
+		for _, line := range lines[hunkStart:end] {
+			sb.WriteByte(line.op)
+			sb.WriteString(line.text)
+			sb.WriteByte('\n')
+		}
+		start = end
+	}
+	return sb.String()
+}
+
+// diffRange 格式化变更块范围（行号从1开始）
+func diffRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitDiffLines 按行拆分，忽略末尾换行
+func splitDiffLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,316 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_migration_test.go
+ * @Description: 配置文件版本迁移测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/cache"
+	"github.com/kamalyes/go-config/pkg/gateway"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const migrationTestYAML = `# 网关配置
+name: gateway
+# 是否启用 TLS
+enable-tls: true # 生产环境必须开启
+cert: /etc/tls.crt
+`
+
+func newTestMigrationRegistry(t *testing.T) *MigrationRegistry {
+	t.Helper()
+	registry := NewMigrationRegistry()
+	require.NoError(t, registry.Register("gateway",
+		Migration{From: "0", To: "1", Description: "TLS 配置归入 tls 节点", Apply: RenameKeys("enable-tls", "tls.enabled", "cert", "tls.cert-file")},
+		Migration{From: "1", To: "2", Description: "新增 tls.min-version", Apply: func(editor *YAMLEditor) error {
+			return editor.Set("tls.min-version", "TLS12")
+		}},
+	))
+	return registry
+}
+
+func writeMigrationFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestMigrationRegistry_Register(t *testing.T) {
+	registry := newTestMigrationRegistry(t)
+
+	err := registry.Register("gateway", Migration{From: "1", To: "3", Apply: RenameKeys()})
+	assert.Error(t, err, "同一起始版本不能重复注册")
+	assert.Error(t, registry.Register("gateway", Migration{From: "5", To: "5", Apply: RenameKeys()}))
+	assert.Error(t, registry.Register("gateway", Migration{From: "5", To: "6"}))
+
+	migrations := registry.Migrations("gateway")
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "0", migrations[0].From)
+	assert.Empty(t, registry.Migrations("unknown"))
+}
+
+func TestMigrationRegistry_Plan(t *testing.T) {
+	registry := newTestMigrationRegistry(t)
+
+	plan, err := registry.Plan("gateway", "0", "")
+	require.NoError(t, err)
+	assert.Len(t, plan, 2)
+
+	plan, err = registry.Plan("gateway", "0", "1")
+	require.NoError(t, err)
+	assert.Len(t, plan, 1)
+
+	plan, err = registry.Plan("gateway", "2", "")
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+
+	_, err = registry.Plan("gateway", "0", "9")
+	assert.Error(t, err)
+
+	require.NoError(t, registry.Register("loop",
+		Migration{From: "a", To: "b", Apply: RenameKeys()},
+		Migration{From: "b", To: "a", Apply: RenameKeys()},
+	))
+	_, err = registry.Plan("loop", "a", "")
+	assert.Error(t, err)
+}
+
+func TestMigrationRegistry_MigrateFile(t *testing.T) {
+	registry := newTestMigrationRegistry(t)
+	path := writeMigrationFile(t, migrationTestYAML)
+
+	result, err := registry.MigrateFile(path, "gateway", MigrateOptions{Backup: true})
+	require.NoError(t, err)
+	assert.Equal(t, "0", result.FromVersion)
+	assert.Equal(t, "2", result.ToVersion)
+	assert.Len(t, result.Applied, 2)
+	assert.True(t, result.Changed())
+	assert.NotEmpty(t, result.BackupPath)
+
+	backup, err := os.ReadFile(result.BackupPath)
+	require.NoError(t, err)
+	assert.Equal(t, migrationTestYAML, string(backup))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# 网关配置
+name: gateway
+tls:
+  # 是否启用 TLS
+  enabled: true # 生产环境必须开启
+  cert-file: /etc/tls.crt
+  min-version: TLS12
+config_version: "2"
+`, string(data))
+
+	// 再次迁移不做任何修改
+	result, err = registry.MigrateFile(path, "gateway", MigrateOptions{})
+	require.NoError(t, err)
+	assert.False(t, result.Changed())
+	assert.Equal(t, "2", result.FromVersion)
+	assert.Empty(t, result.Diff())
+}
+
+func TestMigrationRegistry_DryRun(t *testing.T) {
+	registry := newTestMigrationRegistry(t)
+	path := writeMigrationFile(t, migrationTestYAML)
+
+	result, err := registry.MigrateFile(path, "gateway", MigrateOptions{DryRun: true, TargetVersion: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", result.ToVersion)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, migrationTestYAML, string(data), "dry-run 不应修改文件")
+
+	assert.Equal(t, "--- "+path+"@0\n+++ "+path+`@1
+@@ -1,5 +1,7 @@
+ # 网关配置
+ name: gateway
+-# 是否启用 TLS
+-enable-tls: true # 生产环境必须开启
+-cert: /etc/tls.crt
++tls:
++  # 是否启用 TLS
++  enabled: true # 生产环境必须开启
++  cert-file: /etc/tls.crt
++config_version: "1"
+`, result.Diff())
+}
+
+func TestMigrationRegistry_ApplyError(t *testing.T) {
+	registry := NewMigrationRegistry()
+	require.NoError(t, registry.Register("gateway", Migration{From: "0", To: "1", Apply: func(*YAMLEditor) error {
+		return errors.New("boom")
+	}}))
+	path := writeMigrationFile(t, migrationTestYAML)
+
+	_, err := registry.MigrateFile(path, "gateway", MigrateOptions{})
+	assert.ErrorContains(t, err, "boom")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, migrationTestYAML, string(data))
+}
+
+func TestMigrationRegistry_VersionOrder(t *testing.T) {
+	registry := NewMigrationRegistry()
+	noop := func(*YAMLEditor) error { return nil }
+	require.NoError(t, registry.Register("svc",
+		Migration{From: "10", To: "11", Apply: noop},
+		Migration{From: "9", To: "10", Apply: noop},
+		Migration{From: "2", To: "9", Apply: noop},
+	))
+	var from []string
+	for _, m := range registry.Migrations("svc") {
+		from = append(from, m.From)
+	}
+	assert.Equal(t, []string{"2", "9", "10"}, from)
+
+	assert.Negative(t, compareConfigVersions("1.9", "1.10"))
+	assert.Negative(t, compareConfigVersions("v1.2", "1.2.1"))
+	assert.Zero(t, compareConfigVersions("v2.0", "2.0"))
+	assert.Positive(t, compareConfigVersions("10", "9"))
+}
+
+func TestBuiltinMigrations_GatewayTLS(t *testing.T) {
+	t.Run("生成的网关配置", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join("pkg", "gateway", "gateway.yaml"))
+		require.NoError(t, err)
+		content := strings.Replace(string(data), "    enable-tls: false\n    tls:\n", "    enable-tls: true # 生产环境开启\n    tls:\n", 1)
+		require.NotEqual(t, string(data), content)
+		path := writeMigrationFile(t, content)
+
+		result, err := MigrateConfigFile(path, "gateway", MigrateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "2", result.ToVersion)
+		require.Len(t, result.Applied, 2)
+
+		migrated, err := os.ReadFile(path)
+		require.NoError(t, err)
+		out := string(migrated)
+		for _, comment := range []string{"# HTTP服务器配置（主监听器）", "# 命名监听器列表（支持多端口，如 Ops/Tenant 分离）", "# 缓存配置(包含Redis)"} {
+			assert.Contains(t, out, comment, "注释应保留")
+		}
+		assert.Contains(t, out, "    tls:\n        enabled: true\n        cert-file: \"\"\n")
+		assert.Contains(t, out, "config_version: \"2\"")
+
+		cfg := gateway.Default()
+		require.NoError(t, yaml.Unmarshal(migrated, cfg))
+		assert.False(t, cfg.HTTPServer.EnableTls)
+		assert.True(t, cfg.HTTPServer.TLS.Enabled)
+		assert.True(t, cfg.HTTPServer.TLSEnabled())
+	})
+
+	t.Run("手写配置", func(t *testing.T) {
+		path := writeMigrationFile(t, `http:
+  # 启用 TLS
+  enable-tls: true
+grpc:
+  server:
+    tls: null
+`)
+		_, err := MigrateConfigFile(path, "gateway", MigrateOptions{})
+		require.NoError(t, err)
+		migrated, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, `http:
+  tls:
+    # 启用 TLS
+    enabled: true
+grpc:
+  server:
+    tls: null
+config_version: "2"
+`, string(migrated))
+	})
+}
+
+func TestBuiltinMigrations_CacheTwoLevel(t *testing.T) {
+	path := writeMigrationFile(t, `type: two-level
+two-level:
+  # 本地缓存
+  l1_type: memory
+  l2_type: redis
+  l1-ttl: 1m
+  l1_ttl: 5m # 旧键实际生效
+  l2-size: 100
+`)
+	result, err := MigrateConfigFile(path, "cache", MigrateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1", result.ToVersion)
+
+	migrated, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `type: two-level
+two-level:
+  # 本地缓存
+  l1-type: memory
+  l2-type: redis
+  l1-ttl: 5m # 旧键实际生效
+  l2-size: 100
+config_version: "1"
+`, string(migrated))
+
+	cfg := cache.Default()
+	require.NoError(t, yaml.Unmarshal(migrated, cfg))
+	assert.Equal(t, cache.CacheTypeMemory, cfg.TwoLevel.L1Type)
+	assert.Equal(t, 5*time.Minute, cfg.TwoLevel.L1TTL)
+
+	path = writeMigrationFile(t, "config_version: \"1\"\ncache:\n  two-level:\n    l2_size: 10\n")
+	_, err = MigrateConfigFile(path, "gateway", MigrateOptions{})
+	require.NoError(t, err)
+	migrated, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "config_version: \"2\"\ncache:\n  two-level:\n    l2-size: 10\n", string(migrated))
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Empty(t, unifiedDiff("a\nb\n", "a\nb\n", "x", "y"))
+
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	updated := "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\nELEVEN\n12\n"
+	assert.Equal(t, `--- x
++++ y
+@@ -1,5 +1,5 @@
+ 1
+-2
++TWO
+ 3
+ 4
+ 5
+@@ -8,5 +8,5 @@
+ 8
+ 9
+ 10
+-11
++ELEVEN
+ 12
+`, unifiedDiff(old, updated, "x", "y"))
+
+	assert.Equal(t, "--- x\n+++ y\n@@ -0,0 +1 @@\n+a\n", unifiedDiff("", "a\n", "x", "y"))
+}
+
+func TestValidateConfigFile_IgnoresConfigVersion(t *testing.T) {
+	path := writeValidatorFile(t, "versioned.yaml", "config_version: \"2\"\nservice-name: demo\nport: 80\nlevel: info\n")
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+}
@@ -0,0 +1,120 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_migrations.go
+ * @Description: 内置配置迁移 - 在包初始化时注册到默认迁移注册表
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	for module, migrations := range builtinMigrations() {
+		MustRegisterMigration(module, migrations...)
+	}
+}
+
+// builtinMigrations 内置模块的迁移链，键为模块名称
+func builtinMigrations() map[string][]Migration {
+	return map[string][]Migration{
+		"gateway": {
+			{
+				From:        "0",
+				To:          "1",
+				Description: "enable-tls 归入 tls.enabled",
+				Apply:       mergeEnableKeys("http.enable-tls", "http.tls.enabled"),
+			},
+			{
+				From:        "1",
+				To:          "2",
+				Description: "二级缓存下划线键改为短横线",
+				Apply:       replaceLegacyKeys("cache.two-level", twoLevelLegacyKeys...),
+			},
+		},
+		"cache": {
+			{
+				From:        "0",
+				To:          "1",
+				Description: "二级缓存下划线键改为短横线",
+				Apply:       replaceLegacyKeys("two-level", twoLevelLegacyKeys...),
+			},
+		},
+	}
+}
+
+// twoLevelLegacyKeys 二级缓存旧版 mapstructure 键（l1_type 等）到短横线键的重命名对
+var twoLevelLegacyKeys = []string{
+	"l1_type", "l1-type",
+	"l2_type", "l2-type",
+	"l1_ttl", "l1-ttl",
+	"l2_ttl", "l2-ttl",
+	"l1_size", "l1-size",
+	"l2_size", "l2-size",
+}
+
+// replaceLegacyKeys 在 parent 映射下按原始拼写将旧键重命名为新键（编辑器路径查找不区分 - 与 _，此处需精确匹配）；
+// 两者同时存在时旧键才是实际生效的值，删除新键
+func replaceLegacyKeys(parent string, pairs ...string) func(editor *YAMLEditor) error {
+	return func(editor *YAMLEditor) error {
+		mapping, ok := editor.Get(parent)
+		if !ok || mapping.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(pairs); i += 2 {
+			from := exactMappingIndex(mapping, pairs[i])
+			if from < 0 {
+				continue
+			}
+			if to := exactMappingIndex(mapping, pairs[i+1]); to >= 0 {
+				mapping.Content = append(mapping.Content[:to], mapping.Content[to+2:]...)
+				if to < from {
+					from -= 2
+				}
+			}
+			mapping.Content[from].Value = pairs[i+1]
+		}
+		return nil
+	}
+}
+
+// exactMappingIndex 按原始拼写查找映射键的下标
+func exactMappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeEnableKeys 将布尔开关从旧路径移动到新路径；两者同时存在时保留新路径并按“任一为 true 即启用”合并
+func mergeEnableKeys(pairs ...string) func(editor *YAMLEditor) error {
+	rename := RenameKeys(pairs...)
+	return func(editor *YAMLEditor) error {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			from, to := pairs[i], pairs[i+1]
+			value, ok := editor.GetString(from)
+			if !ok || !editor.Has(to) {
+				continue
+			}
+			if enabled, _ := strconv.ParseBool(value); enabled {
+				if err := editor.Set(to, true); err != nil {
+					return err
+				}
+			}
+			if _, err := editor.Delete(from); err != nil {
+				return err
+			}
+		}
+		return rename(editor)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_module_registry.go
+ * @Description: 全局模块注册表 - 任意包可在 init() 中注册模块，参与配置生成、校验与 Schema 导出
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// moduleRegistry 全局模块注册表: 模块名 -> 模块信息
+var (
+	moduleRegistryMu sync.RWMutex
+	moduleRegistry   = make(map[string]ModuleConfig)
+)
+
+// RegisterModule 注册模块，名称重复时返回错误
+// PackageName 缺省取 Name，OutputSubDir 缺省取 PackageName；Enabled 与 LastGenerated 由生成器维护，注册时忽略
+//
+//	func init() {
+//		goconfig.MustRegisterModule(goconfig.ModuleConfig{
+//			Name:        "billing",
+//			DefaultFunc: func() interface{} { return billing.Default() },
+//			Description: "计费模块",
+//			Team:        "payment",
+//			DependsOn:   []string{"mysql", "redis"},
+//		})
+//	}
+func RegisterModule(module ModuleConfig) error {
+	if module.Name == "" {
+		return ErrModuleNameEmpty
+	}
+	if module.DefaultFunc == nil {
+		return ErrModuleDefaultFuncNil(module.Name)
+	}
+	if module.PackageName == "" {
+		module.PackageName = module.Name
+	}
+	if module.OutputSubDir == "" {
+		module.OutputSubDir = module.PackageName
+	}
+	module.Enabled = false
+	module.LastGenerated = time.Time{}
+	module.Tags = append([]string(nil), module.Tags...)
+	module.DependsOn = append([]string(nil), module.DependsOn...)
+
+	moduleRegistryMu.Lock()
+	defer moduleRegistryMu.Unlock()
+	if _, exists := moduleRegistry[module.Name]; exists {
+		return ErrModuleDuplicate(module.Name)
+	}
+	moduleRegistry[module.Name] = module
+	return nil
+}
+
+// MustRegisterModule 注册模块，失败时 panic，适用于 init()
+func MustRegisterModule(module ModuleConfig) {
+	if err := RegisterModule(module); err != nil {
+		panic(err)
+	}
+}
+
+// UnregisterModule 注销模块，返回模块是否存在
+func UnregisterModule(name string) bool {
+	moduleRegistryMu.Lock()
+	defer moduleRegistryMu.Unlock()
+	if _, exists := moduleRegistry[name]; !exists {
+		return false
+	}
+	delete(moduleRegistry, name)
+	return true
+}
+
+// LookupModule 按名称查找已注册的模块
+func LookupModule(name string) (ModuleConfig, bool) {
+	moduleRegistryMu.RLock()
+	defer moduleRegistryMu.RUnlock()
+	module, exists := moduleRegistry[name]
+	return module, exists
+}
+
+// RegisteredModules 返回所有已注册模块（按名称排序）
+func RegisteredModules() []ModuleConfig {
+	moduleRegistryMu.RLock()
+	modules := make([]ModuleConfig, 0, len(moduleRegistry))
+	for _, module := range moduleRegistry {
+		modules = append(modules, module)
+	}
+	moduleRegistryMu.RUnlock()
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Name < modules[j].Name
+	})
+	return modules
+}
+
+// ModulesByTag 返回带有指定标签的已注册模块（按名称排序）
+func ModulesByTag(tag string) []ModuleConfig {
+	modules := make([]ModuleConfig, 0)
+	for _, module := range RegisteredModules() {
+		for _, t := range module.Tags {
+			if t == tag {
+				modules = append(modules, module)
+				break
+			}
+		}
+	}
+	return modules
+}
+
+// checkModuleDependencies 检查模块依赖是否都已注册且不存在环
+func checkModuleDependencies(modules map[string]ModuleConfig) error {
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, dep := range modules[name].DependsOn {
+			if _, exists := modules[dep]; !exists {
+				return ErrModuleDependencyMissing(name, dep)
+			}
+		}
+	}
+
+	// 0: 未访问, 1: 访问中, 2: 已完成
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int, len(modules))
+	var stack []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			for i, n := range stack {
+				if n == name {
+					return ErrModuleDependencyCycle(append(append([]string{}, stack[i:]...), name))
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range modules[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
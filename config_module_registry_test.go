@@ -0,0 +1,122 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_module_registry_test.go
+ * @Description: 全局模块注册表测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryTestConfig struct {
+	Endpoint string `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint"` // 服务地址
+	Retries  int    `mapstructure:"retries" yaml:"retries" json:"retries"`    // 重试次数
+}
+
+// registerTestModule 注册测试模块并在测试结束时注销
+func registerTestModule(t *testing.T, module ModuleConfig) {
+	t.Helper()
+	if module.DefaultFunc == nil {
+		module.DefaultFunc = func() interface{} { return &registryTestConfig{Endpoint: "http://localhost", Retries: 3} }
+	}
+	require.NoError(t, RegisterModule(module))
+	t.Cleanup(func() { UnregisterModule(module.Name) })
+}
+
+func TestRegisterModule_Defaults(t *testing.T) {
+	registerTestModule(t, ModuleConfig{Name: "test-billing", Team: "payment", Tags: []string{"test-tag"}})
+
+	module, ok := LookupModule("test-billing")
+	require.True(t, ok)
+	assert.Equal(t, "test-billing", module.PackageName)
+	assert.Equal(t, "test-billing", module.OutputSubDir)
+	assert.Equal(t, "payment", module.Team)
+
+	tagged := ModulesByTag("test-tag")
+	require.Len(t, tagged, 1)
+	assert.Equal(t, "test-billing", tagged[0].Name)
+}
+
+func TestRegisterModule_Errors(t *testing.T) {
+	assert.ErrorIs(t, RegisterModule(ModuleConfig{DefaultFunc: func() interface{} { return nil }}), ErrModuleNameEmpty)
+	assert.Error(t, RegisterModule(ModuleConfig{Name: "test-no-default"}))
+
+	// 与内置模块重名
+	err := RegisterModule(ModuleConfig{Name: "redis", DefaultFunc: func() interface{} { return nil }})
+	assert.EqualError(t, err, ErrModuleDuplicate("redis").Error())
+
+	registerTestModule(t, ModuleConfig{Name: "test-dup"})
+	assert.Error(t, RegisterModule(ModuleConfig{Name: "test-dup", DefaultFunc: func() interface{} { return nil }}))
+	assert.Panics(t, func() {
+		MustRegisterModule(ModuleConfig{Name: "test-dup", DefaultFunc: func() interface{} { return nil }})
+	})
+
+	assert.False(t, UnregisterModule("test-not-registered"))
+}
+
+func TestRegisteredModules_IncludesBuiltins(t *testing.T) {
+	modules := RegisteredModules()
+	assert.Len(t, modules, len(builtinModules()))
+	for i := 1; i < len(modules); i++ {
+		assert.Less(t, modules[i-1].Name, modules[i].Name, "应按名称排序")
+	}
+}
+
+func TestSmartConfigGenerator_UsesRegistry(t *testing.T) {
+	registerTestModule(t, ModuleConfig{Name: "test-inhouse", Description: "内部模块", DependsOn: []string{"redis"}})
+
+	tempDir := t.TempDir()
+	generator := NewSmartConfigGenerator(tempDir).WithBackupExisting(false)
+	assert.Contains(t, generator.GetModuleList(), "test-inhouse")
+	assert.Contains(t, generator.GetEnabledModules(), "test-inhouse")
+
+	// 只启用自定义模块时，依赖的模块一并启用
+	require.NoError(t, generator.EnableOnlyModules("test-inhouse"))
+	assert.Equal(t, []string{"redis", "test-inhouse"}, generator.GetEnabledModules())
+
+	require.NoError(t, generator.GenerateModulesByNames("test-inhouse"))
+	assert.FileExists(t, filepath.Join(tempDir, "pkg", "test-inhouse", "test-inhouse.yaml"))
+
+	require.NoError(t, generator.GenerateAllSchemas())
+	assert.FileExists(t, filepath.Join(tempDir, "pkg", "test-inhouse", "test-inhouse.schema.json"))
+	assert.FileExists(t, filepath.Join(tempDir, "pkg", "redis", "redis.schema.json"))
+
+	require.NoError(t, generator.ValidateAllModules())
+}
+
+func TestSmartConfigGenerator_ValidateDependencies(t *testing.T) {
+	registerTestModule(t, ModuleConfig{Name: "test-missing-dep", DependsOn: []string{"test-nowhere"}})
+	generator := NewSmartConfigGenerator(t.TempDir())
+	assert.Error(t, generator.ValidateModuleConfig("test-missing-dep"))
+	assert.Error(t, generator.ValidateAllModules())
+}
+
+func TestCheckModuleDependencies_Cycle(t *testing.T) {
+	modules := map[string]ModuleConfig{
+		"a": {Name: "a", DependsOn: []string{"b"}},
+		"b": {Name: "b", DependsOn: []string{"c"}},
+		"c": {Name: "c", DependsOn: []string{"a"}},
+		"d": {Name: "d"},
+	}
+	err := checkModuleDependencies(modules)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a -> b -> c -> a")
+
+	delete(modules, "c")
+	modules["b"] = ModuleConfig{Name: "b", DependsOn: []string{"d"}}
+	assert.NoError(t, checkModuleDependencies(modules))
+
+	modules["b"] = ModuleConfig{Name: "b", DependsOn: []string{"x"}}
+	assert.EqualError(t, checkModuleDependencies(modules), ErrModuleDependencyMissing("b", "x").Error())
+}
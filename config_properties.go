@@ -0,0 +1,407 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_properties.go
+ * @Description: Java 风格 .properties 编解码 - 点分键表示层级，[i] 表示列表下标
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// PropertiesCodec viper 的 .properties 编解码器
+// viper 自 v1.20 起不再内置 properties 支持，此处补齐以便 DefaultSupportedExtensions 中的格式都能加载
+//
+//	server.port=8080
+//	server.hosts[0]=a
+//	labels.app\.name=demo
+type PropertiesCodec struct{}
+
+// Encode 将配置映射编码为 properties（键按字母排序）
+func (PropertiesCodec) Encode(v map[string]any) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, ErrEncodeYAMLDocument(err)
+	}
+	var sb strings.Builder
+	writePropertiesNode(&sb, "", &node)
+	return []byte(sb.String()), nil
+}
+
+// Decode 解析 properties 内容并还原为嵌套映射
+func (PropertiesCodec) Decode(b []byte, v map[string]any) error {
+	for _, entry := range parsePropertiesEntries(string(b)) {
+		segments, err := splitPropertiesKey(entry.key)
+		if err != nil {
+			return ErrParseProperties(entry.line, err)
+		}
+		if err := setPropertiesValue(v, segments, entry.value); err != nil {
+			return ErrParseProperties(entry.line, err)
+		}
+	}
+	finalizePropertiesLists(v)
+	return nil
+}
+
+// newConfigCodecRegistry 创建包含 properties 编解码器的注册表，其余格式沿用 viper 内置实现
+func newConfigCodecRegistry() *viper.DefaultCodecRegistry {
+	registry := viper.NewCodecRegistry()
+	for _, format := range []string{"properties", "props", "prop"} {
+		_ = registry.RegisterCodec(format, PropertiesCodec{})
+	}
+	return registry
+}
+
+// newViper 创建支持 DefaultSupportedExtensions 全部格式的 viper 实例
+func newViper() *viper.Viper {
+	return viper.NewWithOptions(viper.WithCodecRegistry(newConfigCodecRegistry()))
+}
+
+// writePropertiesNode 按节点顺序展开为 key=value 行，null 与空集合不输出
+func writePropertiesNode(sb *strings.Builder, prefix string, node *yaml.Node) {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			writePropertiesNode(sb, prefix, child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := escapePropertiesSegment(node.Content[i].Value)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			writePropertiesNode(sb, key, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			writePropertiesNode(sb, prefix+"["+strconv.Itoa(i)+"]", item)
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || prefix == "" {
+			return
+		}
+		sb.WriteString(escapePropertiesKey(prefix))
+		sb.WriteByte('=')
+		sb.WriteString(escapePropertiesValue(node.Value))
+		sb.WriteByte('\n')
+	}
+}
+
+// escapePropertiesSegment 转义键段中的层级分隔符
+func escapePropertiesSegment(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ".", `\.`, "[", `\[`, "]", `\]`).Replace(s)
+}
+
+// escapePropertiesKey 转义键中的空白与分隔符（= : # !）
+func escapePropertiesKey(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == ' ' || r == '=' || r == ':':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case (r == '#' || r == '!') && i == 0:
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		default:
+			writePropertiesRune(&sb, r)
+		}
+	}
+	return sb.String()
+}
+
+// escapePropertiesValue 转义值中的反斜杠、前导空白与控制字符
+func escapePropertiesValue(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == ' ' && i == 0:
+			sb.WriteString(`\ `)
+		default:
+			writePropertiesRune(&sb, r)
+		}
+	}
+	return sb.String()
+}
+
+// writePropertiesRune 写入字符，控制字符使用转义序列
+func writePropertiesRune(sb *strings.Builder, r rune) {
+	switch r {
+	case '\t':
+		sb.WriteString(`\t`)
+	case '\n':
+		sb.WriteString(`\n`)
+	case '\r':
+		sb.WriteString(`\r`)
+	case '\f':
+		sb.WriteString(`\f`)
+	default:
+		if unicode.IsControl(r) {
+			fmt.Fprintf(sb, `\u%04x`, r)
+			return
+		}
+		sb.WriteRune(r)
+	}
+}
+
+// propertiesEntry 解析出的键值对（键中的层级转义保留到拆分阶段处理）
+type propertiesEntry struct {
+	key   string
+	value string
+	line  int
+}
+
+// parsePropertiesEntries 按 java.util.Properties 规则解析逻辑行
+func parsePropertiesEntries(content string) []propertiesEntry {
+	entries := make([]propertiesEntry, 0)
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// 续行: 行尾奇数个反斜杠
+		for endsWithContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		keyEnd, valueStart := splitPropertiesLine(line)
+		entries = append(entries, propertiesEntry{
+			key:   line[:keyEnd],
+			value: unescapeProperties(line[valueStart:], ""),
+			line:  lineNo,
+		})
+	}
+	return entries
+}
+
+// endsWithContinuation 判断行尾是否为未转义的反斜杠
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// splitPropertiesLine 返回键结束位置与值开始位置
+func splitPropertiesLine(line string) (int, int) {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '=' || line[i] == ':' || line[i] == ' ' || line[i] == '\t' || line[i] == '\f' {
+			keyEnd = i
+			break
+		}
+	}
+
+	valueStart := keyEnd
+	for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t' || line[valueStart] == '\f') {
+		valueStart++
+	}
+	if valueStart < len(line) && (line[valueStart] == '=' || line[valueStart] == ':') {
+		valueStart++
+		for valueStart < len(line) && (line[valueStart] == ' ' || line[valueStart] == '\t' || line[valueStart] == '\f') {
+			valueStart++
+		}
+	}
+	return keyEnd, valueStart
+}
+
+// unescapeProperties 还原转义序列，keep 中的字符保持转义形式（用于保留键中的层级转义）
+func unescapeProperties(s, keep string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; {
+		case strings.IndexByte(keep, c) >= 0:
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c == 't':
+			sb.WriteByte('\t')
+		case c == 'n':
+			sb.WriteByte('\n')
+		case c == 'r':
+			sb.WriteByte('\r')
+		case c == 'f':
+			sb.WriteByte('\f')
+		case c == 'u' && i+4 < len(s):
+			if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+				sb.WriteRune(rune(r))
+				i += 4
+				continue
+			}
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// propertiesSegment 键路径中的一段: 映射键或列表下标
+type propertiesSegment struct {
+	name  string
+	index int // 列表下标，name 非空时无效
+}
+
+// splitPropertiesKey 拆分 a.b[0].c 形式的键，支持 \. \[ \] 转义
+func splitPropertiesKey(key string) ([]propertiesSegment, error) {
+	key = unescapeProperties(key, `.[]\`)
+	segments := make([]propertiesSegment, 0)
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, propertiesSegment{name: current.String()})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; c {
+		case '\\':
+			if i+1 < len(key) {
+				i++
+				current.WriteByte(key[i])
+			}
+		case '.':
+			flush()
+		case '[':
+			flush()
+			closeIdx := strings.IndexByte(key[i:], ']')
+			if closeIdx < 0 {
+				return nil, fmt.Errorf("键 '%s' 缺少 ']'", key)
+			}
+			idx, err := strconv.Atoi(key[i+1 : i+closeIdx])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("键 '%s' 的列表下标无效", key)
+			}
+			segments = append(segments, propertiesSegment{index: idx})
+			i += closeIdx
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	if len(segments) == 0 || segments[0].name == "" {
+		return nil, fmt.Errorf("键 '%s' 无效", key)
+	}
+	return segments, nil
+}
+
+// propertiesList 解析期间的稀疏列表，解析完成后转换为 []any
+type propertiesList map[int]any
+
+// setPropertiesValue 按路径写入值，自动创建中间映射与列表
+func setPropertiesValue(root map[string]any, segments []propertiesSegment, value string) error {
+	var container any = root
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		var next any
+		if !last {
+			if segments[i+1].name != "" {
+				next = map[string]any{}
+			} else {
+				next = propertiesList{}
+			}
+		}
+
+		switch c := container.(type) {
+		case map[string]any:
+			if seg.name == "" {
+				return fmt.Errorf("下标 [%d] 的父级不是列表", seg.index)
+			}
+			if last {
+				c[seg.name] = value
+				return nil
+			}
+			existing, ok := c[seg.name]
+			if !ok || !sameContainerKind(existing, next) {
+				c[seg.name] = next
+				existing = next
+			}
+			container = existing
+		case propertiesList:
+			if seg.name != "" {
+				return fmt.Errorf("'%s' 的父级是列表", seg.name)
+			}
+			if last {
+				c[seg.index] = value
+				return nil
+			}
+			existing, ok := c[seg.index]
+			if !ok || !sameContainerKind(existing, next) {
+				c[seg.index] = next
+				existing = next
+			}
+			container = existing
+		}
+	}
+	return nil
+}
+
+// sameContainerKind 判断已有节点是否与需要的容器类型一致
+func sameContainerKind(existing, want any) bool {
+	switch existing.(type) {
+	case map[string]any:
+		_, ok := want.(map[string]any)
+		return ok
+	case propertiesList:
+		_, ok := want.(propertiesList)
+		return ok
+	}
+	return false
+}
+
+// finalizePropertiesLists 将稀疏列表按下标顺序转换为 []any
+func finalizePropertiesLists(m map[string]any) {
+	for key, value := range m {
+		m[key] = finalizePropertiesValue(value)
+	}
+}
+
+func finalizePropertiesValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		finalizePropertiesLists(v)
+		return v
+	case propertiesList:
+		indexes := make([]int, 0, len(v))
+		for idx := range v {
+			indexes = append(indexes, idx)
+		}
+		sort.Ints(indexes)
+		items := make([]any, 0, len(indexes))
+		for _, idx := range indexes {
+			items = append(items, finalizePropertiesValue(v[idx]))
+		}
+		return items
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_properties_test.go
+ * @Description: properties 编解码器与 TOML 输出测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPropertiesCodec_Decode(t *testing.T) {
+	content := `# 注释行
+! 另一种注释
+server.host = 0.0.0.0
+server.port: 8080
+server.hosts[0]=a
+server.hosts[1]=b
+server.routes[0].path=/api
+server.routes[0].methods[0]=GET
+labels.app\.name=demo
+message=hello \
+    world
+path=C:\\data
+`
+	result := map[string]any{}
+	require.NoError(t, PropertiesCodec{}.Decode([]byte(content), result))
+
+	server := result["server"].(map[string]any)
+	assert.Equal(t, "0.0.0.0", server["host"])
+	assert.Equal(t, "8080", server["port"])
+	assert.Equal(t, []any{"a", "b"}, server["hosts"])
+	routes := server["routes"].([]any)
+	require.Len(t, routes, 1)
+	assert.Equal(t, "/api", routes[0].(map[string]any)["path"])
+	assert.Equal(t, []any{"GET"}, routes[0].(map[string]any)["methods"])
+	assert.Equal(t, "demo", result["labels"].(map[string]any)["app.name"])
+	assert.Equal(t, "hello world", result["message"])
+	assert.Equal(t, `C:\data`, result["path"])
+}
+
+func TestPropertiesCodec_DecodeOverrideAndInvalid(t *testing.T) {
+	// 与 Java Properties 一致，后出现的键覆盖先前的值
+	result := map[string]any{}
+	require.NoError(t, PropertiesCodec{}.Decode([]byte("a=1\na.b=2\n"), result))
+	assert.Equal(t, map[string]any{"b": "2"}, result["a"])
+
+	err := PropertiesCodec{}.Decode([]byte("a[x]=1\n"), map[string]any{})
+	assert.Error(t, err, "非法下标应报错")
+}
+
+func TestPropertiesCodec_RoundTrip(t *testing.T) {
+	source := map[string]any{
+		"name":   "demo service",
+		"labels": map[string]any{"app.name": "x=y", "empty": ""},
+		"hosts":  []any{"a", "b"},
+		"nested": map[string]any{"items": []any{map[string]any{"id": 1, "tags": []any{"t1"}}}},
+		"note":   "line1\nline2",
+		"none":   nil,
+	}
+
+	data, err := PropertiesCodec{}.Encode(source)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "none")
+
+	decoded := map[string]any{}
+	require.NoError(t, PropertiesCodec{}.Decode(data, decoded))
+	assert.Equal(t, "demo service", decoded["name"])
+	assert.Equal(t, map[string]any{"app.name": "x=y", "empty": ""}, decoded["labels"])
+	assert.Equal(t, []any{"a", "b"}, decoded["hosts"])
+	assert.Equal(t, "line1\nline2", decoded["note"])
+	items := decoded["nested"].(map[string]any)["items"].([]any)
+	assert.Equal(t, "1", items[0].(map[string]any)["id"])
+	assert.Equal(t, []any{"t1"}, items[0].(map[string]any)["tags"])
+}
+
+func TestNewViper_LoadsProperties(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.properties")
+	require.NoError(t, os.WriteFile(path, []byte("server.port=9090\nserver.enable-tls=true\n"), 0644))
+
+	v := newViper()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig())
+	assert.Equal(t, 9090, v.GetInt("server.port"))
+	assert.True(t, v.GetBool("server.enable-tls"))
+}
+
+func TestWriteTOMLDocument(t *testing.T) {
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`name: demo
+port: 8080
+ratio: .inf
+none: null
+tags: [a, b]
+server:
+  host: "0.0.0.0"
+  tls:
+    enabled: true
+routes:
+  - path: /api
+    weight: 1
+  - path: /admin
+"key with space": "q\"uote"
+`), &node))
+
+	expected := `name = "demo"
+port = 8080
+ratio = inf
+tags = ["a", "b"]
+"key with space" = "q\"uote"
+
+[server]
+host = "0.0.0.0"
+
+[server.tls]
+enabled = true
+
+[[routes]]
+path = "/api"
+weight = 1
+
+[[routes]]
+path = "/admin"
+`
+	assert.Equal(t, expected, writeTOMLDocument(&node))
+
+	v := newViper()
+	v.SetConfigType("toml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(expected)))
+	assert.Equal(t, "0.0.0.0", v.GetString("server.host"))
+	assert.Len(t, v.Get("routes"), 2)
+}
@@ -0,0 +1,264 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_roundtrip.go
+ * @Description: 配置往返校验 - 生成配置文件后通过管理器加载，与 Default() 比较以发现格式漂移
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RoundTripFormats 往返校验默认覆盖的格式
+// JSON 模板带 // 注释头且键名沿用 json 标签（camelCase），仅作参考，不在默认校验范围内
+var RoundTripFormats = []string{"yaml", "toml", "properties"}
+
+// RoundTripResult 单个模块在某种格式下的往返校验结果
+type RoundTripResult struct {
+	Module     string   // 模块名称
+	Format     string   // 配置格式
+	Mismatches []string // 不一致的字段（路径: 期望值与实际值）
+	Err        error    // 生成或加载失败时的错误
+}
+
+// OK 是否完全一致
+func (r RoundTripResult) OK() bool {
+	return r.Err == nil && len(r.Mismatches) == 0
+}
+
+// String 格式化输出
+func (r RoundTripResult) String() string {
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("%s(%s): %v", r.Module, r.Format, r.Err)
+	case len(r.Mismatches) > 0:
+		return fmt.Sprintf("%s(%s): %s", r.Module, r.Format, strings.Join(r.Mismatches, "; "))
+	default:
+		return fmt.Sprintf("%s(%s): ok", r.Module, r.Format)
+	}
+}
+
+// VerifyRoundTrip 对单个模块执行往返校验: 渲染 Default() → 写入临时文件 → 通过集成管理器加载 → 与 Default() 比较
+func (sg *SmartConfigGenerator) VerifyRoundTrip(moduleName, format string) RoundTripResult {
+	result := RoundTripResult{Module: moduleName, Format: format}
+
+	module, exists := sg.ModuleRegistry[moduleName]
+	if !exists {
+		result.Err = ErrModuleNotFound(moduleName)
+		return result
+	}
+	if module.DefaultFunc == nil {
+		result.Err = ErrModuleDefaultFuncNil(moduleName)
+		return result
+	}
+
+	expected := module.DefaultFunc()
+	if expected == nil {
+		result.Err = ErrModuleConfigEmpty(moduleName)
+		return result
+	}
+
+	content, err := sg.renderConfig(expected, module, format)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	dir, err := os.MkdirTemp("", "go-config-roundtrip-*")
+	if err != nil {
+		result.Err = ErrCreateOutputDir(err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, module.Name+"."+format)
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		result.Err = ErrWriteConfigOutput(format, err)
+		return result
+	}
+
+	expectedValue := reflect.Indirect(reflect.ValueOf(expected))
+	actual := reflect.New(expectedValue.Type())
+	manager, err := NewIntegratedConfigManager(actual.Interface(), &IntegratedConfigOptions{ConfigPath: filePath})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	// 启动后立即停止，以释放热更新器持有的文件监控器与环境监控协程
+	if err := manager.Start(context.Background()); err != nil {
+		result.Err = err
+		return result
+	}
+	if err := manager.Stop(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	diffConfigValues(nil, expectedValue, actual.Elem(), &result.Mismatches)
+	return result
+}
+
+// VerifyAllRoundTrips 对所有启用的模块执行往返校验，formats 为空时使用 RoundTripFormats
+func (sg *SmartConfigGenerator) VerifyAllRoundTrips(formats ...string) ([]RoundTripResult, error) {
+	if len(formats) == 0 {
+		formats = RoundTripFormats
+	}
+
+	results := make([]RoundTripResult, 0, len(sg.ModuleRegistry)*len(formats))
+	failed := make([]string, 0)
+	for _, name := range sg.GetEnabledModules() {
+		for _, format := range formats {
+			result := sg.VerifyRoundTrip(name, format)
+			if !result.OK() {
+				failed = append(failed, name+"("+format+")")
+				sg.Logger.WarnKV("配置往返校验失败", "module", name, "format", format, "detail", result.String())
+			}
+			results = append(results, result)
+		}
+	}
+
+	if len(failed) > 0 {
+		return results, ErrRoundTripFailed(failed)
+	}
+	return results, nil
+}
+
+// renderConfig 按格式渲染配置内容
+func (sg *SmartConfigGenerator) renderConfig(config interface{}, module ModuleConfig, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return sg.renderYAML(config, module)
+	case "json":
+		return sg.renderJSON(config, module)
+	case "toml":
+		return sg.renderTOML(config, module)
+	case "properties":
+		return sg.renderProperties(config, module)
+	default:
+		return "", ErrUnsupportedConfigFormat(format)
+	}
+}
+
+// diffConfigValues 递归比较期望值与实际值，记录不一致的字段路径
+// 格式无法区分的情况视为一致: nil 与空集合、nil 指针与指向零值的指针
+func diffConfigValues(path []string, expected, actual reflect.Value, out *[]string) {
+	if isEmptyConfigValue(expected) && isEmptyConfigValue(actual) {
+		return
+	}
+	if !expected.IsValid() || !actual.IsValid() {
+		*out = append(*out, fmt.Sprintf("%s: 期望 %v, 实际 %v", formatConfigPath(path), describeValue(expected), describeValue(actual)))
+		return
+	}
+
+	switch expected.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if expected.Kind() == reflect.Interface || actual.Kind() == reflect.Interface {
+			// 动态类型的值（如 map[string]any）在各格式中类型不同，按字面量比较
+			if fmt.Sprint(describeValue(expected)) != fmt.Sprint(describeValue(actual)) {
+				*out = append(*out, fmt.Sprintf("%s: 期望 %v, 实际 %v", formatConfigPath(path), describeValue(expected), describeValue(actual)))
+			}
+			return
+		}
+		diffConfigValues(path, expected.Elem(), actual.Elem(), out)
+	case reflect.Struct:
+		if expected.Type() == timeType {
+			if !expected.Interface().(time.Time).Equal(actual.Interface().(time.Time)) {
+				*out = append(*out, fmt.Sprintf("%s: 期望 %v, 实际 %v", formatConfigPath(path), expected, actual))
+			}
+			return
+		}
+		t := expected.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("yaml") == "-" {
+				continue
+			}
+			name, inline, skip := schemaFieldName(field)
+			if skip {
+				continue
+			}
+			fieldPath := path
+			if !inline {
+				fieldPath = appendPath(path, name)
+			}
+			diffConfigValues(fieldPath, expected.Field(i), actual.Field(i), out)
+		}
+	case reflect.Slice, reflect.Array:
+		if expected.Len() != actual.Len() {
+			*out = append(*out, fmt.Sprintf("%s: 期望 %d 个元素, 实际 %d 个", formatConfigPath(path), expected.Len(), actual.Len()))
+			return
+		}
+		for i := 0; i < expected.Len(); i++ {
+			diffConfigValues(appendPath(path, strconv.Itoa(i)), expected.Index(i), actual.Index(i), out)
+		}
+	case reflect.Map:
+		keys := expected.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			diffConfigValues(appendPath(path, fmt.Sprint(key)), expected.MapIndex(key), actual.MapIndex(key), out)
+		}
+		for _, key := range actual.MapKeys() {
+			if !expected.MapIndex(key).IsValid() {
+				*out = append(*out, fmt.Sprintf("%s: 多出的键", formatConfigPath(appendPath(path, fmt.Sprint(key)))))
+			}
+		}
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return
+	default:
+		if !reflect.DeepEqual(expected.Interface(), actual.Interface()) {
+			*out = append(*out, fmt.Sprintf("%s: 期望 %v, 实际 %v", formatConfigPath(path), expected, actual))
+		}
+	}
+}
+
+// isEmptyConfigValue 判断值在配置文件中是否等同于缺省（nil、空集合、指向零值的指针）
+func isEmptyConfigValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil() || isEmptyConfigValue(v.Elem()) && v.Elem().Kind() == reflect.Struct
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return v.IsZero()
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); v.Type().Field(i).IsExported() && !isEmptyConfigValue(field) && !field.IsZero() {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// describeValue 解引用后用于输出的值
+func describeValue(v reflect.Value) any {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
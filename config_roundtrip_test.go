@@ -0,0 +1,109 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_roundtrip_test.go
+ * @Description: TOML/properties 生成与配置往返校验测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmartConfigGenerator_GenerateTOMLAndProperties(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewSmartConfigGenerator(tempDir).
+		WithBackupExisting(false).
+		WithGenerateTOML(true).
+		WithGenerateProperties(true)
+	require.NoError(t, generator.EnableOnlyModules("ratelimit"))
+	require.NoError(t, generator.GenerateAllConfigs())
+
+	moduleDir := filepath.Join(tempDir, "pkg", "ratelimit")
+	tomlData, err := os.ReadFile(filepath.Join(moduleDir, "ratelimit.toml"))
+	require.NoError(t, err)
+	propsData, err := os.ReadFile(filepath.Join(moduleDir, "ratelimit.properties"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(tomlData), "[global-limit]")
+	assert.Contains(t, string(tomlData), "burst-size = ")
+	assert.Contains(t, string(tomlData), "# ", "应包含字段注释")
+	assert.Contains(t, string(propsData), "global-limit.burst-size=")
+	assert.Contains(t, string(propsData), "# ", "应包含字段注释")
+}
+
+func TestSmartConfigGenerator_DefaultSkipsTOMLAndProperties(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewSmartConfigGenerator(tempDir).WithBackupExisting(false)
+	require.NoError(t, generator.EnableOnlyModules("ratelimit"))
+	require.NoError(t, generator.GenerateAllConfigs())
+
+	moduleDir := filepath.Join(tempDir, "pkg", "ratelimit")
+	assert.NoFileExists(t, filepath.Join(moduleDir, "ratelimit.toml"))
+	assert.NoFileExists(t, filepath.Join(moduleDir, "ratelimit.properties"))
+}
+
+func TestSmartConfigGenerator_VerifyRoundTrip(t *testing.T) {
+	generator := NewSmartConfigGenerator(t.TempDir())
+
+	result := generator.VerifyRoundTrip("ratelimit", "toml")
+	assert.True(t, result.OK(), result.String())
+	assert.Equal(t, "ratelimit(toml): ok", result.String())
+
+	result = generator.VerifyRoundTrip("no-such-module", "yaml")
+	assert.False(t, result.OK())
+
+	result = generator.VerifyRoundTrip("ratelimit", "ini")
+	assert.Error(t, result.Err)
+}
+
+func TestSmartConfigGenerator_VerifyAllRoundTrips(t *testing.T) {
+	generator := NewSmartConfigGenerator(t.TempDir())
+
+	results, err := generator.VerifyAllRoundTrips()
+	for _, result := range results {
+		assert.True(t, result.OK(), result.String())
+	}
+	require.NoError(t, err)
+	assert.Len(t, results, len(generator.GetEnabledModules())*len(RoundTripFormats))
+}
+
+func TestDiffConfigValues(t *testing.T) {
+	type inner struct {
+		Values []string `yaml:"values"`
+	}
+	type sample struct {
+		Name  string         `yaml:"name"`
+		Inner *inner         `yaml:"inner"`
+		Tags  map[string]int `yaml:"tags"`
+	}
+
+	var mismatches []string
+	diffConfigValues(nil,
+		reflect.ValueOf(sample{Name: "a", Inner: &inner{}, Tags: map[string]int{"x": 1}}),
+		reflect.ValueOf(sample{Name: "a", Tags: map[string]int{"x": 1}}),
+		&mismatches)
+	assert.Empty(t, mismatches, "空结构体指针与 nil 等价")
+
+	diffConfigValues(nil,
+		reflect.ValueOf(sample{Name: "a", Tags: map[string]int{"x": 1}}),
+		reflect.ValueOf(sample{Name: "b", Tags: map[string]int{"x": 2, "y": 3}}),
+		&mismatches)
+	assert.Len(t, mismatches, 3)
+}
+
+func TestErrRoundTripFailed(t *testing.T) {
+	err := ErrRoundTripFailed([]string{"a(toml)", "b(yaml)"})
+	assert.Contains(t, err.Error(), "a(toml)")
+}
@@ -0,0 +1,724 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_schema.go
+ * @Description: JSON Schema (draft 2020-12) 导出 - 基于结构体标签和源码注释生成配置文件的机器可读模式
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/cache"
+	"github.com/kamalyes/go-config/pkg/common"
+	"github.com/kamalyes/go-config/pkg/database"
+	"github.com/kamalyes/go-config/pkg/gateway"
+	"github.com/kamalyes/go-config/pkg/i18n"
+	"github.com/kamalyes/go-config/pkg/oss"
+	"github.com/kamalyes/go-config/pkg/ratelimit"
+	"github.com/kamalyes/go-config/pkg/signature"
+	"github.com/kamalyes/go-config/pkg/swagger"
+	"github.com/kamalyes/go-config/pkg/wsc"
+)
+
+// JSONSchemaDraft JSON Schema 版本标识
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// durationPattern 时间间隔字符串格式（time.ParseDuration 可解析的格式）
+const durationPattern = `^-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$|^0$`
+
+// JSONSchema JSON Schema 节点
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`              // 模式版本
+	ID                   string                 `json:"$id,omitempty"`                  // 模式标识
+	Ref                  string                 `json:"$ref,omitempty"`                 // 引用
+	Title                string                 `json:"title,omitempty"`                // 标题
+	Description          string                 `json:"description,omitempty"`          // 描述
+	Type                 any                    `json:"type,omitempty"`                 // 类型: string 或 []string
+	Format               string                 `json:"format,omitempty"`               // 格式: uri, email, date-time...
+	Pattern              string                 `json:"pattern,omitempty"`              // 正则约束
+	Enum                 []any                  `json:"enum,omitempty"`                 // 枚举值
+	Default              any                    `json:"default,omitempty"`              // 默认值
+	Minimum              *float64               `json:"minimum,omitempty"`              // 最小值
+	Maximum              *float64               `json:"maximum,omitempty"`              // 最大值
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum,omitempty"`     // 开区间最小值
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum,omitempty"`     // 开区间最大值
+	MinLength            *int                   `json:"minLength,omitempty"`            // 最小长度
+	MaxLength            *int                   `json:"maxLength,omitempty"`            // 最大长度
+	MinItems             *int                   `json:"minItems,omitempty"`             // 最少元素
+	MaxItems             *int                   `json:"maxItems,omitempty"`             // 最多元素
+	Items                *JSONSchema            `json:"items,omitempty"`                // 数组元素模式
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`           // 对象属性
+	Required             []string               `json:"required,omitempty"`             // 必填属性
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"` // 映射值模式
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`                // 定义（用于递归类型）
+}
+
+// ToJSON 导出为格式化的 JSON
+func (s *JSONSchema) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// schemaEnums 枚举类型注册表: 类型 -> 可选值
+var (
+	schemaEnumsMu sync.RWMutex
+	schemaEnums   = make(map[reflect.Type][]any)
+)
+
+// RegisterSchemaEnum 注册枚举类型的可选值，导出模式时该类型会生成 enum 列表
+// 用法: RegisterSchemaEnum(ratelimit.StrategyTokenBucket, ratelimit.StrategyLeakyBucket)
+func RegisterSchemaEnum[T any](values ...T) {
+	if len(values) == 0 {
+		return
+	}
+	enum := make([]any, 0, len(values))
+	for _, v := range values {
+		enum = append(enum, v)
+	}
+	schemaEnumsMu.Lock()
+	defer schemaEnumsMu.Unlock()
+	schemaEnums[reflect.TypeOf(values[0])] = enum
+}
+
+// lookupSchemaEnum 查找类型的枚举值（以底层基础类型输出）
+func lookupSchemaEnum(t reflect.Type) []any {
+	schemaEnumsMu.RLock()
+	values, ok := schemaEnums[t]
+	schemaEnumsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	result := make([]any, 0, len(values))
+	for _, v := range values {
+		result = append(result, plainValue(reflect.ValueOf(v)))
+	}
+	return result
+}
+
+func init() {
+	RegisterSchemaEnum(ratelimit.StrategyTokenBucket, ratelimit.StrategyLeakyBucket, ratelimit.StrategySlidingWindow, ratelimit.StrategyFixedWindow)
+	RegisterSchemaEnum(ratelimit.ScopeGlobal, ratelimit.ScopePerIP, ratelimit.ScopePerUser, ratelimit.ScopePerRoute)
+	RegisterSchemaEnum(database.DBTypeMySQL, database.DBTypePostgreSQL, database.DBTypeSQLite, database.DBTypeCockroachDB)
+	RegisterSchemaEnum(gateway.TLSVersion10, gateway.TLSVersion11, gateway.TLSVersion12, gateway.TLSVersion13)
+	RegisterSchemaEnum(gateway.NoClientCert, gateway.RequestClientCert, gateway.RequireAnyClientCert, gateway.VerifyClientCertIfGiven, gateway.RequireAndVerifyClientCert)
+	RegisterSchemaEnum(gateway.GRPCCompressGzip, gateway.GRPCCompressSnappy, gateway.GRPCCompressZstd)
+	RegisterSchemaEnum(cache.CacheTypeMemory, cache.CacheTypeRistretto, cache.CacheTypeRedis, cache.CacheTypeSharded, cache.CacheTypeTwoLevel, cache.CacheTypeExpiring)
+	RegisterSchemaEnum(common.SourceTypeQuery, common.SourceTypeHeader, common.SourceTypeCookie)
+	RegisterSchemaEnum(oss.OSSTypeMinio, oss.OSSTypeS3, oss.OSSTypeAliyunOSS, oss.OSSTypeBoltDB)
+	RegisterSchemaEnum(signature.SignatureTypeHMAC, signature.SignatureTypeRSA)
+	RegisterSchemaEnum(swagger.AuthNone, swagger.AuthBasic, swagger.AuthBearer, swagger.AuthCustom)
+	RegisterSchemaEnum(i18n.LegacyMapping, i18n.StandardMapping)
+	RegisterSchemaEnum(i18n.DetectionHeader, i18n.DetectionQuery, i18n.DetectionCookie, i18n.DetectionDefault)
+	RegisterSchemaEnum(wsc.GranularityHour, wsc.GranularityDay, wsc.GranularityMonth, wsc.GranularityYear)
+}
+
+// SchemaGenerator JSON Schema 生成器
+type SchemaGenerator struct {
+	SourceRoot string // 源码根目录，用于从 SourceRoot/pkg/<包名> 提取字段注释
+
+	mu           sync.Mutex
+	commentCache map[string]map[string]*structComments // 注释缓存: 包目录 -> 结构体名 -> 注释
+}
+
+// structComments 结构体注释信息
+type structComments struct {
+	Doc      string            // 结构体文档注释
+	Fields   map[string]string // 字段名 -> 注释（行尾注释优先，其次文档注释）
+	Trailing map[string]string // 字段名 -> 行尾注释
+}
+
+// NewSchemaGenerator 创建 JSON Schema 生成器
+func NewSchemaGenerator(sourceRoot string) *SchemaGenerator {
+	return &SchemaGenerator{
+		SourceRoot:   sourceRoot,
+		commentCache: make(map[string]map[string]*structComments),
+	}
+}
+
+var (
+	defaultSchemaGenerator     *SchemaGenerator
+	defaultSchemaGeneratorOnce sync.Once
+)
+
+// SchemaFor 为配置结构体（或其指针）生成 JSON Schema
+// 属性名取 yaml/mapstructure 标签，约束取 validate 标签，描述取源码注释，
+// 传入值中非零的标量字段作为 default
+func SchemaFor(config any) (*JSONSchema, error) {
+	defaultSchemaGeneratorOnce.Do(func() {
+		sourceRoot := ""
+		if _, file, _, ok := runtime.Caller(0); ok {
+			sourceRoot = filepath.Dir(file)
+		}
+		defaultSchemaGenerator = NewSchemaGenerator(sourceRoot)
+	})
+	return defaultSchemaGenerator.Generate(config)
+}
+
+// Generate 为配置结构体（或其指针）生成 JSON Schema
+func (g *SchemaGenerator) Generate(config any) (*JSONSchema, error) {
+	if config == nil {
+		return nil, ErrConfigEmpty
+	}
+
+	v := reflect.ValueOf(config)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem()).Elem()
+			break
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, ErrSchemaUnsupportedType(v.Type())
+	}
+
+	b := &schemaBuilder{gen: g, defs: make(map[string]*JSONSchema), visiting: make(map[reflect.Type]bool)}
+	root := b.structSchema(v)
+	root.Schema = JSONSchemaDraft
+	if len(b.defs) > 0 {
+		root.Defs = b.defs
+	}
+	return root, nil
+}
+
+// schemaBuilder 单次生成的上下文
+type schemaBuilder struct {
+	gen      *SchemaGenerator
+	defs     map[string]*JSONSchema
+	visiting map[reflect.Type]bool // 正在展开的结构体类型，用于检测递归
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// valueSchema 为任意值生成模式
+func (b *schemaBuilder) valueSchema(v reflect.Value) *JSONSchema {
+	t := v.Type()
+
+	if t.Kind() == reflect.Ptr {
+		elem := reflect.New(t.Elem()).Elem()
+		if !v.IsNil() {
+			elem = v.Elem()
+		}
+		s := b.valueSchema(elem)
+		if s.Ref == "" {
+			s.Type = appendSchemaType(s.Type, "null")
+		}
+		return s
+	}
+
+	if enum := lookupSchemaEnum(t); enum != nil {
+		return &JSONSchema{Type: jsonTypeOf(t.Kind()), Enum: enum}
+	}
+
+	switch {
+	case t == durationType:
+		return &JSONSchema{Type: []string{"string", "integer"}, Pattern: durationPattern}
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return b.structSchema(v)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: b.valueSchema(reflect.New(t.Elem()).Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: b.valueSchema(reflect.New(t.Elem()).Elem())}
+	case reflect.Interface:
+		return &JSONSchema{}
+	default:
+		return &JSONSchema{Type: jsonTypeOf(t.Kind())}
+	}
+}
+
+// structSchema 为结构体生成对象模式，递归类型会放入 $defs 并以 $ref 引用
+func (b *schemaBuilder) structSchema(v reflect.Value) *JSONSchema {
+	t := v.Type()
+	if b.visiting[t] {
+		name := schemaDefName(t)
+		if _, exists := b.defs[name]; !exists {
+			b.defs[name] = &JSONSchema{} // 占位，防止无限递归
+			b.defs[name] = b.expandStruct(reflect.New(t).Elem())
+		}
+		return &JSONSchema{Ref: "#/$defs/" + name}
+	}
+
+	b.visiting[t] = true
+	defer delete(b.visiting, t)
+	return b.expandStruct(v)
+}
+
+// expandStruct 展开结构体字段
+func (b *schemaBuilder) expandStruct(v reflect.Value) *JSONSchema {
+	t := v.Type()
+	comments := b.gen.structComments(t)
+
+	s := &JSONSchema{
+		Type:       "object",
+		Title:      t.Name(),
+		Properties: make(map[string]*JSONSchema),
+	}
+	if comments != nil {
+		s.Description = comments.Doc
+	}
+
+	b.addFields(s, v, comments)
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// addFields 将结构体字段添加为属性（支持 inline/squash 展开）
+func (b *schemaBuilder) addFields(s *JSONSchema, v reflect.Value, comments *structComments) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, inline, skip := schemaFieldName(field)
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+		if inline {
+			inner := fv
+			if inner.Kind() == reflect.Ptr {
+				if inner.IsNil() {
+					inner = reflect.New(inner.Type().Elem())
+				}
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				b.addFields(s, inner, b.gen.structComments(inner.Type()))
+				continue
+			}
+		}
+
+		switch fv.Kind() {
+		case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+			continue
+		}
+
+		prop := b.valueSchema(fv)
+		if comments != nil {
+			if comment := comments.Fields[field.Name]; comment != "" {
+				prop.Description = comment
+			}
+		}
+		if def := schemaDefault(fv); def != nil {
+			prop.Default = def
+		}
+		if applyValidateTag(prop, field.Type, field.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = prop
+	}
+}
+
+// schemaFieldName 解析字段在配置文件中的键名
+// 优先 yaml 标签（与生成的 YAML 一致），其次 mapstructure 标签
+func schemaFieldName(field reflect.StructField) (name string, inline bool, skip bool) {
+	yamlName, yamlOpts := splitTag(field.Tag.Get("yaml"))
+	msName, msOpts := splitTag(field.Tag.Get("mapstructure"))
+
+	if yamlName == "-" || (yamlName == "" && msName == "-") {
+		return "", false, true
+	}
+	if hasTagOption(yamlOpts, "inline") || hasTagOption(msOpts, "squash") {
+		return "", true, false
+	}
+
+	switch {
+	case yamlName != "":
+		name = yamlName
+	case msName != "":
+		name = msName
+	default:
+		name = strings.ToLower(field.Name)
+	}
+	return name, false, false
+}
+
+// splitTag 拆分标签名称与选项
+func splitTag(tag string) (string, []string) {
+	if tag == "" {
+		return "", nil
+	}
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// hasTagOption 检查标签选项
+func hasTagOption(opts []string, option string) bool {
+	for _, opt := range opts {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// applyValidateTag 将 validate 标签映射为模式约束，返回字段是否必填
+// dive 之后的规则作用于数组元素或映射值
+func applyValidateTag(s *JSONSchema, t reflect.Type, tag string) bool {
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	required := false
+	target, targetType := s, t
+	for _, rule := range strings.Split(tag, ",") {
+		key, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		for targetType.Kind() == reflect.Ptr {
+			targetType = targetType.Elem()
+		}
+
+		switch key {
+		case "required":
+			if target == s {
+				required = true
+			} else if targetType.Kind() == reflect.String {
+				target.MinLength = intPtr(1)
+			}
+		case "dive":
+			switch {
+			case target.Items != nil:
+				target, targetType = target.Items, targetType.Elem()
+			case target.AdditionalProperties != nil:
+				target, targetType = target.AdditionalProperties, targetType.Elem()
+			default:
+				return required
+			}
+		case "min", "gte":
+			applyBound(target, targetType, param, true, false)
+		case "max", "lte":
+			applyBound(target, targetType, param, false, false)
+		case "gt":
+			applyBound(target, targetType, param, true, true)
+		case "lt":
+			applyBound(target, targetType, param, false, true)
+		case "len":
+			applyBound(target, targetType, param, true, false)
+			applyBound(target, targetType, param, false, false)
+		case "oneof":
+			target.Enum = parseOneOf(targetType, param)
+		case "url", "uri", "http_url":
+			target.Format = "uri"
+		case "email":
+			target.Format = "email"
+		case "hostname", "hostname_rfc1123":
+			target.Format = "hostname"
+		case "ipv4":
+			target.Format = "ipv4"
+		case "ipv6":
+			target.Format = "ipv6"
+		case "uuid", "uuid4":
+			target.Format = "uuid"
+		}
+	}
+	return required
+}
+
+// applyBound 根据字段类型设置数值/长度/元素个数边界
+func applyBound(s *JSONSchema, t reflect.Type, param string, lower, exclusive bool) {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if exclusive {
+			if lower {
+				n++
+			} else {
+				n--
+			}
+		}
+		switch {
+		case t.Kind() == reflect.String && lower:
+			s.MinLength = intPtr(n)
+		case t.Kind() == reflect.String:
+			s.MaxLength = intPtr(n)
+		case t.Kind() == reflect.Map:
+			// JSON Schema 中映射长度对应 minProperties，此处不做约束
+		case lower:
+			s.MinItems = intPtr(n)
+		default:
+			s.MaxItems = intPtr(n)
+		}
+	default:
+		if t == durationType {
+			// validate 对 Duration 的边界基于纳秒整数，配置文件中通常写作字符串，不做约束
+			return
+		}
+		f, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		switch {
+		case lower && exclusive:
+			s.ExclusiveMinimum = &f
+		case lower:
+			s.Minimum = &f
+		case exclusive:
+			s.ExclusiveMaximum = &f
+		default:
+			s.Maximum = &f
+		}
+	}
+}
+
+// parseOneOf 解析 oneof 参数为枚举列表
+func parseOneOf(t reflect.Type, param string) []any {
+	values := strings.Fields(param)
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		switch jsonTypeOf(t.Kind()) {
+		case "integer":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				enum = append(enum, n)
+				continue
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				enum = append(enum, f)
+				continue
+			}
+		}
+		enum = append(enum, strings.Trim(value, "'"))
+	}
+	return enum
+}
+
+// schemaDefault 提取标量字段（及标量数组）的非零值作为默认值
+func schemaDefault(v reflect.Value) any {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.IsZero() {
+		return nil
+	}
+
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Type() == timeType:
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return plainValue(v)
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+		items := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := schemaDefault(v.Index(i))
+			if item == nil {
+				return nil // 非标量元素或存在零值时不输出默认值
+			}
+			items = append(items, item)
+		}
+		return items
+	}
+	return nil
+}
+
+// plainValue 将命名类型转为其基础类型的值，保证 JSON 输出稳定
+func plainValue(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return v.Interface()
+}
+
+// jsonTypeOf 将 Go 基础类型映射为 JSON Schema 类型
+func jsonTypeOf(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// appendSchemaType 向 type 追加一个可选类型
+func appendSchemaType(current any, extra string) any {
+	switch tv := current.(type) {
+	case string:
+		if tv == "" {
+			return nil
+		}
+		return []string{tv, extra}
+	case []string:
+		return append(tv, extra)
+	}
+	return current
+}
+
+// schemaDefName 生成 $defs 中的类型名称: 包名.类型名
+func schemaDefName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+		pkg = pkg[idx+1:]
+	}
+	if pkg == "" {
+		return t.Name()
+	}
+	return pkg + "." + t.Name()
+}
+
+func intPtr(n int) *int { return &n }
+
+// structComments 获取结构体的源码注释（字段行尾注释优先，其次字段文档注释）
+func (g *SchemaGenerator) structComments(t reflect.Type) *structComments {
+	if g.SourceRoot == "" || t.Name() == "" || t.PkgPath() == "" {
+		return nil
+	}
+
+	pkgPath := t.PkgPath()
+	if idx := strings.LastIndex(pkgPath, "/"); idx >= 0 {
+		pkgPath = pkgPath[idx+1:]
+	}
+	pkgDir := filepath.Join(g.SourceRoot, "pkg", pkgPath)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	structs, exists := g.commentCache[pkgDir]
+	if !exists {
+		structs, _ = parseStructComments(pkgDir)
+		g.commentCache[pkgDir] = structs
+	}
+	return structs[t.Name()]
+}
+
+// parseStructComments 解析包目录下所有结构体的文档与字段注释，供 Schema 与配置模板生成共用
+func parseStructComments(pkgDir string) (map[string]*structComments, error) {
+	result := make(map[string]*structComments)
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkgDir, nil, parser.ParseComments)
+	if err != nil {
+		return result, err
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					structType, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+
+					doc := typeSpec.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					info := &structComments{
+						Doc:      trimTypeDoc(typeSpec.Name.Name, doc),
+						Fields:   make(map[string]string),
+						Trailing: make(map[string]string),
+					}
+					for _, field := range structType.Fields.List {
+						trailing := ""
+						if field.Comment != nil {
+							trailing = strings.TrimSpace(field.Comment.Text())
+						}
+						comment := trailing
+						if comment == "" && field.Doc != nil {
+							comment = strings.TrimSpace(field.Doc.Text())
+						}
+						for _, name := range field.Names {
+							if comment != "" {
+								info.Fields[name.Name] = comment
+							}
+							if trailing != "" {
+								info.Trailing[name.Name] = trailing
+							}
+						}
+					}
+					result[typeSpec.Name.Name] = info
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// trimTypeDoc 去掉文档注释开头的类型名: "RateLimit 增强限流配置" -> "增强限流配置"
+func trimTypeDoc(typeName string, doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	text := strings.TrimSpace(doc.Text())
+	if rest, ok := strings.CutPrefix(text, typeName); ok {
+		text = strings.TrimSpace(rest)
+	}
+	return text
+}
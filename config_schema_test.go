@@ -0,0 +1,191 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_schema_test.go
+ * @Description: JSON Schema 导出测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/gateway"
+	"github.com/kamalyes/go-config/pkg/jobs"
+	"github.com/kamalyes/go-config/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestConfig struct {
+	Name     string              `mapstructure:"name" yaml:"name" json:"name" validate:"required"`                 // 服务名称
+	Port     int                 `mapstructure:"port" yaml:"port" json:"port" validate:"required,min=1,max=65535"` // 端口
+	Level    string              `mapstructure:"level" yaml:"level" json:"level" validate:"oneof=debug info warn"` // 日志级别
+	Endpoint string              `mapstructure:"endpoint" yaml:"endpoint" json:"endpoint" validate:"required,url"` // 端点
+	Hosts    []string            `mapstructure:"hosts" yaml:"hosts" json:"hosts" validate:"min=1,dive,required"`   // 主机列表
+	Timeout  time.Duration       `mapstructure:"timeout" yaml:"timeout" json:"timeout"`                            // 超时
+	Labels   map[string]string   `mapstructure:"labels" yaml:"labels" json:"labels"`                               // 标签
+	Handler  func()              `mapstructure:"-" yaml:"-" json:"-"`                                              // 不导出
+	Snake    string              `mapstructure:"snake_only"`                                                       // 仅mapstructure
+	Strategy *ratelimit.Strategy `mapstructure:"strategy" yaml:"strategy" json:"strategy"`                         // 策略
+}
+
+func TestSchemaFor_ValidateTags(t *testing.T) {
+	cfg := &schemaTestConfig{Name: "svc", Port: 8080, Timeout: 30 * time.Second, Hosts: []string{"a", "b"}}
+
+	schema, err := SchemaFor(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, JSONSchemaDraft, schema.Schema)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"endpoint", "name", "port"}, schema.Required)
+
+	port := schema.Properties["port"]
+	require.NotNil(t, port)
+	assert.Equal(t, "integer", port.Type)
+	assert.Equal(t, 1.0, *port.Minimum)
+	assert.Equal(t, 65535.0, *port.Maximum)
+	assert.EqualValues(t, 8080, port.Default)
+
+	assert.Equal(t, []any{"debug", "info", "warn"}, schema.Properties["level"].Enum)
+	assert.Equal(t, "uri", schema.Properties["endpoint"].Format)
+
+	hosts := schema.Properties["hosts"]
+	assert.Equal(t, "array", hosts.Type)
+	assert.Equal(t, 1, *hosts.MinItems)
+	assert.Equal(t, 1, *hosts.Items.MinLength)
+	assert.Equal(t, []any{"a", "b"}, hosts.Default)
+
+	timeout := schema.Properties["timeout"]
+	assert.Equal(t, []string{"string", "integer"}, timeout.Type)
+	assert.Equal(t, "30s", timeout.Default)
+
+	assert.Equal(t, "object", schema.Properties["labels"].Type)
+	assert.Equal(t, "string", schema.Properties["labels"].AdditionalProperties.Type)
+
+	assert.NotContains(t, schema.Properties, "handler")
+	assert.Contains(t, schema.Properties, "snake_only")
+
+	strategy := schema.Properties["strategy"]
+	assert.Equal(t, []string{"string", "null"}, strategy.Type)
+	assert.Contains(t, strategy.Enum, "token-bucket")
+}
+
+func TestSchemaFor_EnumsAndComments(t *testing.T) {
+	schema, err := SchemaFor(ratelimit.Default())
+	require.NoError(t, err)
+
+	assert.Equal(t, "RateLimit", schema.Title)
+	assert.Equal(t, "增强限流配置", schema.Description)
+
+	strategy := schema.Properties["strategy"]
+	require.NotNil(t, strategy)
+	assert.Equal(t, []any{"token-bucket", "leaky-bucket", "sliding-window", "fixed-window"}, strategy.Enum)
+	assert.Equal(t, "token-bucket", strategy.Default)
+	assert.Equal(t, "限流策略", strategy.Description)
+}
+
+func TestSchemaFor_Gateway(t *testing.T) {
+	schema, err := SchemaFor(gateway.Default())
+	require.NoError(t, err)
+
+	httpServer := schema.Properties["http"]
+	require.NotNil(t, httpServer)
+	tls := httpServer.Properties["tls"]
+	require.NotNil(t, tls)
+	assert.Equal(t, []any{"TLS10", "TLS11", "TLS12", "TLS13"}, tls.Properties["min-version"].Enum)
+
+	data, err := schema.ToJSON()
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+}
+
+func TestSchemaFor_RecursiveType(t *testing.T) {
+	schema, err := SchemaFor(&jobs.TaskCfg{})
+	require.NoError(t, err)
+
+	inline := schema.Properties["dependencies"].Items.Properties["inline"]
+	assert.Equal(t, "#/$defs/jobs.TaskCfg", inline.Ref)
+	require.Contains(t, schema.Defs, "jobs.TaskCfg")
+	assert.Contains(t, schema.Defs["jobs.TaskCfg"].Properties, "cron-spec")
+}
+
+func TestSchemaFor_UnsupportedType(t *testing.T) {
+	_, err := SchemaFor("not a struct")
+	assert.Error(t, err)
+
+	_, err = SchemaFor(nil)
+	assert.ErrorIs(t, err, ErrConfigEmpty)
+}
+
+func TestSmartConfigGenerator_GenerateSchemaFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	generator := NewSmartConfigGenerator(tempDir).WithBackupExisting(false)
+	require.NoError(t, generator.EnableOnlyModules("ratelimit"))
+	require.NoError(t, generator.GenerateAllConfigs())
+
+	schemaPath := filepath.Join(tempDir, "pkg", "ratelimit", "ratelimit.schema.json")
+	data, err := os.ReadFile(schemaPath)
+	require.NoError(t, err)
+
+	var schema JSONSchema
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, JSONSchemaDraft, schema.Schema)
+	assert.Equal(t, "ratelimit.schema.json", schema.ID)
+	assert.Contains(t, schema.Properties, "global-limit")
+
+	yamlData, err := os.ReadFile(filepath.Join(tempDir, "pkg", "ratelimit", "ratelimit.yaml"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(yamlData), "# yaml-language-server: $schema=./ratelimit.schema.json"))
+}
+
+func TestSmartConfigGenerator_SchemaFileBackup(t *testing.T) {
+	tempDir := t.TempDir()
+
+	generator := NewSmartConfigGenerator(tempDir).WithBackupExisting(true)
+	require.NoError(t, generator.EnableOnlyModules("ratelimit"))
+	require.NoError(t, generator.GenerateAllConfigs())
+	require.NoError(t, generator.GenerateAllConfigs())
+
+	// 与其他格式一致，覆盖前备份已有的模式文件
+	backups, err := filepath.Glob(filepath.Join(tempDir, "pkg", "ratelimit", "ratelimit.schema.json.backup.*"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestSmartConfigGenerator_WithoutSchema(t *testing.T) {
+	tempDir := t.TempDir()
+
+	generator := NewSmartConfigGenerator(tempDir).WithBackupExisting(false).WithGenerateSchema(false)
+	require.NoError(t, generator.EnableOnlyModules("health"))
+	require.NoError(t, generator.GenerateAllConfigs())
+
+	_, err := os.Stat(filepath.Join(tempDir, "pkg", "health", "health.schema.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSchemaFor_AllRegisteredModules(t *testing.T) {
+	generator := NewSmartConfigGenerator(t.TempDir())
+
+	for _, name := range generator.GetModuleList() {
+		module := generator.ModuleRegistry[name]
+		t.Run(name, func(t *testing.T) {
+			schema, err := SchemaFor(module.DefaultFunc())
+			require.NoError(t, err)
+			assert.NotEmpty(t, schema.Properties)
+
+			_, err = schema.ToJSON()
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,627 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_tag_checker.go
+ * @Description: 配置结构体标签一致性检查 - mapstructure/yaml/json 名称不一致、缺失、非法与归一化后重复，支持自动修复
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TagIssueKind 标签问题类型
+type TagIssueKind string
+
+const (
+	TagIssueMissing   TagIssueKind = "missing"   // 缺少 mapstructure/yaml/json 标签
+	TagIssueMismatch  TagIssueKind = "mismatch"  // mapstructure 与 yaml 名称不同，json 归一化后不同或不是 camelCase
+	TagIssueInvalid   TagIssueKind = "invalid"   // 非法键名，如 "_"
+	TagIssueDuplicate TagIssueKind = "duplicate" // 同一结构体内键名按灵活命名归一化后重复
+)
+
+// checkedTagKeys 参与一致性检查的标签，也是修复后标签的排列顺序
+var checkedTagKeys = []string{"mapstructure", "yaml", "json"}
+
+// tagNamePattern 合法的配置键名
+var tagNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// TagIssue 一个结构体字段的标签问题
+type TagIssue struct {
+	Module       string       `json:"module,omitempty"`        // 首次发现该类型的模块
+	Type         string       `json:"type"`                    // 结构体类型，如 pay.WechatPay
+	Field        string       `json:"field"`                   // 字段名
+	Kind         TagIssueKind `json:"kind"`                    // 问题类型
+	Message      string       `json:"message"`                 // 问题描述
+	Tag          string       `json:"tag"`                     // 当前标签
+	SuggestedTag string       `json:"suggested_tag,omitempty"` // 修复后的标签，为空表示无法自动修复
+	File         string       `json:"file,omitempty"`          // 源文件（无法定位时为空）
+	Line         int          `json:"line,omitempty"`          // 标签所在行
+	Column       int          `json:"column,omitempty"`        // 标签所在列
+}
+
+// String 格式化为 go vet 风格: file:line:column: Type.Field: message [kind]
+func (i TagIssue) String() string {
+	var sb strings.Builder
+	if i.File != "" {
+		sb.WriteString(i.File)
+		if i.Line > 0 {
+			fmt.Fprintf(&sb, ":%d:%d", i.Line, i.Column)
+		}
+		sb.WriteString(": ")
+	}
+	fmt.Fprintf(&sb, "%s.%s: %s [%s]", i.Type, i.Field, i.Message, i.Kind)
+	return sb.String()
+}
+
+// Fixable 是否可以自动修复
+func (i TagIssue) Fixable() bool {
+	return i.SuggestedTag != "" && i.File != ""
+}
+
+// TagChecker 配置结构体标签一致性检查器
+type TagChecker struct {
+	SourceRoot string // 源码根目录（go.mod 所在目录），用于定位字段与自动修复
+
+	mu        sync.Mutex
+	positions map[string]map[string]token.Position // 包目录 -> "类型.字段" -> 标签位置
+}
+
+// NewTagChecker 创建标签检查器
+func NewTagChecker(sourceRoot string) *TagChecker {
+	return &TagChecker{
+		SourceRoot: sourceRoot,
+		positions:  make(map[string]map[string]token.Position),
+	}
+}
+
+// CheckModuleTags 使用当前源码目录检查所有已注册模块的配置结构体
+func CheckModuleTags() []TagIssue {
+	sourceRoot := ""
+	if _, file, _, ok := runtime.Caller(0); ok {
+		sourceRoot = filepath.Dir(file)
+	}
+	return NewTagChecker(sourceRoot).CheckModules()
+}
+
+// TagTestingT 标签检查测试辅助所需的 testing.TB 子集
+type TagTestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertConsistentTags 在测试中检查配置结构体标签，每个问题报告为一个测试错误
+// 未传入配置时检查所有已注册模块
+func AssertConsistentTags(t TagTestingT, configs ...any) {
+	t.Helper()
+	sourceRoot := ""
+	if _, file, _, ok := runtime.Caller(0); ok {
+		sourceRoot = filepath.Dir(file)
+	}
+	checker := NewTagChecker(sourceRoot)
+
+	var issues []TagIssue
+	if len(configs) == 0 {
+		issues = checker.CheckModules()
+	}
+	for _, config := range configs {
+		issues = append(issues, checker.Check(config)...)
+	}
+	for _, issue := range issues {
+		t.Errorf("%s", issue.String())
+	}
+}
+
+// CheckModules 检查所有已注册模块的配置结构体，同一类型只报告一次
+func (c *TagChecker) CheckModules() []TagIssue {
+	visited := make(map[reflect.Type]bool)
+	var issues []TagIssue
+	for _, module := range RegisteredModules() {
+		if module.DefaultFunc == nil {
+			continue
+		}
+		issues = append(issues, c.checkType(module.Name, reflect.TypeOf(module.DefaultFunc()), visited)...)
+	}
+	sortTagIssues(issues)
+	return issues
+}
+
+// Check 检查单个配置结构体（或其指针）及其嵌套结构体
+func (c *TagChecker) Check(config any) []TagIssue {
+	if config == nil {
+		return nil
+	}
+	issues := c.checkType("", reflect.TypeOf(config), make(map[reflect.Type]bool))
+	sortTagIssues(issues)
+	return issues
+}
+
+// Fix 将可修复问题的建议标签写回源文件，返回被修改的文件
+func (c *TagChecker) Fix(issues []TagIssue) ([]string, error) {
+	byFile := make(map[string][]TagIssue)
+	for _, issue := range issues {
+		if issue.Fixable() {
+			byFile[issue.File] = append(byFile[issue.File], issue)
+		}
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if err := fixTagsInFile(file, byFile[file]); err != nil {
+			return nil, fmt.Errorf("fix %s: %w", file, err)
+		}
+	}
+
+	c.mu.Lock()
+	c.positions = make(map[string]map[string]token.Position)
+	c.mu.Unlock()
+	return files, nil
+}
+
+// checkType 递归检查类型中的结构体（穿透指针、切片、数组与映射）
+func (c *TagChecker) checkType(module string, t reflect.Type, visited map[reflect.Type]bool) []TagIssue {
+	for t != nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+			continue
+		}
+		break
+	}
+	if t == nil || t.Kind() != reflect.Struct || visited[t] || !c.ownsType(t) {
+		return nil
+	}
+	visited[t] = true
+
+	var issues []TagIssue
+	seen := make(map[string]string) // 归一化键名 -> 首个字段名
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		for _, issue := range checkFieldTags(field) {
+			issues = append(issues, c.newTagIssue(module, t, field, issue))
+		}
+
+		if key := decodeKey(field); key != "" {
+			normalized := normalizeConfigKey(key)
+			if first, exists := seen[normalized]; exists {
+				issues = append(issues, c.newTagIssue(module, t, field, fieldTagIssue{
+					kind:    TagIssueDuplicate,
+					message: fmt.Sprintf("key %q collides with field %s after flexible-name normalization", key, first),
+				}))
+			} else {
+				seen[normalized] = field.Name
+			}
+		}
+		issues = append(issues, c.checkType(module, field.Type, visited)...)
+	}
+	return issues
+}
+
+// ownsType 只检查本模块（根包及其子包）中定义的具名结构体
+func (c *TagChecker) ownsType(t reflect.Type) bool {
+	rootPkg := reflect.TypeOf(TagChecker{}).PkgPath()
+	pkg := t.PkgPath()
+	return pkg == rootPkg || strings.HasPrefix(pkg, rootPkg+"/")
+}
+
+// newTagIssue 补充类型、模块与源码位置信息
+func (c *TagChecker) newTagIssue(module string, t reflect.Type, field reflect.StructField, issue fieldTagIssue) TagIssue {
+	result := TagIssue{
+		Module:       module,
+		Type:         t.String(),
+		Field:        field.Name,
+		Kind:         issue.kind,
+		Message:      issue.message,
+		Tag:          string(field.Tag),
+		SuggestedTag: issue.suggested,
+	}
+	if pos, ok := c.fieldPosition(t, field.Name); ok {
+		result.File, result.Line, result.Column = pos.Filename, pos.Line, pos.Column
+	}
+	return result
+}
+
+// fieldPosition 查找字段标签在源码中的位置
+func (c *TagChecker) fieldPosition(t reflect.Type, fieldName string) (token.Position, bool) {
+	pkgDir := c.packageDir(t.PkgPath())
+	if pkgDir == "" || t.Name() == "" {
+		return token.Position{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.positions == nil {
+		c.positions = make(map[string]map[string]token.Position)
+	}
+	fields, ok := c.positions[pkgDir]
+	if !ok {
+		fields = parseTagPositions(pkgDir)
+		c.positions[pkgDir] = fields
+	}
+	pos, ok := fields[t.Name()+"."+fieldName]
+	return pos, ok
+}
+
+// packageDir 将包路径映射为 SourceRoot 下的目录
+func (c *TagChecker) packageDir(pkgPath string) string {
+	if c.SourceRoot == "" {
+		return ""
+	}
+	rootPkg := reflect.TypeOf(TagChecker{}).PkgPath()
+	if pkgPath == rootPkg {
+		return c.SourceRoot
+	}
+	if rel, ok := strings.CutPrefix(pkgPath, rootPkg+"/"); ok {
+		return filepath.Join(c.SourceRoot, filepath.FromSlash(rel))
+	}
+	return ""
+}
+
+// parseTagPositions 解析包目录中所有结构体字段标签的位置（含测试文件）
+func parseTagPositions(pkgDir string) map[string]token.Position {
+	result := make(map[string]token.Position)
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkgDir, nil, 0)
+	if err != nil {
+		return result
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			forEachTaggedField(file, func(typeName, fieldName string, tag *ast.BasicLit) {
+				result[typeName+"."+fieldName] = fset.Position(tag.Pos())
+			})
+		}
+	}
+	return result
+}
+
+// forEachTaggedField 遍历文件中具名结构体的带标签字段
+func forEachTaggedField(file *ast.File, fn func(typeName, fieldName string, tag *ast.BasicLit)) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		typeSpec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := typeSpec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		for _, field := range structType.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			for _, name := range field.Names {
+				fn(typeSpec.Name.Name, name.Name, field.Tag)
+			}
+		}
+		return true
+	})
+}
+
+// fixTagsInFile 将建议标签写回单个源文件并 gofmt
+func fixTagsInFile(path string, issues []TagIssue) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	suggested := make(map[int]string, len(issues)) // 标签行 -> 建议标签
+	for _, issue := range issues {
+		suggested[issue.Line] = issue.SuggestedTag
+	}
+
+	type edit struct {
+		start, end int
+		text       string
+	}
+	var edits []edit
+	forEachTaggedField(file, func(typeName, fieldName string, tag *ast.BasicLit) {
+		pos := fset.Position(tag.Pos())
+		newTag, ok := suggested[pos.Line]
+		if !ok {
+			return
+		}
+		delete(suggested, pos.Line)
+		edits = append(edits, edit{start: pos.Offset, end: fset.Position(tag.End()).Offset, text: "`" + newTag + "`"})
+	})
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+
+	for _, e := range edits {
+		src = append(src[:e.start], append([]byte(e.text), src[e.end:]...)...)
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
+
+// fieldTagIssue 字段级检查结果
+type fieldTagIssue struct {
+	kind      TagIssueKind
+	message   string
+	suggested string
+}
+
+// checkFieldTags 检查单个字段的 mapstructure/yaml/json 标签
+// 规则: 三者均需存在且为合法键名或 "-"；mapstructure 与 yaml 必须完全一致（否则按 yaml 生成的文件在严格解码时丢值）；
+// json 须为 camelCase（不含 "_"、"-"）且归一化后与 mapstructure 相同，也可单独为 "-"
+func checkFieldTags(field reflect.StructField) []fieldTagIssue {
+	tag := string(field.Tag)
+	names := make(map[string]string, len(checkedTagKeys))
+	var missing, invalid []string
+	for _, key := range checkedTagKeys {
+		value, ok := field.Tag.Lookup(key)
+		name, _ := splitTag(value)
+		if !ok || name == "" {
+			missing = append(missing, key)
+			continue
+		}
+		if name != "-" && !tagNamePattern.MatchString(name) {
+			invalid = append(invalid, key)
+		}
+		names[key] = name
+	}
+
+	canonical := canonicalTagName(field, names)
+	want := map[string]string{"mapstructure": canonical, "yaml": canonical, "json": canonical}
+	if canonical != "-" {
+		want["json"] = jsonTagName(canonical, names["json"])
+	}
+
+	var issues []fieldTagIssue
+	if len(missing) > 0 {
+		issues = append(issues, fieldTagIssue{
+			kind:    TagIssueMissing,
+			message: fmt.Sprintf("missing %s tag name", strings.Join(missing, "/")),
+		})
+	}
+	if len(invalid) > 0 {
+		parts := make([]string, 0, len(invalid))
+		for _, key := range invalid {
+			parts = append(parts, fmt.Sprintf("%s:%q", key, names[key]))
+		}
+		issues = append(issues, fieldTagIssue{
+			kind:    TagIssueInvalid,
+			message: fmt.Sprintf("invalid key name %s", strings.Join(parts, " ")),
+		})
+	}
+	if len(invalid) == 0 {
+		ms, yml, js := names["mapstructure"], names["yaml"], names["json"]
+		switch {
+		case ms != "" && yml != "" && ms != yml:
+			issues = append(issues, fieldTagIssue{
+				kind:    TagIssueMismatch,
+				message: fmt.Sprintf("mapstructure %q does not match yaml %q", ms, yml),
+			})
+		case js != "" && js != "-" && canonical != "-" && normalizeConfigKey(js) != normalizeConfigKey(canonical):
+			issues = append(issues, fieldTagIssue{
+				kind:    TagIssueMismatch,
+				message: fmt.Sprintf("json %q does not match %q after normalization", js, canonical),
+			})
+		case js != "" && js != "-" && !isCamelCase(js):
+			issues = append(issues, fieldTagIssue{
+				kind:    TagIssueMismatch,
+				message: fmt.Sprintf("json %q is not camelCase", js),
+			})
+		}
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+
+	suggested := tag
+	for _, key := range checkedTagKeys {
+		if names[key] == want[key] || (key == "json" && names[key] == "-" && canonical != "-") {
+			continue
+		}
+		suggested = setTagName(suggested, key, want[key])
+	}
+	if suggested != tag {
+		for i := range issues {
+			issues[i].suggested = suggested
+		}
+	}
+	return issues
+}
+
+// canonicalTagName 选取修复时使用的键名: yaml 优先（与生成的配置文件一致），其次 mapstructure、json，
+// 任一检查标签为 "-" 而其余为非法键名时统一为 "-"，都没有时由字段名转 kebab-case
+func canonicalTagName(field reflect.StructField, names map[string]string) string {
+	for _, key := range []string{"yaml", "mapstructure"} {
+		if name := names[key]; name == "-" || tagNamePattern.MatchString(name) {
+			return name
+		}
+	}
+	if names["json"] == "-" {
+		return "-"
+	}
+	if name := names["json"]; tagNamePattern.MatchString(name) {
+		return toKebabCase(name)
+	}
+	return toKebabCase(field.Name)
+}
+
+// jsonTagName 修复 json 标签时保留已有的 camelCase 名称（如 publicKeyPem），缺失、非法或非 camelCase 时由 canonical 转换
+func jsonTagName(canonical, current string) string {
+	if tagNamePattern.MatchString(current) && isCamelCase(current) && normalizeConfigKey(current) == normalizeConfigKey(canonical) {
+		return current
+	}
+	var sb strings.Builder
+	upper := false
+	for i, r := range canonical {
+		switch {
+		case r == '-' || r == '_':
+			upper = i > 0
+		case upper:
+			sb.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// isCamelCase json 键名不含 "_"、"-" 分隔符
+func isCamelCase(name string) bool {
+	return !strings.ContainsAny(name, "_-")
+}
+
+// decodeKey 字段在解码时使用的键名（mapstructure 标签，缺失时为字段名），忽略的字段返回空
+func decodeKey(field reflect.StructField) string {
+	name, _ := splitTag(field.Tag.Get("mapstructure"))
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// toKebabCase 将 PascalCase/camelCase/snake_case 转为 kebab-case，连续大写视为一个词: CertP12Path -> cert-p12-path
+func toKebabCase(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if r == '_' || r == '-' {
+			sb.WriteByte('-')
+			continue
+		}
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' && runes[i-1] != '-' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+					(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// tagPair 结构体标签中的一项
+type tagPair struct {
+	key   string
+	value string
+}
+
+// parseTagPairs 按 reflect.StructTag 规则拆分标签，保持原顺序
+func parseTagPairs(tag string) []tagPair {
+	var pairs []tagPair
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " \t")
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		pairs = append(pairs, tagPair{key: key, value: value})
+		tag = tag[i+1:]
+	}
+	return pairs
+}
+
+// setTagName 设置标签中指定键的名称（保留 omitempty 等选项）
+// 检查的三个标签按 mapstructure、yaml、json 顺序排在最前，其余标签保持原顺序
+func setTagName(tag, key, name string) string {
+	pairs := parseTagPairs(tag)
+	found := false
+	for i := range pairs {
+		if pairs[i].key == key {
+			_, opts := splitTag(pairs[i].value)
+			pairs[i].value = strings.Join(append([]string{name}, opts...), ",")
+			found = true
+		}
+	}
+	if !found {
+		pairs = append(pairs, tagPair{key: key, value: name})
+	}
+
+	rank := func(key string) int {
+		for i, k := range checkedTagKeys {
+			if k == key {
+				return i
+			}
+		}
+		return len(checkedTagKeys)
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return rank(pairs[i].key) < rank(pairs[j].key) })
+
+	parts := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		parts = append(parts, pair.key+":"+strconv.Quote(pair.value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// sortTagIssues 按文件、行、字段排序
+func sortTagIssues(issues []TagIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Field < b.Field
+	})
+}
@@ -0,0 +1,146 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_tag_checker_test.go
+ * @Description: 配置结构体标签一致性检查测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tagCheckSample 覆盖各类标签问题的结构体，源码同时写入临时目录用于定位与修复
+type tagCheckSample struct {
+	Name        string            `mapstructure:"name" yaml:"name" json:"name"`
+	CertP12Path string            `mapstructure:"cert_p12_path" yaml:"cert-p12-path" json:"cert_p12_path" validate:"required"`
+	Endpoint    string            `mapstructure:"_" yaml:"-" json:"_"`
+	MaxRetries  int               `yaml:"max-retries"`
+	Timeout     int               `mapstructure:"timeout" yaml:"timeout" json:"deadline"`
+	ServiceName string            `mapstructure:"service-name" yaml:"service-name" json:"serviceName"`
+	Service     string            `mapstructure:"servicename" yaml:"servicename" json:"servicename"`
+	Nested      *tagCheckNested   `mapstructure:"nested" yaml:"nested" json:"nested"`
+	Items       []*tagCheckNested `mapstructure:"items" yaml:"items" json:"items"`
+	Hidden      string            `mapstructure:"-" yaml:"-" json:"hidden"`
+	RetryDelay  int               `mapstructure:"retry-delay" yaml:"retry-delay" json:"retry_delay,omitempty"`
+	internal    string
+}
+
+// tagCheckNested 嵌套结构体（切片与指针中出现，只检查一次）
+type tagCheckNested struct {
+	HostName string `mapstructure:"hostname" yaml:"host-name" json:"hostName"`
+}
+
+const tagCheckSampleSource = "package goconfig\n\n" +
+	"type tagCheckSample struct {\n" +
+	"\tName        string            `mapstructure:\"name\" yaml:\"name\" json:\"name\"`\n" +
+	"\tCertP12Path string            `mapstructure:\"cert_p12_path\" yaml:\"cert-p12-path\" json:\"cert_p12_path\" validate:\"required\"` // 证书\n" +
+	"\tEndpoint    string            `mapstructure:\"_\" yaml:\"-\" json:\"_\"`\n" +
+	"\tMaxRetries  int               `yaml:\"max-retries\"`\n" +
+	"\tTimeout     int               `mapstructure:\"timeout\" yaml:\"timeout\" json:\"deadline\"`\n" +
+	"\tServiceName string            `mapstructure:\"service-name\" yaml:\"service-name\" json:\"serviceName\"`\n" +
+	"\tService     string            `mapstructure:\"servicename\" yaml:\"servicename\" json:\"servicename\"`\n" +
+	"\tNested      *tagCheckNested   `mapstructure:\"nested\" yaml:\"nested\" json:\"nested\"`\n" +
+	"\tItems       []*tagCheckNested `mapstructure:\"items\" yaml:\"items\" json:\"items\"`\n" +
+	"\tHidden      string            `mapstructure:\"-\" yaml:\"-\" json:\"hidden\"`\n" +
+	"\tRetryDelay  int               `mapstructure:\"retry-delay\" yaml:\"retry-delay\" json:\"retry_delay,omitempty\"`\n" +
+	"\tinternal    string\n" +
+	"}\n\n" +
+	"type tagCheckNested struct {\n" +
+	"\tHostName string `mapstructure:\"hostname\" yaml:\"host-name\" json:\"hostName\"`\n" +
+	"}\n"
+
+func TestTagChecker_Check(t *testing.T) {
+	issues := NewTagChecker("").Check(&tagCheckSample{})
+
+	got := make(map[string]TagIssue, len(issues))
+	for _, issue := range issues {
+		got[fmt.Sprintf("%s/%s", issue.Field, issue.Kind)] = issue
+	}
+	require.Len(t, got, len(issues), "每个字段每类问题只报告一次")
+
+	assert.Equal(t, `mapstructure:"cert-p12-path" yaml:"cert-p12-path" json:"certP12Path" validate:"required"`,
+		got["CertP12Path/mismatch"].SuggestedTag)
+	assert.Equal(t, `mapstructure:"-" yaml:"-" json:"-"`, got["Endpoint/invalid"].SuggestedTag)
+	assert.Equal(t, `mapstructure:"max-retries" yaml:"max-retries" json:"maxRetries"`, got["MaxRetries/missing"].SuggestedTag)
+	assert.Contains(t, got["Timeout/mismatch"].Message, `json "deadline"`)
+	assert.Equal(t, `mapstructure:"timeout" yaml:"timeout" json:"timeout"`, got["Timeout/mismatch"].SuggestedTag)
+	assert.Contains(t, got["RetryDelay/mismatch"].Message, `json "retry_delay" is not camelCase`)
+	assert.Equal(t, `mapstructure:"retry-delay" yaml:"retry-delay" json:"retryDelay,omitempty"`, got["RetryDelay/mismatch"].SuggestedTag)
+	assert.Contains(t, got["Service/duplicate"].Message, "ServiceName")
+	assert.Empty(t, got["Service/duplicate"].SuggestedTag, "重复键无法自动修复")
+	assert.Equal(t, `mapstructure:"host-name" yaml:"host-name" json:"hostName"`, got["HostName/mismatch"].SuggestedTag)
+	assert.Equal(t, "goconfig.tagCheckNested", got["HostName/mismatch"].Type)
+
+	for _, field := range []string{"Name", "Hidden", "Nested", "Items", "internal"} {
+		for key := range got {
+			assert.False(t, strings.HasPrefix(key, field+"/"), "字段 %s 不应报告问题", field)
+		}
+	}
+	assert.Len(t, issues, 7)
+	assert.False(t, issues[0].Fixable(), "未设置源码目录时无法定位")
+}
+
+func TestTagChecker_Fix(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "sample.go")
+	require.NoError(t, os.WriteFile(path, []byte(tagCheckSampleSource), 0644))
+
+	checker := NewTagChecker(root)
+	issues := checker.Check(&tagCheckSample{})
+	require.NotEmpty(t, issues)
+	assert.Equal(t, path, issues[0].File)
+	assert.Equal(t, 5, issues[0].Line, "CertP12Path 位于第 5 行")
+	assert.Contains(t, issues[0].String(), path+":5:")
+
+	files, err := checker.Fix(issues)
+	require.NoError(t, err)
+	assert.Equal(t, []string{path}, files)
+
+	fixed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(fixed)
+	assert.Contains(t, content, "`mapstructure:\"cert-p12-path\" yaml:\"cert-p12-path\" json:\"certP12Path\" validate:\"required\"` // 证书")
+	assert.Contains(t, content, "`mapstructure:\"-\" yaml:\"-\" json:\"-\"`")
+	assert.Contains(t, content, "`mapstructure:\"max-retries\" yaml:\"max-retries\" json:\"maxRetries\"`")
+	assert.Contains(t, content, "`mapstructure:\"host-name\" yaml:\"host-name\" json:\"hostName\"`")
+	assert.Contains(t, content, "`mapstructure:\"retry-delay\" yaml:\"retry-delay\" json:\"retryDelay,omitempty\"`")
+	assert.Contains(t, content, "`mapstructure:\"servicename\" yaml:\"servicename\" json:\"servicename\"`", "重复键保持原样")
+}
+
+func TestToKebabCase(t *testing.T) {
+	cases := map[string]string{
+		"CertP12Path":  "cert-p12-path",
+		"HTTPServer":   "http-server",
+		"MaxRetries":   "max-retries",
+		"service_name": "service-name",
+		"ID":           "id",
+		"jwtSecret":    "jwt-secret",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, toKebabCase(input), input)
+	}
+}
+
+func TestSetTagName(t *testing.T) {
+	assert.Equal(t, `mapstructure:"a-b,omitempty" yaml:"a-b" json:"aB" validate:"required"`,
+		setTagName(`yaml:"a-b" validate:"required"  mapstructure:"a_b,omitempty" json:"aB"`, "mapstructure", "a-b"))
+	assert.Equal(t, `mapstructure:"x" yaml:"x"`, setTagName(`yaml:"x"`, "mapstructure", "x"))
+}
+
+// TestRegisteredModuleTags 所有已注册模块的配置结构体标签保持一致
+func TestRegisteredModuleTags(t *testing.T) {
+	AssertConsistentTags(t)
+}
@@ -0,0 +1,191 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_toml.go
+ * @Description: TOML 输出 - 按 YAML 节点顺序生成 TOML，键名与 YAML 模板保持一致
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// tomlBareKeyPattern TOML 裸键允许的字符
+var tomlBareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// writeTOMLDocument 将映射节点写为 TOML
+// 每张表内先输出键值对，再输出子表与表数组；null 值在 TOML 中没有对应表示，直接省略
+func writeTOMLDocument(node *yaml.Node) string {
+	var sb strings.Builder
+	writeTOMLTable(&sb, nil, resolveAlias(node))
+	return strings.TrimLeft(sb.String(), "\n")
+}
+
+// writeTOMLTable 输出一张表的内容
+func writeTOMLTable(sb *strings.Builder, path []string, node *yaml.Node) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = resolveAlias(node.Content[0])
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+
+	type entry struct {
+		key   string
+		value *yaml.Node
+	}
+	var tables, tableArrays []entry
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		value := resolveAlias(node.Content[i+1])
+		switch {
+		case isYAMLNull(value):
+			continue
+		case value.Kind == yaml.MappingNode && len(value.Content) > 0:
+			tables = append(tables, entry{key, value})
+		case isTOMLTableArray(value):
+			tableArrays = append(tableArrays, entry{key, value})
+		default:
+			fmt.Fprintf(sb, "%s = %s\n", tomlKey(key), tomlInlineValue(value))
+		}
+	}
+
+	for _, table := range tables {
+		childPath := append(append([]string{}, path...), table.key)
+		fmt.Fprintf(sb, "\n[%s]\n", tomlKeyPath(childPath))
+		writeTOMLTable(sb, childPath, table.value)
+	}
+	for _, array := range tableArrays {
+		childPath := append(append([]string{}, path...), array.key)
+		for _, item := range array.value.Content {
+			fmt.Fprintf(sb, "\n[[%s]]\n", tomlKeyPath(childPath))
+			writeTOMLTable(sb, childPath, resolveAlias(item))
+		}
+	}
+}
+
+// isTOMLTableArray 判断序列是否应输出为 [[表数组]]（非空且元素均为映射）
+func isTOMLTableArray(node *yaml.Node) bool {
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return false
+	}
+	for _, item := range node.Content {
+		if resolveAlias(item).Kind != yaml.MappingNode {
+			return false
+		}
+	}
+	return true
+}
+
+// isYAMLNull 判断节点是否为 null
+func isYAMLNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+// tomlInlineValue 输出行内值: 标量、数组或行内表
+func tomlInlineValue(node *yaml.Node) string {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		items := make([]string, 0, len(node.Content))
+		for _, item := range node.Content {
+			if !isYAMLNull(resolveAlias(item)) {
+				items = append(items, tomlInlineValue(item))
+			}
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case yaml.MappingNode:
+		items := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if value := resolveAlias(node.Content[i+1]); !isYAMLNull(value) {
+				items = append(items, tomlKey(node.Content[i].Value)+" = "+tomlInlineValue(value))
+			}
+		}
+		if len(items) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	default:
+		return tomlScalar(node)
+	}
+}
+
+// tomlScalar 按 YAML 标签输出 TOML 标量
+func tomlScalar(node *yaml.Node) string {
+	switch node.Tag {
+	case "!!bool", "!!int", "!!timestamp":
+		return node.Value
+	case "!!float":
+		switch strings.ToLower(node.Value) {
+		case ".inf", "+.inf":
+			return "inf"
+		case "-.inf":
+			return "-inf"
+		case ".nan":
+			return "nan"
+		}
+		return node.Value
+	default:
+		return tomlString(node.Value)
+	}
+}
+
+// tomlKeyPath 拼接表头中的点分键
+func tomlKeyPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlKey 输出键名，非裸键使用引号
+func tomlKey(key string) string {
+	if tomlBareKeyPattern.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlString 输出 TOML 基本字符串
+func tomlString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&sb, `\u%04X`, r)
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,676 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_validator.go
+ * @Description: 配置文件离线校验 - 无需启动应用即可检查配置文件，并定位到文件行列
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	validator "github.com/kamalyes/go-argus"
+	"github.com/kamalyes/go-config/internal"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationSeverity 校验问题级别
+type ValidationSeverity string
+
+const (
+	SeverityError   ValidationSeverity = "error"   // 错误：配置无法使用
+	SeverityWarning ValidationSeverity = "warning" // 警告：如未知配置项
+)
+
+// ValidationIssue 配置文件校验问题
+type ValidationIssue struct {
+	File     string             `json:"file"`           // 文件路径
+	Line     int                `json:"line"`           // 行号（从1开始，0表示无法定位）
+	Column   int                `json:"column"`         // 列号（从1开始，0表示无法定位）
+	Path     string             `json:"path,omitempty"` // 配置键路径，如 http.tls.min-version
+	Rule     string             `json:"rule,omitempty"` // 触发的规则，如 required、min、decode
+	Severity ValidationSeverity `json:"severity"`       // 级别
+	Message  string             `json:"message"`        // 问题描述
+	// Disabled 问题位于文件显式禁用（enabled: false）的模块或子模块内，不计入 ErrorCount
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// String 格式化为 file:line:column: severity: path: message
+func (i ValidationIssue) String() string {
+	var sb strings.Builder
+	sb.WriteString(i.File)
+	if i.Line > 0 {
+		fmt.Fprintf(&sb, ":%d:%d", i.Line, i.Column)
+	}
+	fmt.Fprintf(&sb, ": %s: ", i.Severity)
+	if i.Disabled {
+		sb.WriteString("(disabled) ")
+	}
+	if i.Path != "" {
+		sb.WriteString(i.Path + ": ")
+	}
+	sb.WriteString(i.Message)
+	if i.Rule != "" {
+		fmt.Fprintf(&sb, " [%s]", i.Rule)
+	}
+	return sb.String()
+}
+
+// ConfigFileReport 配置文件校验报告
+type ConfigFileReport struct {
+	File   string            `json:"file"`             // 文件路径
+	Module string            `json:"module,omitempty"` // 目标模块
+	Issues []ValidationIssue `json:"issues"`           // 所有问题
+}
+
+// HasErrors 是否存在错误级别的问题
+func (r *ConfigFileReport) HasErrors() bool {
+	return r.ErrorCount() > 0
+}
+
+// ErrorCount 文件中配置项的错误数量，不含来自默认值的错误
+func (r *ConfigFileReport) ErrorCount() int {
+	return r.count(SeverityError)
+}
+
+// DisabledErrorCount 位于已禁用模块内的错误数量
+func (r *ConfigFileReport) DisabledErrorCount() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == SeverityError && issue.Disabled {
+			n++
+		}
+	}
+	return n
+}
+
+// WarningCount 警告数量
+func (r *ConfigFileReport) WarningCount() int {
+	return r.count(SeverityWarning)
+}
+
+func (r *ConfigFileReport) count(severity ValidationSeverity) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity && !issue.Disabled {
+			n++
+		}
+	}
+	return n
+}
+
+// ToJSON 导出为 JSON（便于 CI 生成注解）
+func (r *ConfigFileReport) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// ValidateConfigFile 离线校验配置文件
+// config 为目标配置结构体指针（通常是模块的 Default()），文件内容会覆盖到其上，
+// 与应用启动时的加载方式一致。校验包括：
+//  1. 语法与类型解码（使用与热更新一致的灵活命名规则）
+//  2. 未知配置项（警告）
+//  3. validate 标签规则
+//  4. 配置自身的 Validate() 方法
+//
+// 所有问题都会尽可能从 YAML 节点定位到文件行列；
+// 文件显式禁用（enabled: false）的模块或子模块内的错误标记为 Disabled，不计入 ErrorCount
+func ValidateConfigFile(filePath string, config any) (*ConfigFileReport, error) {
+	if config == nil {
+		return nil, ErrConfigEmpty
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, ErrReadConfigFile(err)
+	}
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filePath), "."))
+	if ext == "" {
+		return nil, ErrUnsupportedFormat(filepath.Ext(filePath))
+	}
+
+	fv := &fileValidation{report: &ConfigFileReport{File: filePath, Issues: []ValidationIssue{}}}
+
+	// YAML/JSON 解析为节点树，用于定位行列
+	if ext == "yaml" || ext == "yml" || ext == "json" {
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			fv.addIssue(ValidationIssue{Line: yamlErrorLine(err), Column: 1, Rule: "syntax", Severity: SeverityError, Message: err.Error()})
+			return fv.finish(), nil
+		}
+		fv.root = &root
+	}
+
+	v := newViper()
+	v.SetConfigType(ext)
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		fv.addIssue(ValidationIssue{Rule: "syntax", Severity: SeverityError, Message: err.Error()})
+		return fv.finish(), nil
+	}
+
+	fv.settings = v.AllSettings()
+
+	// 解码，与 UnmarshalWithFlexibleNaming 保持相同的命名规则
+	fv.collectDecodeErrors(UnmarshalWithFlexibleNaming(v, config))
+	fv.collectUnknownKeys(fv.settings, reflect.TypeOf(config), nil)
+
+	// validate 标签规则
+	fv.walkTags(reflect.ValueOf(config), nil)
+
+	// 配置自身的校验逻辑（标签校验错误已在上面逐项报告）
+	if validatable, ok := config.(interface{ Validate() error }); ok {
+		if err := validatable.Validate(); err != nil {
+			var ve validator.ValidationErrors
+			if !errors.As(err, &ve) {
+				fv.addIssue(ValidationIssue{Rule: "Validate", Severity: SeverityError, Message: err.Error()})
+			}
+		}
+	}
+
+	return fv.finish(), nil
+}
+
+// ValidateConfigFile 按模块名称离线校验配置文件
+func (sg *SmartConfigGenerator) ValidateConfigFile(filePath, moduleName string) (*ConfigFileReport, error) {
+	module, exists := sg.ModuleRegistry[moduleName]
+	if !exists {
+		return nil, ErrModuleNotFound(moduleName)
+	}
+	return validateModuleConfigFile(filePath, module)
+}
+
+// ValidateModuleConfigFile 按全局模块注册表中的模块名称离线校验配置文件，无需创建配置生成器
+func ValidateModuleConfigFile(filePath, moduleName string) (*ConfigFileReport, error) {
+	module, exists := LookupModule(moduleName)
+	if !exists {
+		return nil, ErrModuleNotFound(moduleName)
+	}
+	return validateModuleConfigFile(filePath, module)
+}
+
+// validateModuleConfigFile 以模块的 Default() 为基础校验配置文件
+func validateModuleConfigFile(filePath string, module ModuleConfig) (*ConfigFileReport, error) {
+	moduleName := module.Name
+	if module.DefaultFunc == nil {
+		return nil, ErrModuleDefaultFuncNil(moduleName)
+	}
+
+	config := module.DefaultFunc()
+	if config == nil {
+		return nil, ErrModuleConfigEmpty(moduleName)
+	}
+	if reflect.TypeOf(config).Kind() != reflect.Ptr {
+		ptr := reflect.New(reflect.TypeOf(config))
+		ptr.Elem().Set(reflect.ValueOf(config))
+		config = ptr.Interface()
+	}
+
+	report, err := ValidateConfigFile(filePath, config)
+	if err != nil {
+		return nil, err
+	}
+	report.Module = moduleName
+	return report, nil
+}
+
+// fileValidation 单个文件的校验上下文
+type fileValidation struct {
+	report   *ConfigFileReport
+	root     *yaml.Node     // YAML 节点树（非 YAML/JSON 文件时为 nil）
+	settings map[string]any // 文件中的配置项，用于识别显式禁用的模块
+}
+
+// addIssue 添加问题
+func (fv *fileValidation) addIssue(issue ValidationIssue) {
+	issue.File = fv.report.File
+	fv.report.Issues = append(fv.report.Issues, issue)
+}
+
+// addPathIssue 添加与配置路径相关的问题，并定位到文件行列
+func (fv *fileValidation) addPathIssue(path []string, rule string, severity ValidationSeverity, message string) {
+	issue := ValidationIssue{Path: formatConfigPath(path), Rule: rule, Severity: severity, Message: message}
+	if node, _ := locateYAMLNode(fv.root, path); node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	issue.Disabled = severity == SeverityError && sectionDisabled(fv.settings, path)
+	fv.addIssue(issue)
+}
+
+// sectionDisabled 路径所在的模块或任一上级子模块是否在文件中显式设置了 enabled: false（键名匹配规则与解码一致）
+func sectionDisabled(settings map[string]any, path []string) bool {
+	var value any = settings
+	for i := 0; i < len(path); i++ {
+		switch node := value.(type) {
+		case map[string]any:
+			for key, child := range node {
+				if FlexibleMatchName(key, "enabled") {
+					if enabled, err := strconv.ParseBool(fmt.Sprint(child)); err == nil && !enabled {
+						return true
+					}
+				}
+			}
+			found := false
+			for key, child := range node {
+				if FlexibleMatchName(key, path[i]) {
+					value, found = child, true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		case []any:
+			idx, err := strconv.Atoi(path[i])
+			if err != nil || idx < 0 || idx >= len(node) {
+				return false
+			}
+			value = node[idx]
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// finish 按位置排序并返回报告
+func (fv *fileValidation) finish() *ConfigFileReport {
+	sort.SliceStable(fv.report.Issues, func(i, j int) bool {
+		a, b := fv.report.Issues[i], fv.report.Issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return fv.report
+}
+
+// collectDecodeErrors 展开 mapstructure 的聚合错误，逐字段报告
+func (fv *fileValidation) collectDecodeErrors(err error) {
+	if err == nil {
+		return
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			fv.collectDecodeErrors(e)
+		}
+		return
+	}
+
+	var decodeErr *mapstructure.DecodeError
+	if errors.As(err, &decodeErr) {
+		// 嵌套结构体的错误也是聚合错误，优先报告最内层字段
+		if inner := errors.Unwrap(decodeErr); inner != nil {
+			if _, ok := inner.(interface{ Unwrap() []error }); ok {
+				fv.collectDecodeErrors(inner)
+				return
+			}
+			fv.addPathIssue(parseConfigPath(decodeErr.Name()), "decode", SeverityError, inner.Error())
+			return
+		}
+	}
+
+	fv.addIssue(ValidationIssue{Rule: "decode", Severity: SeverityError, Message: err.Error()})
+}
+
+// collectUnknownKeys 对照目标结构查找文件中无法匹配任何字段的配置项
+// 匹配规则与解码时的 FlexibleMatchName 一致
+func (fv *fileValidation) collectUnknownKeys(value any, t reflect.Type, path []string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		settings, ok := value.(map[string]any)
+		if !ok || t == timeType {
+			return
+		}
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if len(path) == 0 && key == ConfigVersionKey {
+				continue // 迁移版本记录，不属于配置结构
+			}
+			field, found := matchDecodeField(t, key)
+			if !found {
+				fv.addPathIssue(appendPath(path, key), "unknown", SeverityWarning, "未知配置项，将被忽略")
+				continue
+			}
+			fv.collectUnknownKeys(settings[key], field.Type, appendPath(path, key))
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			fv.collectUnknownKeys(item, t.Elem(), appendPath(path, strconv.Itoa(i)))
+		}
+	case reflect.Map:
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		for key, entry := range entries {
+			fv.collectUnknownKeys(entry, t.Elem(), appendPath(path, key))
+		}
+	}
+}
+
+// matchDecodeField 按 mapstructure 的匹配方式查找字段（含 squash 展开的嵌入字段）
+func matchDecodeField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tagName, opts := splitTag(field.Tag.Get("mapstructure"))
+		if tagName == "-" {
+			continue
+		}
+		if hasTagOption(opts, "squash") {
+			inner := field.Type
+			for inner.Kind() == reflect.Ptr {
+				inner = inner.Elem()
+			}
+			if inner.Kind() == reflect.Struct {
+				if f, ok := matchDecodeField(inner, key); ok {
+					return f, true
+				}
+			}
+			continue
+		}
+		if tagName == "" {
+			tagName = field.Name
+		}
+		if FlexibleMatchName(key, tagName) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// walkTags 遍历配置结构，按字段的 validate 标签及已注册的枚举值逐项校验
+func (fv *fileValidation) walkTags(v reflect.Value, path []string) {
+	if enum := lookupSchemaEnum(v.Type()); enum != nil && !v.IsZero() {
+		fv.checkEnum(v, enum, path)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			fv.walkTags(v.Elem(), path)
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, inline, skip := schemaFieldName(field)
+			if skip {
+				continue
+			}
+
+			value := v.Field(i)
+			switch value.Kind() {
+			case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+				continue
+			}
+
+			fieldPath := path
+			if !inline {
+				fieldPath = appendPath(path, name)
+			}
+
+			if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+				fv.checkTag(value, tag, fieldPath)
+			}
+			fv.walkTags(value, fieldPath)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fv.walkTags(v.Index(i), appendPath(path, strconv.Itoa(i)))
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			fv.walkTags(v.MapIndex(key), appendPath(path, fmt.Sprint(key.Interface())))
+		}
+	}
+}
+
+// checkTag 校验单个字段的 validate 标签
+func (fv *fileValidation) checkTag(value reflect.Value, tag string, path []string) {
+	err := internal.ValidateVar(value.Interface(), tag)
+	if err == nil {
+		return
+	}
+
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		fv.addPathIssue(path, "validate", SeverityError, err.Error())
+		return
+	}
+	for _, fe := range ve {
+		// dive 规则的错误命名空间形如 [0] / [key]，指向具体元素
+		issuePath := path
+		if ns := fe.Namespace(); strings.HasPrefix(ns, "[") {
+			issuePath = append(appendPath(path), parseConfigPath(ns)...)
+		}
+		fv.addPathIssue(issuePath, fe.Tag(), SeverityError, validationRuleMessage(fe.Tag(), fe.Param()))
+	}
+}
+
+// checkEnum 校验枚举类型字段的取值
+func (fv *fileValidation) checkEnum(v reflect.Value, enum []any, path []string) {
+	actual := plainValue(v)
+	allowed := make([]string, 0, len(enum))
+	for _, value := range enum {
+		if value == actual {
+			return
+		}
+		allowed = append(allowed, fmt.Sprint(value))
+	}
+	fv.addPathIssue(path, "enum", SeverityError, fmt.Sprintf("无效的取值 %v，必须是以下值之一: %s", actual, strings.Join(allowed, ", ")))
+}
+
+// validationRuleMessage 生成可读的规则失败描述
+func validationRuleMessage(tag, param string) string {
+	switch tag {
+	case "required":
+		return "必填项不能为空"
+	case "min", "gte":
+		return fmt.Sprintf("不能小于 %s", param)
+	case "max", "lte":
+		return fmt.Sprintf("不能大于 %s", param)
+	case "gt":
+		return fmt.Sprintf("必须大于 %s", param)
+	case "lt":
+		return fmt.Sprintf("必须小于 %s", param)
+	case "len":
+		return fmt.Sprintf("长度必须为 %s", param)
+	case "oneof":
+		return fmt.Sprintf("必须是以下值之一: %s", strings.Join(strings.Fields(param), ", "))
+	case "url", "uri", "http_url":
+		return "必须是合法的URL"
+	case "email":
+		return "必须是合法的邮箱地址"
+	}
+	if param != "" {
+		return fmt.Sprintf("不满足规则 %s=%s", tag, param)
+	}
+	return fmt.Sprintf("不满足规则 %s", tag)
+}
+
+// appendPath 复制并追加路径段，避免共享底层数组
+func appendPath(path []string, segments ...string) []string {
+	result := make([]string, 0, len(path)+len(segments))
+	result = append(result, path...)
+	return append(result, segments...)
+}
+
+// parseConfigPath 解析 mapstructure/validator 风格的路径: a.b[0].c / [key]
+func parseConfigPath(path string) []string {
+	segments := make([]string, 0)
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				segments = append(segments, part)
+				break
+			}
+			if open > 0 {
+				segments = append(segments, part[:open])
+			}
+			closeIdx := strings.IndexByte(part[open:], ']')
+			if closeIdx < 0 {
+				segments = append(segments, part[open+1:])
+				break
+			}
+			segments = append(segments, part[open+1:open+closeIdx])
+			part = part[open+closeIdx+1:]
+		}
+	}
+	return segments
+}
+
+// formatConfigPath 格式化路径: 数字段显示为 [i]
+func formatConfigPath(path []string) string {
+	var sb strings.Builder
+	for i, seg := range path {
+		if _, err := strconv.Atoi(seg); err == nil && i > 0 {
+			sb.WriteString("[" + seg + "]")
+			continue
+		}
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(seg)
+	}
+	return sb.String()
+}
+
+// configKeyEqual 按灵活命名规则比较键名（忽略大小写、- 与 _）
+func configKeyEqual(a, b string) bool {
+	return normalizeConfigKey(a) == normalizeConfigKey(b)
+}
+
+// normalizeConfigKey 归一化键名: 小写并去掉 - 和 _
+func normalizeConfigKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("-", "", "_", "").Replace(key)
+}
+
+// locateYAMLNode 按路径在 YAML 节点树中查找节点
+// 返回找到的最深节点（映射项返回键节点）以及是否完整匹配，首段即未匹配时返回 nil
+func locateYAMLNode(root *yaml.Node, path []string) (*yaml.Node, bool) {
+	if root == nil {
+		return nil, false
+	}
+
+	node := root
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil, false
+		}
+		node = node.Content[0]
+	}
+
+	var found *yaml.Node
+	if len(path) == 0 {
+		found = node
+	}
+	for _, seg := range path {
+		node = resolveAlias(node)
+		switch node.Kind {
+		case yaml.MappingNode:
+			key, value := findMappingEntry(node, seg)
+			if key == nil {
+				return found, false
+			}
+			found, node = key, value
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return found, false
+			}
+			node = node.Content[idx]
+			found = node
+		default:
+			return found, false
+		}
+	}
+	return found, true
+}
+
+// findMappingEntry 在映射节点中查找键（支持 << 合并键）
+func findMappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if configKeyEqual(mapping.Content[i].Value, key) {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != "<<" {
+			continue
+		}
+		merge := resolveAlias(mapping.Content[i+1])
+		sources := []*yaml.Node{merge}
+		if merge.Kind == yaml.SequenceNode {
+			sources = merge.Content
+		}
+		for _, source := range sources {
+			if source = resolveAlias(source); source.Kind == yaml.MappingNode {
+				if k, v := findMappingEntry(source, key); k != nil {
+					return k, v
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// resolveAlias 解析别名节点
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+var yamlErrorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// yamlErrorLine 从 YAML 解析错误中提取行号
+func yamlErrorLine(err error) int {
+	if match := yamlErrorLinePattern.FindStringSubmatch(err.Error()); len(match) == 2 {
+		line, _ := strconv.Atoi(match[1])
+		return line
+	}
+	return 0
+}
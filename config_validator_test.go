@@ -0,0 +1,295 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\config_validator_test.go
+ * @Description: 配置文件离线校验测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package goconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kamalyes/go-config/internal"
+	"github.com/kamalyes/go-config/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatorTestItem struct {
+	Name string `mapstructure:"name" yaml:"name" validate:"required"`
+}
+
+type validatorTestConfig struct {
+	ServiceName string              `mapstructure:"service-name" yaml:"service-name" validate:"required"`
+	Port        int                 `mapstructure:"port" yaml:"port" validate:"min=1,max=65535"`
+	Level       string              `mapstructure:"level" yaml:"level" validate:"oneof=debug info"`
+	Strategy    ratelimit.Strategy  `mapstructure:"strategy" yaml:"strategy"`
+	Items       []validatorTestItem `mapstructure:"items" yaml:"items" validate:"dive"`
+	Tags        []string            `mapstructure:"tags" yaml:"tags" validate:"dive,required"`
+	Fail        bool                `mapstructure:"fail" yaml:"fail"`
+}
+
+func (c *validatorTestConfig) Validate() error {
+	if err := internal.ValidateStruct(c); err != nil {
+		return err
+	}
+	if c.Fail {
+		return errors.New("custom failure")
+	}
+	return nil
+}
+
+func writeValidatorFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func findIssue(report *ConfigFileReport, path string) *ValidationIssue {
+	for i := range report.Issues {
+		if report.Issues[i].Path == path {
+			return &report.Issues[i]
+		}
+	}
+	return nil
+}
+
+func TestValidateConfigFile_Valid(t *testing.T) {
+	path := writeValidatorFile(t, "ok.yaml", `
+service_name: demo
+port: 8080
+level: info
+strategy: token-bucket
+items:
+  - name: a
+tags: [x]
+`)
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+	assert.False(t, report.HasErrors())
+}
+
+func TestValidateConfigFile_ReportsPositions(t *testing.T) {
+	path := writeValidatorFile(t, "bad.yaml", `service-name: demo
+port: 70000
+level: trace
+strategy: bogus
+items:
+  - name: a
+  - name: ""
+tags:
+  - ok
+  - ""
+unknown: 1
+`)
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+
+	port := findIssue(report, "port")
+	require.NotNil(t, port)
+	assert.Equal(t, "max", port.Rule)
+	assert.Equal(t, 2, port.Line)
+	assert.Equal(t, 1, port.Column)
+	assert.Equal(t, path, port.File)
+
+	level := findIssue(report, "level")
+	require.NotNil(t, level)
+	assert.Equal(t, "oneof", level.Rule)
+	assert.Equal(t, 3, level.Line)
+
+	strategy := findIssue(report, "strategy")
+	require.NotNil(t, strategy)
+	assert.Equal(t, "enum", strategy.Rule)
+	assert.Equal(t, 4, strategy.Line)
+
+	item := findIssue(report, "items[1].name")
+	require.NotNil(t, item)
+	assert.Equal(t, "required", item.Rule)
+	assert.Equal(t, 7, item.Line)
+	assert.Equal(t, 5, item.Column)
+
+	tag := findIssue(report, "tags[1]")
+	require.NotNil(t, tag)
+	assert.Equal(t, 10, tag.Line)
+
+	unknown := findIssue(report, "unknown")
+	require.NotNil(t, unknown)
+	assert.Equal(t, SeverityWarning, unknown.Severity)
+	assert.Equal(t, 11, unknown.Line)
+
+	assert.True(t, report.HasErrors())
+	assert.Equal(t, 1, report.WarningCount())
+}
+
+func TestValidateConfigFile_CustomValidate(t *testing.T) {
+	path := writeValidatorFile(t, "custom.yaml", "service-name: demo\nport: 80\nlevel: info\nfail: true\n")
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "Validate", report.Issues[0].Rule)
+	assert.Equal(t, "custom failure", report.Issues[0].Message)
+}
+
+func TestValidateConfigFile_DecodeAndMissing(t *testing.T) {
+	path := writeValidatorFile(t, "decode.yaml", `port: abc
+items:
+  - name: x
+`)
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+
+	port := findIssue(report, "port")
+	require.NotNil(t, port)
+	assert.Equal(t, "decode", port.Rule)
+	assert.Equal(t, 1, port.Line)
+
+	// 缺失的必填项无法定位到具体行
+	missing := findIssue(report, "service-name")
+	require.NotNil(t, missing)
+	assert.Equal(t, "required", missing.Rule)
+	assert.Zero(t, missing.Line)
+	assert.False(t, missing.Disabled, "未禁用模块中缺失的必填项计入错误")
+	assert.True(t, report.HasErrors())
+}
+
+type validatorTestSub struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+	Token   string `mapstructure:"token" yaml:"token" validate:"required"`
+}
+
+type validatorTestModule struct {
+	Enabled bool              `mapstructure:"enabled" yaml:"enabled"`
+	Name    string            `mapstructure:"name" yaml:"name" validate:"required"`
+	Sub     *validatorTestSub `mapstructure:"sub" yaml:"sub"`
+}
+
+func TestValidateConfigFile_DisabledSections(t *testing.T) {
+	// 子模块显式禁用：其中的错误不计入，模块本身的错误照常计入
+	path := writeValidatorFile(t, "module.yaml", "enabled: true\nsub:\n  enabled: false\n")
+	report, err := ValidateConfigFile(path, &validatorTestModule{Sub: &validatorTestSub{}})
+	require.NoError(t, err)
+	token := findIssue(report, "sub.token")
+	require.NotNil(t, token)
+	assert.True(t, token.Disabled)
+	assert.Contains(t, token.String(), "error: (disabled) sub.token")
+	name := findIssue(report, "name")
+	require.NotNil(t, name)
+	assert.False(t, name.Disabled)
+	assert.Equal(t, 1, report.ErrorCount())
+	assert.Equal(t, 1, report.DisabledErrorCount())
+
+	// 整个模块显式禁用
+	path = writeValidatorFile(t, "module.yaml", "enabled: false\n")
+	report, err = ValidateConfigFile(path, &validatorTestModule{Sub: &validatorTestSub{}})
+	require.NoError(t, err)
+	assert.False(t, report.HasErrors())
+	assert.Equal(t, 2, report.DisabledErrorCount())
+}
+
+func TestValidateModuleConfigFile(t *testing.T) {
+	// 已配置的数据库模块中，默认 cockroachdb 配置块缺少密码仍属于错误
+	path := writeValidatorFile(t, "database.yaml", "mysql:\n  host: db\n")
+	report, err := ValidateModuleConfigFile(path, "database")
+	require.NoError(t, err)
+	assert.Equal(t, "database", report.Module)
+	assert.True(t, report.HasErrors())
+	password := findIssue(report, "cockroachdb.password")
+	require.NotNil(t, password)
+	assert.False(t, password.Disabled)
+
+	path = writeValidatorFile(t, "database.yaml", "enabled: false\nmysql:\n  host: db\n")
+	report, err = ValidateModuleConfigFile(path, "database")
+	require.NoError(t, err)
+	assert.False(t, report.HasErrors())
+	assert.Positive(t, report.DisabledErrorCount())
+
+	_, err = ValidateModuleConfigFile(path, "no-such-module")
+	assert.Error(t, err)
+}
+
+func TestValidateConfigFile_SyntaxError(t *testing.T) {
+	path := writeValidatorFile(t, "syntax.yaml", "port: 1\nlevel: [info\n")
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, "syntax", report.Issues[0].Rule)
+	assert.Positive(t, report.Issues[0].Line)
+}
+
+func TestValidateConfigFile_JSON(t *testing.T) {
+	path := writeValidatorFile(t, "cfg.json", `{
+  "serviceName": "demo",
+  "port": 0
+}`)
+	report, err := ValidateConfigFile(path, &validatorTestConfig{})
+	require.NoError(t, err)
+
+	port := findIssue(report, "port")
+	require.NotNil(t, port)
+	assert.Equal(t, 3, port.Line)
+	assert.Nil(t, findIssue(report, "serviceName"), "camelCase 键应按灵活命名规则匹配")
+
+	data, err := report.ToJSON()
+	require.NoError(t, err)
+	var decoded ConfigFileReport
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, len(report.Issues), len(decoded.Issues))
+}
+
+func TestValidateConfigFile_Errors(t *testing.T) {
+	_, err := ValidateConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), &validatorTestConfig{})
+	assert.Error(t, err)
+
+	_, err = ValidateConfigFile("whatever.yaml", nil)
+	assert.ErrorIs(t, err, ErrConfigEmpty)
+
+	generator := NewSmartConfigGenerator(t.TempDir())
+	_, err = generator.ValidateConfigFile("whatever.yaml", "no-such-module")
+	assert.Error(t, err)
+}
+
+func TestSmartConfigGenerator_ValidateConfigFile(t *testing.T) {
+	path := writeValidatorFile(t, "ratelimit.yaml", `enabled: true
+strategy: leaky-bucket
+global-limit:
+  requests-per-second: 10
+  burst-sise: 20
+`)
+	generator := NewSmartConfigGenerator(t.TempDir())
+	report, err := generator.ValidateConfigFile(path, "ratelimit")
+	require.NoError(t, err)
+	assert.Equal(t, "ratelimit", report.Module)
+	assert.False(t, report.HasErrors())
+
+	typo := findIssue(report, "global-limit.burst-sise")
+	require.NotNil(t, typo)
+	assert.Equal(t, SeverityWarning, typo.Severity)
+	assert.Equal(t, 5, typo.Line)
+	assert.Equal(t, 3, typo.Column)
+}
+
+func TestValidationIssue_String(t *testing.T) {
+	issue := ValidationIssue{File: "a.yaml", Line: 3, Column: 5, Path: "http.port", Rule: "min", Severity: SeverityError, Message: "不能小于 1"}
+	assert.Equal(t, "a.yaml:3:5: error: http.port: 不能小于 1 [min]", issue.String())
+
+	issue = ValidationIssue{File: "a.yaml", Severity: SeverityError, Message: "boom"}
+	assert.Equal(t, "a.yaml: error: boom", issue.String())
+}
+
+func TestParseConfigPath(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "0", "c"}, parseConfigPath("a.b[0].c"))
+	assert.Equal(t, []string{"tasks", "job", "breaker"}, parseConfigPath("tasks[job].breaker"))
+	assert.Equal(t, []string{"1"}, parseConfigPath("[1]"))
+	assert.Equal(t, "items[1].name", formatConfigPath([]string{"items", "1", "name"}))
+}
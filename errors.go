@@ -14,6 +14,7 @@ package goconfig
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // 预定义的基础错误（无参数的错误）
@@ -28,6 +29,9 @@ var (
 	ErrManagerNotRunning  = errors.New("集成配置管理器未运行")
 	ErrReloaderRunning    = errors.New("热更新器已经在运行")
 	ErrReloaderNotRunning = errors.New("热更新器未运行")
+
+	// 模块注册相关
+	ErrModuleNameEmpty = errors.New("模块名称不能为空")
 )
 
 // 环境管理相关错误
@@ -86,6 +90,21 @@ func ErrModuleValidationFailed(failedModules []string) error {
 	return fmt.Errorf("以下模块配置验证失败: %v", failedModules)
 }
 
+// ErrModuleDuplicate 模块重复注册错误
+func ErrModuleDuplicate(moduleName string) error {
+	return fmt.Errorf("模块 %s 已注册，不能重复注册", moduleName)
+}
+
+// ErrModuleDependencyMissing 模块依赖未注册错误
+func ErrModuleDependencyMissing(moduleName, dependency string) error {
+	return fmt.Errorf("模块 %s 依赖的模块 %s 未注册", moduleName, dependency)
+}
+
+// ErrModuleDependencyCycle 模块依赖存在环错误
+func ErrModuleDependencyCycle(cycle []string) error {
+	return fmt.Errorf("模块依赖存在循环: %s", strings.Join(cycle, " -> "))
+}
+
 // 任务配置相关错误
 
 // ErrInvalidTimezone 无效的时区错误
@@ -296,3 +315,109 @@ func ErrAutoDiscoverConfigFiles(err error) error {
 
 // ErrCallbackFuncNil 回调函数不能为空错误
 var ErrCallbackFuncNil = errors.New("回调函数不能为空")
+
+// 配置模式相关错误
+
+// ErrSchemaUnsupportedType 不支持生成模式的类型错误
+func ErrSchemaUnsupportedType(t any) error {
+	return fmt.Errorf("不支持为类型 %v 生成JSON Schema，仅支持结构体或结构体指针", t)
+}
+
+// ErrGenerateSchema 生成JSON Schema失败错误
+func ErrGenerateSchema(err error) error {
+	return fmt.Errorf("生成JSON Schema失败: %w", err)
+}
+
+// ErrWriteSchema 写入JSON Schema文件失败错误
+func ErrWriteSchema(err error) error {
+	return fmt.Errorf("写入JSON Schema文件失败: %w", err)
+}
+
+// 配置编辑与迁移相关错误
+
+// ErrParseYAMLDocument 解析YAML文档失败错误
+func ErrParseYAMLDocument(err error) error {
+	return fmt.Errorf("解析YAML文档失败: %w", err)
+}
+
+// ErrEncodeYAMLDocument 编码YAML文档失败错误
+func ErrEncodeYAMLDocument(err error) error {
+	return fmt.Errorf("编码YAML文档失败: %w", err)
+}
+
+// ErrInvalidConfigPath 无效的配置键路径错误
+func ErrInvalidConfigPath(path string) error {
+	return fmt.Errorf("无效的配置键路径: '%s'", path)
+}
+
+// ErrConfigPathConflict 配置键路径与现有节点类型冲突错误
+func ErrConfigPathConflict(path string) error {
+	return fmt.Errorf("配置键路径 '%s' 处已存在非映射节点，无法继续写入", path)
+}
+
+// ErrConfigKeyExists 目标配置键已存在错误
+func ErrConfigKeyExists(path string) error {
+	return fmt.Errorf("目标配置键 '%s' 已存在", path)
+}
+
+// ErrEditThroughAlias 无法通过别名编辑配置错误
+func ErrEditThroughAlias(path string) error {
+	return fmt.Errorf("配置键路径 '%s' 经过YAML别名，请直接编辑锚点定义处", path)
+}
+
+// ErrMigrationInvalid 迁移定义无效错误
+func ErrMigrationInvalid(module, from, to string) error {
+	return fmt.Errorf("模块 %s 的迁移 %s -> %s 无效: 版本不能为空且不能相同，Apply 不能为空", module, from, to)
+}
+
+// ErrMigrationDuplicate 迁移重复注册错误
+func ErrMigrationDuplicate(module, from string) error {
+	return fmt.Errorf("模块 %s 已注册从版本 %s 开始的迁移", module, from)
+}
+
+// ErrMigrationCycle 迁移链存在循环错误
+func ErrMigrationCycle(module, version string) error {
+	return fmt.Errorf("模块 %s 的迁移链在版本 %s 处形成循环", module, version)
+}
+
+// ErrMigrationTargetUnreachable 无法迁移到目标版本错误
+func ErrMigrationTargetUnreachable(module, from, to string) error {
+	return fmt.Errorf("模块 %s 无法从版本 %s 迁移到版本 %s", module, from, to)
+}
+
+// ErrMigrationApply 执行迁移失败错误
+func ErrMigrationApply(module, from, to string, err error) error {
+	return fmt.Errorf("模块 %s 执行迁移 %s -> %s 失败: %w", module, from, to, err)
+}
+
+// 配置格式相关错误
+
+// ErrParseProperties 解析properties文件失败错误
+func ErrParseProperties(line int, err error) error {
+	return fmt.Errorf("解析properties第 %d 行失败: %w", line, err)
+}
+
+// ErrGenerateTOML 生成TOML配置失败错误
+func ErrGenerateTOML(err error) error {
+	return fmt.Errorf("生成TOML配置失败: %w", err)
+}
+
+// ErrGenerateProperties 生成properties配置失败错误
+func ErrGenerateProperties(err error) error {
+	return fmt.Errorf("生成properties配置失败: %w", err)
+}
+
+// ErrWriteConfigOutput 写入生成的配置文件失败错误
+func ErrWriteConfigOutput(format string, err error) error {
+	return fmt.Errorf("写入%s配置文件失败: %w", format, err)
+}
+
+// ErrUnsupportedConfigFormat 不支持的配置格式错误
+func ErrUnsupportedConfigFormat(format string) error {
+	return fmt.Errorf("不支持的配置格式: %s（支持 yaml、json、toml、properties）", format)
+}
+
+// ErrRoundTripFailed 配置往返校验失败错误
+func ErrRoundTripFailed(failed []string) error {
+	return fmt.Errorf("以下模块/格式往返校验失败: %s", strings.Join(failed, ", "))
+}
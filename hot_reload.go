@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/kamalyes/go-config/internal"
 	"github.com/kamalyes/go-logger"
 	"github.com/kamalyes/go-toolbox/pkg/syncx"
 	"github.com/spf13/viper"
@@ -74,6 +75,8 @@ type hotReloadManager struct {
 	running         bool               // 是否运行中
 	cancel          context.CancelFunc // 取消函数
 	configPath      string             // 配置文件路径
+	realPath        string             // 配置文件解析符号链接后的实际路径
+	targetDir       string             // 实际路径所在目录，不同于配置文件目录时额外监控
 	debounceTimer   *time.Timer        // 防抖定时器
 }
 
@@ -172,7 +175,8 @@ func (h *hotReloadManager) Start(ctx context.Context) error {
 		if err != nil {
 			return ErrAddWatcher(err)
 		}
-		logger.GetGlobalLogger().Info("监控的配置文件: %s", absPath)
+		h.resolveRealPath()
+		logger.GetGlobalLogger().Info("监控的配置文件: %s (实际路径: %s)", absPath, h.realPath)
 	}
 
 	h.running = true
@@ -248,10 +252,12 @@ func (h *hotReloadManager) SetConfig(config interface{}) error {
 
 	oldConfig := h.config
 	h.config = config
+	internal.RunApplyHooks(config)
 
 	// 触发配置变更回调
 	event := CreateEvent(CallbackTypeReloaded, "manual", oldConfig, config)
 	event.WithMetadata("manual", true)
+	attachChanges(&event, oldConfig, config)
 
 	syncx.Go().
 		OnPanic(func(r any) {
@@ -323,6 +329,11 @@ func (h *hotReloadManager) runEventLoop(ctx context.Context) {
 
 // handleFileEvent 处理文件事件
 func (h *hotReloadManager) handleFileEvent(ctx context.Context, event fsnotify.Event) {
+	// 仅权限变化不影响配置内容
+	if event.Op == fsnotify.Chmod {
+		return
+	}
+
 	// 获取事件文件的绝对路径
 	eventPath, err := filepath.Abs(event.Name)
 	if err != nil {
@@ -330,30 +341,71 @@ func (h *hotReloadManager) handleFileEvent(ctx context.Context, event fsnotify.E
 		return
 	}
 
-	// 只处理我们监控的配置文件的事件
-	if eventPath != h.configPath {
+	// 配置文件或其实际文件的事件：处理写入、创建和重命名事件（编辑器通常使用重命名来保存文件）
+	if eventPath == h.configPath || (h.realPath != "" && eventPath == h.realPath) {
+		if event.Op&fsnotify.Write == fsnotify.Write ||
+			event.Op&fsnotify.Create == fsnotify.Create ||
+			event.Op&fsnotify.Rename == fsnotify.Rename {
+			h.resolveRealPath()
+			h.scheduleReload(ctx, event.Name)
+		}
 		return
 	}
 
-	// 处理写入、创建和重命名事件（编辑器通常使用重命名来保存文件）
-	if event.Op&fsnotify.Write == fsnotify.Write ||
-		event.Op&fsnotify.Create == fsnotify.Create ||
-		event.Op&fsnotify.Rename == fsnotify.Rename {
+	// 其他路径的事件：Kubernetes 通过原子替换 ..data 符号链接更新 ConfigMap/Secret，
+	// 配置文件路径上不会产生事件，重新解析实际路径判断配置文件是否已指向新文件
+	if h.resolveRealPath() {
+		logger.GetGlobalLogger().InfoContext(ctx, "🔗 配置文件符号链接已切换: %s -> %s", h.configPath, h.realPath)
+		h.scheduleReload(ctx, h.configPath)
+	}
+}
 
-		// 使用锁保护防抖处理
-		h.mu.Lock()
-		if h.debounceTimer != nil {
-			h.debounceTimer.Stop()
+// scheduleReload 防抖后重新加载配置，防抖期间的多次变更只触发一次加载
+func (h *hotReloadManager) scheduleReload(ctx context.Context, source string) {
+	// 使用锁保护防抖处理
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.debounceTimer != nil {
+		h.debounceTimer.Stop()
+	}
+
+	h.debounceTimer = time.AfterFunc(h.hotConfig.DebounceDelay, func() {
+		logger.GetGlobalLogger().InfoContext(ctx, "📁 配置文件发生变化，开始重新加载: %s", source)
+		if err := h.reloadConfig(ctx, source); err != nil {
+			logger.GetGlobalLogger().ErrorContext(ctx, "重新加载配置失败: %v", err)
 		}
+	})
+}
 
-		h.debounceTimer = time.AfterFunc(h.hotConfig.DebounceDelay, func() {
-			logger.GetGlobalLogger().InfoContext(ctx, "📁 配置文件发生变化，开始重新加载: %s", event.Name)
-			if err := h.reloadConfig(ctx, event.Name); err != nil {
-				logger.GetGlobalLogger().ErrorContext(ctx, "重新加载配置失败: %v", err)
-			}
-		})
-		h.mu.Unlock()
+// resolveRealPath 解析配置文件的符号链接并记录实际路径，返回实际路径是否相对上次解析发生变化
+//
+// 实际文件位于其他目录时额外监控该目录，以便捕获对实际文件的直接修改；切换后移除旧目录的监控
+func (h *hotReloadManager) resolveRealPath() bool {
+	realPath, err := filepath.EvalSymlinks(h.configPath)
+	if err != nil || realPath == h.realPath {
+		return false
+	}
+	changed := h.realPath != ""
+	h.realPath = realPath
+
+	targetDir := filepath.Dir(realPath)
+	if targetDir == h.targetDir {
+		return changed
+	}
+	if h.targetDir != "" {
+		// 旧目录可能已被删除，监控随之失效，忽略错误
+		_ = h.watcher.Remove(h.targetDir)
+		h.targetDir = ""
+	}
+	configDir := filepath.Dir(h.configPath)
+	if realDir, err := filepath.EvalSymlinks(configDir); err == nil && realDir != targetDir && configDir != targetDir {
+		if err := h.watcher.Add(targetDir); err != nil {
+			logger.GetGlobalLogger().Warn("监控配置文件实际目录失败: %s: %v", targetDir, err)
+		} else {
+			h.targetDir = targetDir
+		}
 	}
+	return changed
 }
 
 // reloadConfig 重新加载配置
@@ -383,12 +435,14 @@ func (h *hotReloadManager) reloadConfig(ctx context.Context, source string) erro
 	}
 
 	h.config = newConfig
+	internal.RunApplyHooks(newConfig)
 	duration := time.Since(start)
 
 	// 触发配置变更回调
 	event := CreateEvent(CallbackTypeConfigChanged, source, oldConfig, newConfig)
 	event.WithMetadata("config_path", h.configPath)
 	event.WithMetadata("duration", duration)
+	attachChanges(&event, oldConfig, newConfig)
 
 	syncx.Go(ctx).
 		OnPanic(func(r any) {
@@ -421,3 +475,18 @@ func (h *hotReloadManager) triggerErrorCallback(ctx context.Context, err error,
 			return h.TriggerCallbacks(ctx, event)
 		})
 }
+
+// ChangeDetector 配置变更检测器，比较新旧配置并返回写入事件元数据的键与值；无相关变化时 ok 为 false
+type ChangeDetector = internal.ChangeDetector
+
+// RegisterChangeDetector 注册配置变更检测器，热更新时其结果写入配置变更事件元数据
+func RegisterChangeDetector(detector ChangeDetector) {
+	internal.RegisterChangeDetector(detector)
+}
+
+// attachChanges 执行已注册的变更检测器，将结果写入事件元数据
+func attachChanges(event *CallbackEvent, oldConfig, newConfig interface{}) {
+	for key, value := range internal.DetectChanges(oldConfig, newConfig) {
+		event.WithMetadata(key, value)
+	}
+}
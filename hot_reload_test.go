@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
+	"github.com/kamalyes/go-config/pkg/database"
 	"github.com/kamalyes/go-config/pkg/gateway"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -958,3 +959,170 @@ func TestHotReloader_TriggerErrorCallback(t *testing.T) {
 	time.Sleep(150 * time.Millisecond)
 	assert.True(t, triggered.Load())
 }
+
+func TestReloadConfig_ReportsDatabaseChanges(t *testing.T) {
+	yamlPath := filepath.Join(t.TempDir(), "datasource.yaml")
+	writeSources := func(replicaHost string, withAudit bool) {
+		content := `
+database:
+  default: orders
+  sources:
+    orders:
+      type: mysql
+      dsn: "app:secret@tcp(primary:3306)/orders?charset=utf8mb4"
+      policy: round-robin
+      replicas:
+        - name: r1
+          host: ` + replicaHost + `
+          weight: 2
+`
+		if withAudit {
+			content += `
+    audit:
+      dsn: "postgres://audit:pw@audit-db/audit"
+`
+		}
+		require.NoError(t, os.WriteFile(yamlPath, []byte(content), 0644))
+	}
+	writeSources("replica1", true)
+
+	v := viper.New()
+	v.SetConfigFile(yamlPath)
+	require.NoError(t, v.ReadInConfig())
+	config := &gateway.Gateway{}
+	require.NoError(t, UnmarshalWithFlexibleNaming(v, config))
+	require.Contains(t, config.Database.Sources, "orders")
+	assert.Equal(t, database.PolicyRoundRobin, config.Database.Sources["orders"].Policy)
+
+	reloader, err := NewHotReloader(config, v, yamlPath, &HotReloadConfig{Enabled: false})
+	require.NoError(t, err)
+
+	events := make(chan CallbackEvent, 1)
+	require.NoError(t, reloader.RegisterCallback(func(ctx context.Context, event CallbackEvent) error {
+		events <- event
+		return nil
+	}, CallbackOptions{ID: "datasource-changes", Types: []CallbackType{CallbackTypeConfigChanged}}))
+
+	writeSources("replica9", false)
+	require.NoError(t, reloader.Reload(context.Background()))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, []database.SourceChange{
+			{Name: "audit", Action: database.SourceRemoved},
+			{Name: "orders", Action: database.SourceUpdated},
+		}, DatabaseChanges(event))
+	case <-time.After(2 * time.Second):
+		t.Fatal("未收到配置变更回调")
+	}
+
+	provider, err := reloader.GetConfig().(*gateway.Gateway).Database.Resolve("orders", database.AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, "replica9", provider.GetHost())
+}
+
+func TestRegisterChangeDetector(t *testing.T) {
+	type detectorConfig struct{ Version int }
+	RegisterChangeDetector(func(oldConfig, newConfig interface{}) (string, interface{}, bool) {
+		before, ok1 := oldConfig.(*detectorConfig)
+		after, ok2 := newConfig.(*detectorConfig)
+		if !ok1 || !ok2 || before.Version == after.Version {
+			return "", nil, false
+		}
+		return "version_delta", after.Version - before.Version, true
+	})
+
+	event := CreateEvent(CallbackTypeConfigChanged, "test", nil, nil)
+	attachChanges(&event, &detectorConfig{Version: 1}, &detectorConfig{Version: 3})
+	delta, ok := event.GetMetadata("version_delta")
+	require.True(t, ok)
+	assert.Equal(t, 2, delta)
+	assert.Nil(t, DatabaseChanges(event))
+
+	event = CreateEvent(CallbackTypeConfigChanged, "test", nil, nil)
+	attachChanges(&event, &detectorConfig{Version: 1}, &detectorConfig{Version: 1})
+	_, ok = event.GetMetadata("version_delta")
+	assert.False(t, ok)
+}
+
+// configMapDir 模拟 Kubernetes ConfigMap 挂载目录：
+// app.yaml -> ..data/app.yaml，..data -> ..<version>，更新时原子替换 ..data
+type configMapDir struct {
+	t       *testing.T
+	dir     string
+	current string
+}
+
+func newConfigMapDir(t *testing.T, content string) *configMapDir {
+	m := &configMapDir{t: t, dir: t.TempDir()}
+	m.swap("..2026_10_19_00_00_00.1", content)
+	require.NoError(t, os.Symlink(filepath.Join("..data", "app.yaml"), m.configFile()))
+	return m
+}
+
+func (m *configMapDir) configFile() string {
+	return filepath.Join(m.dir, "app.yaml")
+}
+
+// swap 写入新版本目录，原子替换 ..data 后删除旧版本目录，与 kubelet 的更新顺序一致
+func (m *configMapDir) swap(version, content string) {
+	versionDir := filepath.Join(m.dir, version)
+	require.NoError(m.t, os.Mkdir(versionDir, 0755))
+	require.NoError(m.t, os.WriteFile(filepath.Join(versionDir, "app.yaml"), []byte(content), 0644))
+	tmpLink := filepath.Join(m.dir, "..data_tmp")
+	require.NoError(m.t, os.Symlink(version, tmpLink))
+	require.NoError(m.t, os.Rename(tmpLink, filepath.Join(m.dir, "..data")))
+	if m.current != "" {
+		require.NoError(m.t, os.RemoveAll(filepath.Join(m.dir, m.current)))
+	}
+	m.current = version
+}
+
+func TestHotReloader_ConfigMapSymlinkSwap(t *testing.T) {
+	cm := newConfigMapDir(t, "app:\n  name: v1\n")
+
+	config := &TestConfig{}
+	v, err := createViper(cm.configFile())
+	require.NoError(t, err)
+	require.NoError(t, v.Unmarshal(config))
+	assert.Equal(t, "v1", config.App.Name)
+
+	hotReloader, err := NewHotReloader(config, v, cm.configFile(), &HotReloadConfig{
+		Enabled:         true,
+		DebounceDelay:   100 * time.Millisecond,
+		CallbackTimeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	var reloads atomic.Int32
+	require.NoError(t, hotReloader.RegisterCallback(func(ctx context.Context, event CallbackEvent) error {
+		reloads.Add(1)
+		return nil
+	}, CallbackOptions{ID: "configmap", Types: []CallbackType{CallbackTypeConfigChanged}}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, hotReloader.Start(ctx))
+	defer hotReloader.Stop()
+
+	appName := func() string {
+		return hotReloader.GetConfig().(*TestConfig).App.Name
+	}
+
+	cm.swap("..2026_10_19_00_01_00.2", "app:\n  name: v2\n")
+	require.Eventually(t, func() bool { return appName() == "v2" }, 2*time.Second, 20*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(1), reloads.Load(), "一次 ..data 切换只触发一次重新加载")
+
+	// 再次切换需基于新的实际路径重新解析
+	cm.swap("..2026_10_19_00_02_00.3", "app:\n  name: v3\n")
+	require.Eventually(t, func() bool { return appName() == "v3" }, 2*time.Second, 20*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(2), reloads.Load())
+
+	// 直接修改实际文件同样触发重新加载
+	require.NoError(t, os.WriteFile(filepath.Join(cm.dir, cm.current, "app.yaml"), []byte("app:\n  name: v4\n"), 0644))
+	require.Eventually(t, func() bool { return appName() == "v4" }, 2*time.Second, 20*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, int32(3), reloads.Load())
+}
@@ -78,7 +78,7 @@ func NewIntegratedConfigManager(config interface{}, options *IntegratedConfigOpt
 	}
 
 	// 创建Viper实例
-	v := viper.New()
+	v := newViper()
 
 	// 配置Viper
 	if options.ConfigPath != "" {
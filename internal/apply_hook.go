@@ -0,0 +1,42 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\internal\apply_hook.go
+ * @Description: 配置应用钩子注册表 - 热更新应用新配置后、触发回调前由各配置模块刷新派生状态
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package internal
+
+import "sync"
+
+// ApplyHook 新配置生效时调用，用于丢弃由配置派生的缓存；与 ChangeDetector 不同，允许修改配置的内部状态
+type ApplyHook func(config interface{})
+
+var (
+	applyHooksMu sync.RWMutex
+	applyHooks   []ApplyHook
+)
+
+// RegisterApplyHook 注册配置应用钩子，nil 会被忽略
+func RegisterApplyHook(hook ApplyHook) {
+	if hook == nil {
+		return
+	}
+	applyHooksMu.Lock()
+	defer applyHooksMu.Unlock()
+	applyHooks = append(applyHooks, hook)
+}
+
+// RunApplyHooks 依次执行已注册的配置应用钩子
+func RunApplyHooks(config interface{}) {
+	applyHooksMu.RLock()
+	hooks := append([]ApplyHook(nil), applyHooks...)
+	applyHooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(config)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\internal\change_detector.go
+ * @Description: 配置变更检测器注册表 - 各配置模块在热更新时补充事件元数据
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package internal
+
+import "sync"
+
+// ChangeDetector 比较新旧配置，返回写入配置变更事件元数据的键与值；无相关变化时 ok 为 false
+type ChangeDetector func(oldConfig, newConfig interface{}) (key string, value interface{}, ok bool)
+
+var (
+	changeDetectorsMu sync.RWMutex
+	changeDetectors   []ChangeDetector
+)
+
+// RegisterChangeDetector 注册变更检测器，nil 会被忽略
+func RegisterChangeDetector(detector ChangeDetector) {
+	if detector == nil {
+		return
+	}
+	changeDetectorsMu.Lock()
+	defer changeDetectorsMu.Unlock()
+	changeDetectors = append(changeDetectors, detector)
+}
+
+// DetectChanges 依次执行已注册的检测器，返回有变化的元数据
+func DetectChanges(oldConfig, newConfig interface{}) map[string]interface{} {
+	changeDetectorsMu.RLock()
+	detectors := append([]ChangeDetector(nil), changeDetectors...)
+	changeDetectorsMu.RUnlock()
+
+	var metadata map[string]interface{}
+	for _, detect := range detectors {
+		key, value, ok := detect(oldConfig, newConfig)
+		if !ok {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]interface{})
+		}
+		metadata[key] = value
+	}
+	return metadata
+}
@@ -70,6 +70,11 @@ func ValidateStruct(c interface{}) error {
 	return validate.Struct(c)
 }
 
+// ValidateVar 使用标签规则验证单个值
+func ValidateVar(field interface{}, tag string) error {
+	return validate.Var(field, tag)
+}
+
 // 验证额外函数
 func ValidateExtra(extraFunc func() error) error {
 	if extraFunc != nil {
@@ -0,0 +1,228 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\internal\dsn.go
+ * @Description: DSN 公共工具 - Config 后缀参数、查询串与 libpq key/value 格式的解析与输出
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package internal
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DSNOption DSN 中的一个参数，保留原始顺序
+type DSNOption struct {
+	Key   string
+	Value string
+}
+
+// ParseDSNOptions 解析配置中的 Config 后缀
+// 支持 a=1&b=2（值可带 %XX 转义）与 libpq 风格的 a=1 b='x y'，前导 ? 会被忽略
+func ParseDSNOptions(config string) ([]DSNOption, error) {
+	config = strings.TrimPrefix(strings.TrimSpace(config), "?")
+	if config == "" {
+		return nil, nil
+	}
+	if !strings.Contains(config, "&") && strings.ContainsAny(config, " \t'") {
+		return ParseKeyValueDSN(config)
+	}
+
+	var opts []DSNOption
+	for _, part := range strings.Split(config, "&") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		if key == "" {
+			return nil, fmt.Errorf("invalid option %q: empty key", part)
+		}
+		opts = append(opts, DSNOption{Key: unescapeOption(key), Value: unescapeOption(value)})
+	}
+	return opts, nil
+}
+
+// FormatDSNOptions 输出 Config 后缀的规范形式 a=1&b=2，仅转义会破坏结构的字符
+func FormatDSNOptions(opts []DSNOption) string {
+	parts := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		parts = append(parts, escapeOption(opt.Key)+"="+escapeOption(opt.Value))
+	}
+	return strings.Join(parts, "&")
+}
+
+// DSNOptionsToMap 将参数列表转换为映射，重复的键以后出现的为准
+func DSNOptionsToMap(opts []DSNOption) map[string]string {
+	result := make(map[string]string, len(opts))
+	for _, opt := range opts {
+		result[opt.Key] = opt.Value
+	}
+	return result
+}
+
+// EncodeDSNQuery 按 URL 查询串规则编码参数（保持顺序），用于 URL 形式的 DSN
+func EncodeDSNQuery(opts []DSNOption) string {
+	parts := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		parts = append(parts, url.QueryEscape(opt.Key)+"="+url.QueryEscape(opt.Value))
+	}
+	return strings.Join(parts, "&")
+}
+
+// ParseDSNQuery 按 URL 查询串规则解码参数（保持顺序）
+func ParseDSNQuery(rawQuery string) ([]DSNOption, error) {
+	var opts []DSNOption
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		rawKey, rawValue, _ := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query key %q: %w", rawKey, err)
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query value for %q: %w", key, err)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid query option %q: empty key", part)
+		}
+		opts = append(opts, DSNOption{Key: key, Value: value})
+	}
+	return opts, nil
+}
+
+// ParseKeyValueDSN 解析 libpq 风格的 key=value 连接串，值可用单引号包裹并以 \ 转义
+func ParseKeyValueDSN(s string) ([]DSNOption, error) {
+	var opts []DSNOption
+	i := 0
+	for {
+		for i < len(s) && isDSNSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			return opts, nil
+		}
+
+		start := i
+		for i < len(s) && s[i] != '=' && !isDSNSpace(s[i]) {
+			i++
+		}
+		key := s[start:i]
+		for i < len(s) && isDSNSpace(s[i]) {
+			i++
+		}
+		if key == "" || i >= len(s) || s[i] != '=' {
+			return nil, fmt.Errorf("invalid key/value DSN near %q: missing '=' after key", s[start:])
+		}
+		i++
+		for i < len(s) && isDSNSpace(s[i]) {
+			i++
+		}
+
+		var value strings.Builder
+		if i < len(s) && s[i] == '\'' {
+			i++
+			closed := false
+			for i < len(s) {
+				c := s[i]
+				i++
+				if c == '\\' && i < len(s) {
+					value.WriteByte(s[i])
+					i++
+					continue
+				}
+				if c == '\'' {
+					closed = true
+					break
+				}
+				value.WriteByte(c)
+			}
+			if !closed {
+				return nil, fmt.Errorf("invalid key/value DSN: unterminated quoted value for %q", key)
+			}
+		} else {
+			for i < len(s) && !isDSNSpace(s[i]) {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				value.WriteByte(s[i])
+				i++
+			}
+		}
+		opts = append(opts, DSNOption{Key: key, Value: value.String()})
+	}
+}
+
+// FormatKeyValueDSN 输出 libpq 风格的连接串，值为空或含空白、引号、反斜杠时加引号
+func FormatKeyValueDSN(opts []DSNOption) string {
+	parts := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		value := opt.Value
+		if value == "" || strings.ContainsAny(value, " \t\r\n'\\") {
+			value = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+		}
+		parts = append(parts, opt.Key+"="+value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// JoinHostPort 拼接主机与端口，端口为空时只返回主机，IPv6 地址自动加方括号
+func JoinHostPort(host, port string) string {
+	if port == "" {
+		if strings.Contains(host, ":") {
+			return "[" + host + "]"
+		}
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// SplitHostPort 拆分主机与端口，缺少端口时使用 defaultPort
+func SplitHostPort(hostport, defaultPort string) (string, string) {
+	if host, port, err := net.SplitHostPort(hostport); err == nil {
+		if port == "" {
+			port = defaultPort
+		}
+		return host, port
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]"), defaultPort
+}
+
+// escapeOption 转义 Config 后缀中会破坏 a=1&b=2 结构的字符
+func escapeOption(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' || c == '&' || c == '=' || c == '#' || c == '+' || c <= ' ' || c == 0x7f {
+			fmt.Fprintf(&sb, "%%%02X", c)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// unescapeOption 还原 %XX 转义，非法转义时保留原文
+func unescapeOption(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	if unescaped, err := url.PathUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// isDSNSpace 判断是否为 key/value 连接串中的空白字符
+func isDSNSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
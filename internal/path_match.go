@@ -0,0 +1,30 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\internal\path_match.go
+ * @Description: 路径模式匹配 - 断路器、超时与 Swagger 选择器共用的 glob 规则
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package internal
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchPath 路径匹配：以 "/**" 结尾的模式匹配该前缀及其所有子路径，其余按 path.Match 逐段匹配
+func MatchPath(pattern, p string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return p == prefix || strings.HasPrefix(p, prefix+"/")
+	}
+	ok, _ := path.Match(pattern, p)
+	return ok
+}
+
+// IsPathGlob 模式是否使用了 glob 语法（"/**" 后缀或 * ? [）
+func IsPathGlob(pattern string) bool {
+	return strings.HasSuffix(pattern, "/**") || strings.ContainsAny(pattern, "*?[")
+}
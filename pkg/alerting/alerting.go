@@ -12,6 +12,8 @@
 package alerting
 
 import (
+	"errors"
+
 	"github.com/kamalyes/go-config/internal"
 	"github.com/kamalyes/go-toolbox/pkg/syncx"
 )
@@ -27,7 +29,7 @@ type Alerting struct {
 // NotificationChannel 通知渠道配置
 type NotificationChannel struct {
 	Name     string            `mapstructure:"name" yaml:"name" json:"name"`             // 渠道名称
-	Type     string            `mapstructure:"type" yaml:"type" json:"type"`             // 渠道类型 (slack, email, webhook)
+	Type     string            `mapstructure:"type" yaml:"type" json:"type"`             // 渠道类型 (webhook, slack, email, dingtalk, wecom, feishu)
 	Settings map[string]string `mapstructure:"settings" yaml:"settings" json:"settings"` // 渠道设置
 }
 
@@ -65,7 +67,16 @@ func (a *Alerting) Clone() internal.Configurable {
 
 // Validate 验证配置
 func (a *Alerting) Validate() error {
-	return internal.ValidateStruct(a)
+	if err := internal.ValidateStruct(a); err != nil {
+		return err
+	}
+	var errs []error
+	for _, channel := range a.Channels {
+		if err := channel.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // WithWebhooks 设置Webhook列表
@@ -127,6 +138,21 @@ func (a *Alerting) AddEmailChannel(name, smtpHost, smtpPort, username, password
 	return a.AddChannel(name, "email", settings)
 }
 
+// AddDingTalkChannel 添加钉钉机器人通知渠道，secret 为空时不加签
+func (a *Alerting) AddDingTalkChannel(name, webhook, secret string) *Alerting {
+	return a.AddChannel(name, ChannelDingTalk, map[string]string{"webhook": webhook, "secret": secret})
+}
+
+// AddWeComChannel 添加企业微信机器人通知渠道
+func (a *Alerting) AddWeComChannel(name, webhook string) *Alerting {
+	return a.AddChannel(name, ChannelWeCom, map[string]string{"webhook": webhook})
+}
+
+// AddFeishuChannel 添加飞书机器人通知渠道，secret 为空时不签名
+func (a *Alerting) AddFeishuChannel(name, webhook, secret string) *Alerting {
+	return a.AddChannel(name, ChannelFeishu, map[string]string{"webhook": webhook, "secret": secret})
+}
+
 // Enable 启用告警
 func (a *Alerting) Enable() *Alerting {
 	a.Enabled = true
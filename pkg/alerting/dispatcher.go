@@ -0,0 +1,336 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\alerting\dispatcher.go
+ * @Description: 统一通知分发：按告警键冷却与每小时限额，消息模板渲染
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"regexp"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// 告警被抑制的原因，可通过 errors.Is 判断
+var (
+	ErrCooldown    = errors.New("alert is in cooldown")
+	ErrHourlyLimit = errors.New("hourly alert limit reached")
+)
+
+// DeliveryError 部分或全部渠道投递失败，Delivered/Failed 为对应的渠道名称，
+// 调用方可通过 Dispatcher.Retry 只向失败的渠道补发
+type DeliveryError struct {
+	Delivered []string
+	Failed    []string
+	Err       error
+}
+
+func (e *DeliveryError) Error() string { return e.Err.Error() }
+
+func (e *DeliveryError) Unwrap() error { return e.Err }
+
+// Partial 是否有渠道投递成功
+func (e *DeliveryError) Partial() bool { return len(e.Delivered) > 0 }
+
+// Dispatcher 通知分发器，将消息投递到全部渠道，并按告警键限制发送频率
+//
+// 同一告警键在冷却时间内只发送一次，且任意一小时窗口内不超过 maxPerHour 次；
+// 全部渠道投递失败时撤销发送记录，允许立即重试
+type Dispatcher struct {
+	mu         sync.Mutex
+	notifiers  []Notifier
+	cooldown   time.Duration
+	maxPerHour int
+	attempts   int
+	backoff    time.Duration
+	now        func() time.Time
+	history    map[string][]time.Time // 告警键 -> 最近一小时内的发送时间
+}
+
+// NewDispatcher 创建通知分发器，默认不冷却、不限额、每个渠道只尝试一次
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	return &Dispatcher{
+		notifiers: notifiers,
+		attempts:  1,
+		now:       time.Now,
+		history:   make(map[string][]time.Time),
+	}
+}
+
+// WithNotifiers 追加通知渠道
+func (d *Dispatcher) WithNotifiers(notifiers ...Notifier) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.notifiers = append(d.notifiers, notifiers...)
+	return d
+}
+
+// WithCooldown 设置同一告警键的冷却时间，0 表示不冷却
+func (d *Dispatcher) WithCooldown(cooldown time.Duration) *Dispatcher {
+	d.cooldown = cooldown
+	return d
+}
+
+// WithMaxPerHour 设置同一告警键每小时最大发送次数，0 表示不限制
+func (d *Dispatcher) WithMaxPerHour(limit int) *Dispatcher {
+	d.maxPerHour = limit
+	return d
+}
+
+// WithRetry 设置每个渠道的总尝试次数与初始退避时间
+func (d *Dispatcher) WithRetry(attempts int, backoff time.Duration) *Dispatcher {
+	d.attempts, d.backoff = attempts, backoff
+	return d
+}
+
+// WithClock 设置时钟，便于测试
+func (d *Dispatcher) WithClock(now func() time.Time) *Dispatcher {
+	d.now = now
+	return d
+}
+
+// Notifiers 返回已注册的通知渠道
+func (d *Dispatcher) Notifiers() []Notifier {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Notifier(nil), d.notifiers...)
+}
+
+// Send 按告警键投递消息，key 为空时使用消息标题
+//
+// 被冷却或限额抑制时返回包装 ErrCooldown / ErrHourlyLimit 的错误，不会投递；
+// 任一渠道投递失败时返回 *DeliveryError，合并各渠道的错误
+func (d *Dispatcher) Send(ctx context.Context, key string, msg *Message) error {
+	if key == "" {
+		key = msg.Subject
+	}
+
+	d.mu.Lock()
+	now := d.now()
+	if err := d.admit(key, now); err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	notifiers := append([]Notifier(nil), d.notifiers...)
+	if len(notifiers) == 0 {
+		d.mu.Unlock()
+		return nil
+	}
+	// 先占位记录，避免并发发送同一告警键时同时通过冷却检查
+	d.history[key] = append(d.history[key], now)
+	d.mu.Unlock()
+
+	err := d.deliver(ctx, notifiers, msg)
+	var de *DeliveryError
+	if errors.As(err, &de) && !de.Partial() {
+		d.release(key, now)
+	}
+	return err
+}
+
+// Retry 只向指定名称的渠道补发消息，不受冷却与限额限制，也不记录发送；
+// 用于 Send 返回部分成功的 *DeliveryError 后补发失败的渠道，已移除的渠道视为无需补发
+func (d *Dispatcher) Retry(ctx context.Context, msg *Message, names []string) error {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var notifiers []Notifier
+	for _, n := range d.Notifiers() {
+		if wanted[n.Name()] {
+			notifiers = append(notifiers, n)
+		}
+	}
+	return d.deliver(ctx, notifiers, msg)
+}
+
+// deliver 向各渠道投递消息，失败时返回 *DeliveryError
+func (d *Dispatcher) deliver(ctx context.Context, notifiers []Notifier, msg *Message) error {
+	var delivered, failed []string
+	var errs []error
+	for _, n := range notifiers {
+		if err := SendWithRetry(ctx, n, msg, d.attempts, d.backoff); err != nil {
+			failed = append(failed, n.Name())
+			errs = append(errs, err)
+			continue
+		}
+		delivered = append(delivered, n.Name())
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &DeliveryError{Delivered: delivered, Failed: failed, Err: errors.Join(errs...)}
+}
+
+// release 撤销全部渠道投递失败的发送记录
+func (d *Dispatcher) release(key string, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	sent := d.history[key]
+	for i := len(sent) - 1; i >= 0; i-- {
+		if sent[i].Equal(at) {
+			d.history[key] = append(sent[:i:i], sent[i+1:]...)
+			break
+		}
+	}
+	if len(d.history[key]) == 0 {
+		delete(d.history, key)
+	}
+}
+
+// Allow 判断告警键当前是否允许发送，不记录发送
+func (d *Dispatcher) Allow(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.admit(key, d.now()) == nil
+}
+
+// Reset 清除告警键的发送记录，key 为空时清除全部
+func (d *Dispatcher) Reset(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if key == "" {
+		d.history = make(map[string][]time.Time)
+		return
+	}
+	delete(d.history, key)
+}
+
+// admit 清理过期记录并检查冷却与限额，调用方需持有锁
+func (d *Dispatcher) admit(key string, now time.Time) error {
+	window := time.Hour
+	if d.cooldown > window {
+		window = d.cooldown
+	}
+	for k, sent := range d.history {
+		i := 0
+		for i < len(sent) && now.Sub(sent[i]) >= window {
+			i++
+		}
+		if i == len(sent) {
+			delete(d.history, k)
+		} else {
+			d.history[k] = sent[i:]
+		}
+	}
+
+	sent := d.history[key]
+	if len(sent) == 0 {
+		return nil
+	}
+	if last := sent[len(sent)-1]; d.cooldown > 0 && now.Sub(last) < d.cooldown {
+		return fmt.Errorf("alert %q suppressed for %s: %w", key, (d.cooldown - now.Sub(last)).Round(time.Second), ErrCooldown)
+	}
+	if d.maxPerHour > 0 {
+		count := 0
+		for _, t := range sent {
+			if now.Sub(t) < time.Hour {
+				count++
+			}
+		}
+		if count >= d.maxPerHour {
+			return fmt.Errorf("alert %q suppressed after %d notification(s) in the last hour: %w", key, count, ErrHourlyLimit)
+		}
+	}
+	return nil
+}
+
+// MessageTemplate 消息模板：主题与 HTML 正文
+//
+// 正文使用 html/template 渲染以转义数据中的 HTML；主题是邮件头与 IM 标题而非 HTML，
+// 使用 text/template 渲染，避免 "&" 等字符被转义成实体
+type MessageTemplate struct {
+	subject *texttemplate.Template
+	body    *htmltemplate.Template
+}
+
+// NewMessageTemplate 解析主题与正文模板
+func NewMessageTemplate(name, subject, body string) (*MessageTemplate, error) {
+	s, err := texttemplate.New(name + "-subject").Option("missingkey=zero").Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template %q: %w", name, err)
+	}
+	b, err := htmltemplate.New(name + "-body").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template %q: %w", name, err)
+	}
+	return &MessageTemplate{subject: s, body: b}, nil
+}
+
+// Render 渲染消息，Text 由 HTML 正文提取，供不支持 HTML 的渠道使用
+func (t *MessageTemplate) Render(data any) (*Message, error) {
+	var subject, body bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := t.body.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+	return &Message{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    body.String(),
+		Text:    htmlToText(body.String()),
+	}, nil
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// htmlToText 提取 HTML 中的可见文本，跳过 head、style、script
+func htmlToText(src string) string {
+	z := html.NewTokenizer(strings.NewReader(src))
+	var buf strings.Builder
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			lines := strings.Split(buf.String(), "\n")
+			for i, line := range lines {
+				lines[i] = strings.Join(strings.Fields(line), " ")
+			}
+			return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "head", "style", "script":
+				skip++
+			case "br", "p", "div", "tr", "li", "h1", "h2", "h3", "h4", "table":
+				buf.WriteString("\n")
+			case "td", "th":
+				buf.WriteString(" ")
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "head", "style", "script":
+				if skip > 0 {
+					skip--
+				}
+			case "p", "div", "tr", "li", "h1", "h2", "h3", "h4", "table":
+				buf.WriteString("\n")
+			}
+		case html.SelfClosingTagToken:
+			if name, _ := z.TagName(); string(name) == "br" {
+				buf.WriteString("\n")
+			}
+		case html.TextToken:
+			if skip == 0 {
+				buf.Write(z.Text())
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\alerting\dispatcher_test.go
+ * @Description: 通知分发器与消息模板测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package alerting
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_CooldownAndHourlyLimit(t *testing.T) {
+	server, last := recordingServer(t, http.StatusOK)
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	d := NewDispatcher(NewWebhookNotifier("ops", server.URL, nil, 0)).
+		WithCooldown(5 * time.Minute).
+		WithMaxPerHour(3).
+		WithClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	require.NoError(t, d.Send(ctx, "user-1", &Message{Subject: "first"}))
+	body, _ := last()
+	assert.Equal(t, "first", body["subject"])
+
+	err := d.Send(ctx, "user-1", &Message{Subject: "again"})
+	assert.ErrorIs(t, err, ErrCooldown)
+	assert.ErrorContains(t, err, `alert "user-1" suppressed for 5m0s`)
+	assert.NoError(t, d.Send(ctx, "user-2", &Message{}), "不同告警键互不影响")
+
+	for i := 0; i < 2; i++ {
+		now = now.Add(5 * time.Minute)
+		require.NoError(t, d.Send(ctx, "user-1", &Message{}))
+	}
+	now = now.Add(10 * time.Minute)
+	assert.False(t, d.Allow("user-1"))
+	assert.ErrorIs(t, d.Send(ctx, "user-1", &Message{}), ErrHourlyLimit)
+
+	// 首条记录滑出一小时窗口后恢复发送
+	now = time.Date(2026, 10, 19, 1, 0, 0, 0, time.UTC)
+	assert.True(t, d.Allow("user-1"))
+	require.NoError(t, d.Send(ctx, "user-1", &Message{}))
+
+	d.Reset("user-1")
+	assert.True(t, d.Allow("user-1"))
+}
+
+func TestDispatcher_FailureReleasesSlot(t *testing.T) {
+	flaky := &flakyNotifier{failures: 1}
+	d := NewDispatcher(flaky).WithCooldown(time.Hour)
+
+	err := d.Send(context.Background(), "", &Message{Subject: "disk"})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.True(t, d.Allow("disk"), "全部渠道失败时不计入发送记录，key 为空时使用标题")
+
+	require.NoError(t, d.Send(context.Background(), "", &Message{Subject: "disk"}))
+	assert.False(t, d.Allow("disk"))
+
+	// 部分渠道失败仍视为已发送
+	d = NewDispatcher(&flakyNotifier{failures: 5}, &flakyNotifier{}).WithCooldown(time.Hour).WithRetry(2, time.Millisecond)
+	assert.ErrorContains(t, d.Send(context.Background(), "k", &Message{}), "failed after 2 attempt(s)")
+	assert.False(t, d.Allow("k"))
+
+	assert.NoError(t, NewDispatcher().Send(context.Background(), "k", &Message{}), "无渠道时静默忽略")
+}
+
+func TestDispatcher_RetryFailed(t *testing.T) {
+	ok, broken := &flakyNotifier{name: "ok"}, &flakyNotifier{name: "broken", failures: 2}
+	d := NewDispatcher(ok, broken).WithCooldown(time.Hour)
+	ctx := context.Background()
+
+	err := d.Send(ctx, "k", &Message{})
+	var de *DeliveryError
+	require.ErrorAs(t, err, &de)
+	assert.True(t, de.Partial())
+	assert.Equal(t, []string{"ok"}, de.Delivered)
+	assert.Equal(t, []string{"broken"}, de.Failed)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// 冷却期内只向失败的渠道补发
+	require.ErrorAs(t, d.Retry(ctx, &Message{}, de.Failed), &de)
+	assert.False(t, de.Partial())
+	require.NoError(t, d.Retry(ctx, &Message{}, de.Failed))
+	assert.Equal(t, int32(1), ok.calls.Load(), "已送达的渠道不会重复收到")
+	assert.Equal(t, int32(3), broken.calls.Load())
+	assert.False(t, d.Allow("k"), "补发不影响发送记录")
+
+	assert.NoError(t, d.Retry(ctx, &Message{}, []string{"removed"}), "已移除的渠道无需补发")
+}
+
+func TestDispatcher_SMTP(t *testing.T) {
+	smtpServer := newSMTPStandIn(t)
+	mail := NewSMTPNotifier("mail", "127.0.0.1", smtpServer.port(), "", "", "alert@example.com", []string{"a@example.com"}, false)
+	mail.Cc = []string{"c@example.com"}
+
+	tmpl, err := NewMessageTemplate("alert", "告警 - {{.App}}", `<html><head><style>p{color:red}</style></head><body><p>用户 <b>{{.User}}</b></p><br/>次数: {{.Count}}</body></html>`)
+	require.NoError(t, err)
+	msg, err := tmpl.Render(map[string]any{"App": "A&B", "User": "<script>", "Count": 3})
+	require.NoError(t, err)
+	assert.Equal(t, "告警 - A&B", msg.Subject, "主题不做 HTML 转义")
+	assert.Contains(t, msg.HTML, "&lt;script&gt;")
+	assert.Equal(t, "用户 <script>\n\n次数: 3", msg.Text)
+
+	require.NoError(t, NewDispatcher(mail).Send(context.Background(), "k", msg))
+	mails := smtpServer.received()
+	require.Len(t, mails, 1)
+	assert.Equal(t, []string{"a@example.com", "c@example.com"}, mails[0].To)
+	assert.Contains(t, mails[0].Data, "Cc: c@example.com\r\n")
+	assert.Contains(t, mails[0].Data, "<b>&lt;script&gt;</b>")
+
+	_, err = NewMessageTemplate("bad", "{{.X", "")
+	assert.ErrorContains(t, err, `invalid subject template "bad"`)
+	_, err = NewMessageTemplate("bad", "", "{{if}}")
+	assert.ErrorContains(t, err, `invalid body template "bad"`)
+}
@@ -0,0 +1,193 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\alerting\im.go
+ * @Description: 国内 IM 群机器人通知渠道：钉钉、企业微信、飞书
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package alerting
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotResult 群机器人接口的通用响应，HTTP 200 时仍需检查业务错误码
+type robotResult struct {
+	ErrCode int    `json:"errcode"` // 钉钉、企业微信
+	ErrMsg  string `json:"errmsg"`
+	Code    int    `json:"code"` // 飞书
+	Msg     string `json:"msg"`
+}
+
+// err 业务错误码非 0 时返回错误
+func (r *robotResult) err(name string) error {
+	switch {
+	case r.ErrCode != 0:
+		return fmt.Errorf("notifier %q: robot returned errcode %d: %s", name, r.ErrCode, r.ErrMsg)
+	case r.Code != 0:
+		return fmt.Errorf("notifier %q: robot returned code %d: %s", name, r.Code, r.Msg)
+	}
+	return nil
+}
+
+// markdownText 拼接 Markdown 标题与正文
+func markdownText(msg *Message) string {
+	if msg.Subject == "" {
+		return msg.Text
+	}
+	return "### " + msg.Subject + "\n\n" + msg.Text
+}
+
+// hmacBase64 计算 HMAC-SHA256 并进行 Base64 编码
+func hmacBase64(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// DingTalkNotifier 钉钉自定义机器人，以 Markdown 消息发送
+type DingTalkNotifier struct {
+	name      string
+	URL       string   // 机器人 Webhook 地址（含 access_token）
+	Secret    string   // 加签密钥，为空时不签名
+	AtMobiles []string // 需要 @ 的手机号
+	AtAll     bool     // 是否 @ 所有人
+	Client    *http.Client
+	now       func() time.Time
+}
+
+// NewDingTalkNotifier 创建钉钉机器人通知渠道
+func NewDingTalkNotifier(name, webhookURL, secret string) *DingTalkNotifier {
+	return &DingTalkNotifier{name: name, URL: webhookURL, Secret: secret, Client: &http.Client{Timeout: DefaultNotifyTimeout}, now: time.Now}
+}
+
+// Name 返回渠道名称
+func (d *DingTalkNotifier) Name() string {
+	return d.name
+}
+
+// Send 发送通知
+func (d *DingTalkNotifier) Send(ctx context.Context, msg *Message) error {
+	target := d.URL
+	if d.Secret != "" {
+		// 签名串为 "毫秒时间戳\n密钥"，以密钥做 HMAC-SHA256
+		ts := strconv.FormatInt(d.now().UnixMilli(), 10)
+		sign := hmacBase64(d.Secret, ts+"\n"+d.Secret)
+		target = appendQuery(target, url.Values{"timestamp": {ts}, "sign": {sign}})
+	}
+
+	text := markdownText(msg)
+	for _, mobile := range d.AtMobiles {
+		text += " @" + mobile
+	}
+	title := msg.Subject
+	if title == "" {
+		title = d.name
+	}
+	payload := map[string]any{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"title": title, "text": text},
+		"at":       map[string]any{"atMobiles": d.AtMobiles, "isAtAll": d.AtAll},
+	}
+
+	var result robotResult
+	if err := postJSON(ctx, d.Client, target, nil, payload, &result); err != nil {
+		return err
+	}
+	return result.err(d.name)
+}
+
+// WeComNotifier 企业微信群机器人，以 Markdown 消息发送
+type WeComNotifier struct {
+	name   string
+	URL    string // 机器人 Webhook 地址（含 key）
+	Client *http.Client
+}
+
+// NewWeComNotifier 创建企业微信机器人通知渠道
+func NewWeComNotifier(name, webhookURL string) *WeComNotifier {
+	return &WeComNotifier{name: name, URL: webhookURL, Client: &http.Client{Timeout: DefaultNotifyTimeout}}
+}
+
+// Name 返回渠道名称
+func (w *WeComNotifier) Name() string {
+	return w.name
+}
+
+// Send 发送通知
+func (w *WeComNotifier) Send(ctx context.Context, msg *Message) error {
+	payload := map[string]any{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": markdownText(msg)},
+	}
+	var result robotResult
+	if err := postJSON(ctx, w.Client, w.URL, nil, payload, &result); err != nil {
+		return err
+	}
+	return result.err(w.name)
+}
+
+// FeishuNotifier 飞书自定义机器人，以文本消息发送
+type FeishuNotifier struct {
+	name   string
+	URL    string // 机器人 Webhook 地址
+	Secret string // 签名校验密钥，为空时不签名
+	Client *http.Client
+	now    func() time.Time
+}
+
+// NewFeishuNotifier 创建飞书机器人通知渠道
+func NewFeishuNotifier(name, webhookURL, secret string) *FeishuNotifier {
+	return &FeishuNotifier{name: name, URL: webhookURL, Secret: secret, Client: &http.Client{Timeout: DefaultNotifyTimeout}, now: time.Now}
+}
+
+// Name 返回渠道名称
+func (f *FeishuNotifier) Name() string {
+	return f.name
+}
+
+// Send 发送通知
+func (f *FeishuNotifier) Send(ctx context.Context, msg *Message) error {
+	text := msg.Text
+	if msg.Subject != "" {
+		text = msg.Subject + "\n" + msg.Text
+	}
+	payload := map[string]any{
+		"msg_type": "text",
+		"content":  map[string]string{"text": text},
+	}
+	if f.Secret != "" {
+		// 飞书以 "秒级时间戳\n密钥" 作为 HMAC 密钥，对空串签名
+		ts := strconv.FormatInt(f.now().Unix(), 10)
+		payload["timestamp"] = ts
+		payload["sign"] = hmacBase64(ts+"\n"+f.Secret, "")
+	}
+
+	var result robotResult
+	if err := postJSON(ctx, f.Client, f.URL, nil, payload, &result); err != nil {
+		return err
+	}
+	return result.err(f.name)
+}
+
+// appendQuery 向地址追加查询参数，保留已有参数
+func appendQuery(rawURL string, values url.Values) string {
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + values.Encode()
+}
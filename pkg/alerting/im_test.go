@@ -0,0 +1,130 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\alerting\im_test.go
+ * @Description: 钉钉、企业微信、飞书机器人渠道测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// robotServer 模拟群机器人接口，返回固定响应并记录请求
+func robotServer(t *testing.T, response string) (*httptest.Server, func() (map[string]any, url.Values)) {
+	var mu sync.Mutex
+	var body map[string]any
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body = map[string]any{}
+		json.NewDecoder(r.Body).Decode(&body)
+		query = r.URL.Query()
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() (map[string]any, url.Values) {
+		mu.Lock()
+		defer mu.Unlock()
+		return body, query
+	}
+}
+
+var fixedNow = func() time.Time { return time.UnixMilli(1700000000123) }
+
+func TestDingTalkNotifier(t *testing.T) {
+	server, last := robotServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	n := NewDingTalkNotifier("ding", server.URL+"/robot/send?access_token=abc", "SEC123")
+	n.AtMobiles = []string{"13800000000"}
+	n.now = fixedNow
+
+	require.NoError(t, n.Send(context.Background(), &Message{Subject: "CPU", Text: "high"}))
+	body, query := last()
+	assert.Equal(t, "abc", query.Get("access_token"))
+	assert.Equal(t, "1700000000123", query.Get("timestamp"))
+	assert.Equal(t, hmacBase64("SEC123", "1700000000123\nSEC123"), query.Get("sign"))
+	assert.Equal(t, "markdown", body["msgtype"])
+	assert.Equal(t, map[string]any{"title": "CPU", "text": "### CPU\n\nhigh @13800000000"}, body["markdown"])
+	assert.Equal(t, []any{"13800000000"}, body["at"].(map[string]any)["atMobiles"])
+
+	failing, _ := robotServer(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	err := NewDingTalkNotifier("ding", failing.URL, "").Send(context.Background(), &Message{Text: "x"})
+	assert.ErrorContains(t, err, `notifier "ding": robot returned errcode 310000: sign not match`)
+}
+
+func TestWeComNotifier(t *testing.T) {
+	server, last := robotServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	require.NoError(t, NewWeComNotifier("wecom", server.URL).Send(context.Background(), &Message{Subject: "磁盘", Text: "90%"}))
+	body, _ := last()
+	assert.Equal(t, map[string]any{"content": "### 磁盘\n\n90%"}, body["markdown"])
+
+	failing, _ := robotServer(t, `{"errcode":93000,"errmsg":"invalid webhook url"}`)
+	assert.ErrorContains(t, NewWeComNotifier("wecom", failing.URL).Send(context.Background(), &Message{}), "errcode 93000")
+}
+
+func TestFeishuNotifier(t *testing.T) {
+	server, last := robotServer(t, `{"code":0,"msg":"success"}`)
+	n := NewFeishuNotifier("feishu", server.URL, "s3cret")
+	n.now = fixedNow
+
+	require.NoError(t, n.Send(context.Background(), &Message{Subject: "延迟", Text: "p99 2s"}))
+	body, _ := last()
+	assert.Equal(t, "text", body["msg_type"])
+	assert.Equal(t, map[string]any{"text": "延迟\np99 2s"}, body["content"])
+	assert.Equal(t, "1700000000", body["timestamp"])
+	assert.Equal(t, hmacBase64("1700000000\ns3cret", ""), body["sign"])
+
+	failing, _ := robotServer(t, `{"code":19021,"msg":"sign match fail"}`)
+	assert.ErrorContains(t, NewFeishuNotifier("feishu", failing.URL, "").Send(context.Background(), &Message{}), "code 19021: sign match fail")
+}
+
+func TestNotificationChannel_RobotSettings(t *testing.T) {
+	a := Default().
+		AddDingTalkChannel("ding", "http://ding", "sec").
+		AddWeComChannel("wecom", "http://wecom").
+		AddFeishuChannel("feishu", "http://feishu", "").
+		AddChannel("ding-at", ChannelDingTalk, map[string]string{"webhook": "http://ding", "at_mobiles": "1, 2", "at_all": "true", "timeout": "3s"})
+	require.NoError(t, a.Validate())
+
+	notifiers, err := a.Notifiers()
+	require.NoError(t, err)
+	require.Len(t, notifiers, 4)
+	assert.Equal(t, "sec", notifiers[0].(*DingTalkNotifier).Secret)
+	assert.IsType(t, &WeComNotifier{}, notifiers[1])
+	assert.IsType(t, &FeishuNotifier{}, notifiers[2])
+	ding := notifiers[3].(*DingTalkNotifier)
+	assert.Equal(t, []string{"1", "2"}, ding.AtMobiles)
+	assert.True(t, ding.AtAll)
+	assert.Equal(t, 3*time.Second, ding.Client.Timeout)
+
+	invalid := Default().
+		AddChannel("d", ChannelDingTalk, map[string]string{"webhook": "http://ding", "at_all": "maybe"}).
+		AddChannel("w", ChannelWeCom, nil).
+		AddChannel("f", ChannelFeishu, map[string]string{"webhook": "http://feishu", "timeout": "soon"})
+	err = invalid.Validate()
+	require.Error(t, err)
+	for _, expected := range []string{
+		`channel "d": invalid at_all "maybe"`,
+		`channel "w": missing setting "webhook"`,
+		`channel "f": invalid timeout "soon"`,
+	} {
+		assert.Contains(t, err.Error(), expected)
+	}
+}
@@ -0,0 +1,473 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\alerting\notifier.go
+ * @Description: 通知渠道驱动：Webhook、Slack、SMTP 邮件、IM 机器人及重试发送
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 渠道类型
+const (
+	ChannelWebhook  = "webhook"
+	ChannelSlack    = "slack"
+	ChannelEmail    = "email"
+	ChannelDingTalk = "dingtalk"
+	ChannelWeCom    = "wecom"
+	ChannelFeishu   = "feishu"
+)
+
+// DefaultNotifyTimeout 未配置超时时间时的默认值
+const DefaultNotifyTimeout = 10 * time.Second
+
+// SlackPostMessageURL Slack Bot Token 方式发送消息的接口地址
+const SlackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// Message 通知消息
+type Message struct {
+	Subject string `json:"subject"`           // 标题（邮件主题、IM 消息标题）
+	Text    string `json:"text"`              // 纯文本正文
+	HTML    string `json:"html,omitempty"`    // HTML 正文（仅邮件使用，为空时发送纯文本）
+	Payload any    `json:"payload,omitempty"` // Webhook 请求体，为空时发送整个 Message
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+}
+
+// WebhookNotifier 以 JSON POST 方式发送通知
+type WebhookNotifier struct {
+	name    string
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// NewWebhookNotifier 创建 Webhook 通知渠道，timeout 为 0 时使用默认超时
+func NewWebhookNotifier(name, url string, headers map[string]string, timeout time.Duration) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = DefaultNotifyTimeout
+	}
+	return &WebhookNotifier{name: name, URL: url, Headers: headers, Client: &http.Client{Timeout: timeout}}
+}
+
+// Name 返回渠道名称
+func (w *WebhookNotifier) Name() string {
+	return w.name
+}
+
+// Send 发送通知
+func (w *WebhookNotifier) Send(ctx context.Context, msg *Message) error {
+	var body any = msg
+	if msg.Payload != nil {
+		body = msg.Payload
+	}
+	return postJSON(ctx, w.Client, w.URL, w.Headers, body, nil)
+}
+
+// SlackNotifier Slack 通知，支持 Incoming Webhook 与 Bot Token 两种方式
+type SlackNotifier struct {
+	name       string
+	WebhookURL string // Incoming Webhook 地址，设置后优先使用
+	Token      string // Bot Token
+	APIURL     string // chat.postMessage 接口地址
+	Channel    string
+	Username   string
+	IconEmoji  string
+	Client     *http.Client
+}
+
+// NewSlackWebhookNotifier 通过 Incoming Webhook 发送 Slack 消息
+func NewSlackWebhookNotifier(name, webhookURL, channel string) *SlackNotifier {
+	return &SlackNotifier{name: name, WebhookURL: webhookURL, Channel: channel, Client: &http.Client{Timeout: DefaultNotifyTimeout}}
+}
+
+// NewSlackBotNotifier 通过 Bot Token 调用 chat.postMessage 发送 Slack 消息
+func NewSlackBotNotifier(name, token, channel string) *SlackNotifier {
+	return &SlackNotifier{name: name, Token: token, APIURL: SlackPostMessageURL, Channel: channel, Client: &http.Client{Timeout: DefaultNotifyTimeout}}
+}
+
+// Name 返回渠道名称
+func (s *SlackNotifier) Name() string {
+	return s.name
+}
+
+// Send 发送通知
+func (s *SlackNotifier) Send(ctx context.Context, msg *Message) error {
+	text := msg.Text
+	if msg.Subject != "" {
+		text = "*" + msg.Subject + "*\n" + msg.Text
+	}
+	payload := map[string]string{"text": text}
+	if s.Channel != "" {
+		payload["channel"] = s.Channel
+	}
+	if s.Username != "" {
+		payload["username"] = s.Username
+	}
+	if s.IconEmoji != "" {
+		payload["icon_emoji"] = s.IconEmoji
+	}
+
+	if s.WebhookURL != "" {
+		return postJSON(ctx, s.Client, s.WebhookURL, nil, payload, nil)
+	}
+	return postJSON(ctx, s.Client, s.APIURL, map[string]string{"Authorization": "Bearer " + s.Token}, payload, nil)
+}
+
+// SMTPNotifier 通过 SMTP 发送邮件通知
+type SMTPNotifier struct {
+	name     string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Cc       []string
+	TLS      bool // true 时使用隐式 TLS（如 465 端口），否则在服务端支持时自动 STARTTLS
+	Timeout  time.Duration
+}
+
+// NewSMTPNotifier 创建邮件通知渠道
+func NewSMTPNotifier(name, host string, port int, username, password, from string, to []string, useTLS bool) *SMTPNotifier {
+	if from == "" {
+		from = username
+	}
+	return &SMTPNotifier{name: name, Host: host, Port: port, Username: username, Password: password, From: from, To: to, TLS: useTLS, Timeout: DefaultNotifyTimeout}
+}
+
+// Name 返回渠道名称
+func (s *SMTPNotifier) Name() string {
+	return s.name
+}
+
+// Send 发送邮件
+func (s *SMTPNotifier) Send(ctx context.Context, msg *Message) error {
+	if len(s.To) == 0 {
+		return fmt.Errorf("smtp notifier %q has no recipients", s.name)
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	dialer := &net.Dialer{Timeout: s.Timeout}
+	var conn net.Conn
+	var err error
+	if s.TLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect smtp server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else if s.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(s.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if !s.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+				return fmt.Errorf("smtp starttls failed: %w", err)
+			}
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range append(append([]string(nil), s.To...), s.Cc...) {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(buildMail(s.From, s.To, s.Cc, msg)); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write mail body: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMail 构建 MIME 邮件内容
+func buildMail(from string, to, cc []string, msg *Message) []byte {
+	contentType, body := "text/plain; charset=UTF-8", msg.Text
+	if msg.HTML != "" {
+		contentType, body = "text/html; charset=UTF-8", msg.HTML
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	if len(cc) > 0 {
+		fmt.Fprintf(&buf, "Cc: %s\r\n", strings.Join(cc, ", "))
+	}
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: %s\r\n\r\n", contentType)
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+// postJSON 以 JSON 方式 POST，非 2xx 响应视为失败；out 不为空时解析响应体
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultNotifyTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post %s returned status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out != nil && len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode response from %s: %w", url, err)
+		}
+	}
+	return nil
+}
+
+// SendWithRetry 发送通知，失败时按指数退避重试，attempts 为总尝试次数
+func SendWithRetry(ctx context.Context, n Notifier, msg *Message, attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.Join(err, ctx.Err())
+			case <-time.After(backoff << (i - 1)):
+			}
+		}
+		if err = n.Send(ctx, msg); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("notifier %q failed after %d attempt(s): %w", n.Name(), attempts, err)
+}
+
+// NewNotifier 根据渠道类型与 Settings 创建通知渠道，所有 HTTP 类渠道均支持 timeout
+//
+// webhook: url, header.<Name>
+// slack: webhook 或 token, channel, username, icon_emoji
+// email: smtp_host, smtp_port, username, password, from, recipients / cc（逗号分隔）, tls
+// dingtalk: webhook, secret, at_mobiles（逗号分隔）, at_all
+// wecom: webhook
+// feishu: webhook, secret
+func (c NotificationChannel) NewNotifier() (Notifier, error) {
+	settings := c.Settings
+	require := func(keys ...string) error {
+		for _, key := range keys {
+			if strings.TrimSpace(settings[key]) == "" {
+				return fmt.Errorf("channel %q: missing setting %q", c.Name, key)
+			}
+		}
+		return nil
+	}
+	parseBool := func(key string) (bool, error) {
+		v := settings[key]
+		if v == "" {
+			return false, nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("channel %q: invalid %s %q", c.Name, key, v)
+		}
+		return b, nil
+	}
+	timeout, err := parseTimeout(settings["timeout"])
+	if err != nil {
+		return nil, fmt.Errorf("channel %q: %w", c.Name, err)
+	}
+	client := &http.Client{Timeout: DefaultNotifyTimeout}
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+
+	switch strings.ToLower(c.Type) {
+	case ChannelWebhook:
+		if err := require("url"); err != nil {
+			return nil, err
+		}
+		headers := map[string]string{}
+		for key, value := range settings {
+			if name, ok := strings.CutPrefix(key, "header."); ok {
+				headers[name] = value
+			}
+		}
+		return NewWebhookNotifier(c.Name, settings["url"], headers, timeout), nil
+	case ChannelSlack:
+		var n *SlackNotifier
+		switch {
+		case settings["webhook"] != "":
+			n = NewSlackWebhookNotifier(c.Name, settings["webhook"], settings["channel"])
+		case settings["token"] != "":
+			if err := require("channel"); err != nil {
+				return nil, err
+			}
+			n = NewSlackBotNotifier(c.Name, settings["token"], settings["channel"])
+		default:
+			return nil, fmt.Errorf("channel %q: slack requires setting \"webhook\" or \"token\"", c.Name)
+		}
+		n.Username, n.IconEmoji, n.Client = settings["username"], settings["icon_emoji"], client
+		return n, nil
+	case ChannelEmail:
+		if err := require("smtp_host", "smtp_port", "recipients"); err != nil {
+			return nil, err
+		}
+		port, err := strconv.Atoi(settings["smtp_port"])
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("channel %q: invalid smtp_port %q", c.Name, settings["smtp_port"])
+		}
+		useTLS, err := parseBool("tls")
+		if err != nil {
+			return nil, err
+		}
+		from := settings["from"]
+		if from == "" && settings["username"] == "" {
+			return nil, fmt.Errorf("channel %q: missing setting \"from\"", c.Name)
+		}
+		n := NewSMTPNotifier(c.Name, settings["smtp_host"], port, settings["username"], settings["password"], from, splitList(settings["recipients"]), useTLS)
+		n.Cc = splitList(settings["cc"])
+		if timeout > 0 {
+			n.Timeout = timeout
+		}
+		return n, nil
+	case ChannelDingTalk:
+		if err := require("webhook"); err != nil {
+			return nil, err
+		}
+		atAll, err := parseBool("at_all")
+		if err != nil {
+			return nil, err
+		}
+		n := NewDingTalkNotifier(c.Name, settings["webhook"], settings["secret"])
+		n.AtMobiles, n.AtAll, n.Client = splitList(settings["at_mobiles"]), atAll, client
+		return n, nil
+	case ChannelWeCom:
+		if err := require("webhook"); err != nil {
+			return nil, err
+		}
+		n := NewWeComNotifier(c.Name, settings["webhook"])
+		n.Client = client
+		return n, nil
+	case ChannelFeishu:
+		if err := require("webhook"); err != nil {
+			return nil, err
+		}
+		n := NewFeishuNotifier(c.Name, settings["webhook"], settings["secret"])
+		n.Client = client
+		return n, nil
+	default:
+		return nil, fmt.Errorf("channel %q has unsupported type %q", c.Name, c.Type)
+	}
+}
+
+// Validate 校验渠道类型与 Settings 是否完整
+func (c NotificationChannel) Validate() error {
+	_, err := c.NewNotifier()
+	return err
+}
+
+// Notifiers 创建全部通知渠道：Webhooks 列表与 Channels，一次性返回所有配置错误
+func (a *Alerting) Notifiers() ([]Notifier, error) {
+	var notifiers []Notifier
+	var errs []error
+	for i, url := range a.Webhooks {
+		notifiers = append(notifiers, NewWebhookNotifier(fmt.Sprintf("webhook-%d", i), url, nil, 0))
+	}
+	for _, channel := range a.Channels {
+		n, err := channel.NewNotifier()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notifiers = append(notifiers, n)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return notifiers, nil
+}
+
+// splitList 拆分逗号分隔的列表并去除空白项
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTimeout 解析超时时间，为空时返回 0
+func parseTimeout(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", value, err)
+	}
+	return timeout, nil
+}
@@ -0,0 +1,266 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\alerting\notifier_test.go
+ * @Description: 通知渠道驱动测试，使用 httptest 与本地 SMTP 替身
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpMail SMTP 替身收到的邮件
+type smtpMail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// smtpStandIn 最小化的本地 SMTP 服务，不支持 AUTH 与 STARTTLS
+type smtpStandIn struct {
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []smtpMail
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStandIn{listener: listener}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) received() []smtpMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMail(nil), s.mails...)
+}
+
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP stand-in")
+
+	var mail smtpMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			mail = smtpMail{From: strings.Trim(cmd[len("MAIL FROM:"):], "<> ")}
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			mail.To = append(mail.To, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// recordingServer 记录请求体与请求头的 httptest 服务
+func recordingServer(t *testing.T, status int) (*httptest.Server, func() (map[string]any, http.Header)) {
+	var mu sync.Mutex
+	var body map[string]any
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body = map[string]any{}
+		json.NewDecoder(r.Body).Decode(&body)
+		header = r.Header.Clone()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() (map[string]any, http.Header) {
+		mu.Lock()
+		defer mu.Unlock()
+		return body, header
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, last := recordingServer(t, http.StatusOK)
+	n := NewWebhookNotifier("ops", server.URL, map[string]string{"X-Token": "abc"}, time.Second)
+
+	require.NoError(t, n.Send(context.Background(), &Message{Subject: "disk", Text: "full"}))
+	body, header := last()
+	assert.Equal(t, "disk", body["subject"])
+	assert.Equal(t, "abc", header.Get("X-Token"))
+
+	require.NoError(t, n.Send(context.Background(), &Message{Payload: map[string]int{"count": 2}}))
+	body, _ = last()
+	assert.Equal(t, map[string]any{"count": float64(2)}, body, "Payload 作为请求体")
+
+	failing, _ := recordingServer(t, http.StatusBadGateway)
+	err := NewWebhookNotifier("bad", failing.URL, nil, 0).Send(context.Background(), &Message{})
+	assert.ErrorContains(t, err, "status 502")
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, last := recordingServer(t, http.StatusOK)
+
+	hook := NewSlackWebhookNotifier("slack", server.URL, "#alerts")
+	require.NoError(t, hook.Send(context.Background(), &Message{Subject: "CPU", Text: "high"}))
+	body, header := last()
+	assert.Equal(t, "*CPU*\nhigh", body["text"])
+	assert.Equal(t, "#alerts", body["channel"])
+	assert.Empty(t, header.Get("Authorization"))
+
+	bot := NewSlackBotNotifier("slack-bot", "xoxb-1", "#ops")
+	bot.APIURL = server.URL
+	bot.IconEmoji = ":fire:"
+	require.NoError(t, bot.Send(context.Background(), &Message{Text: "down"}))
+	body, header = last()
+	assert.Equal(t, "Bearer xoxb-1", header.Get("Authorization"))
+	assert.Equal(t, ":fire:", body["icon_emoji"])
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	smtpServer := newSMTPStandIn(t)
+	n := NewSMTPNotifier("mail", "127.0.0.1", smtpServer.port(), "", "", "alert@example.com", []string{"a@example.com", "b@example.com"}, false)
+
+	require.NoError(t, n.Send(context.Background(), &Message{Subject: "磁盘告警", Text: "line1\nline2", HTML: "<b>disk</b>"}))
+	mails := smtpServer.received()
+	require.Len(t, mails, 1)
+	assert.Equal(t, "alert@example.com", mails[0].From)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, mails[0].To)
+	assert.Contains(t, mails[0].Data, "Subject: =?UTF-8?b?")
+	assert.Contains(t, mails[0].Data, "Content-Type: text/html; charset=UTF-8")
+	assert.Contains(t, mails[0].Data, "<b>disk</b>")
+
+	n.To = nil
+	assert.ErrorContains(t, n.Send(context.Background(), &Message{}), "no recipients")
+}
+
+// flakyNotifier 前 failures 次发送失败
+type flakyNotifier struct {
+	name     string
+	failures int32
+	calls    atomic.Int32
+}
+
+func (f *flakyNotifier) Name() string {
+	if f.name == "" {
+		return "flaky"
+	}
+	return f.name
+}
+
+func (f *flakyNotifier) Send(context.Context, *Message) error {
+	if f.calls.Add(1) <= f.failures {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+func TestSendWithRetry(t *testing.T) {
+	n := &flakyNotifier{failures: 2}
+	require.NoError(t, SendWithRetry(context.Background(), n, &Message{}, 3, time.Millisecond))
+	assert.Equal(t, int32(3), n.calls.Load())
+
+	n = &flakyNotifier{failures: 5}
+	err := SendWithRetry(context.Background(), n, &Message{}, 2, time.Millisecond)
+	assert.ErrorContains(t, err, `notifier "flaky" failed after 2 attempt(s)`)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestAlerting_Notifiers(t *testing.T) {
+	smtpServer := newSMTPStandIn(t)
+	a := Default().
+		AddWebhook("http://hooks.example.com/a").
+		AddSlackChannel("slack", "http://hooks.slack.example.com/x", "#ops").
+		AddEmailChannel("mail", "127.0.0.1", strconv.Itoa(smtpServer.port()), "ops@example.com", "", []string{"a@example.com", " b@example.com"}).
+		AddChannel("hook", ChannelWebhook, map[string]string{"url": "http://x", "timeout": "2s", "header.X-Key": "k"})
+
+	notifiers, err := a.Notifiers()
+	require.NoError(t, err)
+	require.Len(t, notifiers, 4)
+	assert.Equal(t, "webhook-0", notifiers[0].Name())
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, notifiers[2].(*SMTPNotifier).To)
+	assert.Equal(t, "ops@example.com", notifiers[2].(*SMTPNotifier).From)
+	hook := notifiers[3].(*WebhookNotifier)
+	assert.Equal(t, map[string]string{"X-Key": "k"}, hook.Headers)
+	assert.Equal(t, 2*time.Second, hook.Client.Timeout)
+
+	a = Default().
+		AddChannel("w", ChannelWebhook, nil).
+		AddChannel("s", ChannelSlack, map[string]string{"token": "t"}).
+		AddChannel("m", ChannelEmail, map[string]string{"smtp_host": "h", "smtp_port": "x", "recipients": "a@b"}).
+		AddChannel("p", "pager", nil)
+	_, err = a.Notifiers()
+	require.Error(t, err)
+	for _, expected := range []string{
+		`channel "w": missing setting "url"`,
+		`channel "s": missing setting "channel"`,
+		`channel "m": invalid smtp_port "x"`,
+		`channel "p" has unsupported type "pager"`,
+	} {
+		assert.Contains(t, err.Error(), expected)
+	}
+}
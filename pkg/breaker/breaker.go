@@ -9,6 +9,9 @@
 package breaker
 
 import (
+	"errors"
+	"fmt"
+	"path"
 	"time"
 
 	"github.com/kamalyes/go-config/internal"
@@ -83,9 +86,39 @@ func (cb *CircuitBreaker) Init() error {
 	return internal.ValidateStruct(cb)
 }
 
-// Validate 验证配置
+// Validate 验证配置，启用时校验阈值、窗口与路径模式
 func (cb *CircuitBreaker) Validate() error {
-	return internal.ValidateStruct(cb)
+	if err := internal.ValidateStruct(cb); err != nil {
+		return err
+	}
+	if !cb.Enabled {
+		return nil
+	}
+	var errs []error
+	positive := []struct {
+		name  string
+		value int64
+	}{
+		{"failure-threshold", int64(cb.FailureThreshold)},
+		{"success-threshold", int64(cb.SuccessThreshold)},
+		{"timeout", cb.Timeout},
+		{"sliding-window-size", int64(cb.SlidingWindowSize)},
+		{"sliding-window-bucket", cb.SlidingWindowBucket},
+	}
+	for _, field := range positive {
+		if field.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", field.name, field.value))
+		}
+	}
+	if cb.VolumeThreshold < 0 {
+		errs = append(errs, fmt.Errorf("volume-threshold must not be negative, got %d", cb.VolumeThreshold))
+	}
+	for _, pattern := range append(append([]string(nil), cb.PreventionPaths...), cb.ExcludePaths...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid path pattern %q: %w", pattern, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // GetLabel 获取配置标签
@@ -0,0 +1,52 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\breaker\clock.go
+ * @Description: 断路器时钟，FakeClock 用于确定性测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 断路器使用的时钟
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 系统时钟
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FakeClock 手动推进的时钟，并发安全
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock 创建从 start 开始的假时钟
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now 返回当前时间
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance 推进时钟
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,215 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\breaker\middleware.go
+ * @Description: 断路器 HTTP 中间件，按防护路径分别熔断，支持热更新
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package breaker
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kamalyes/go-config/internal"
+)
+
+// allPaths PreventionPaths 为空时保护全部路径所用的模式
+const allPaths = "/**"
+
+// Middleware 断路器 HTTP 中间件
+//
+// 请求路径命中 ExcludePaths 时直接放行，否则按命中的第一个 PreventionPaths 模式
+// 使用对应的断路器（PreventionPaths 为空时全部路径共用一个断路器）；
+// 响应状态码 >= 500 或处理器 panic 视为失败，熔断时返回 503 与 Retry-After
+type Middleware struct {
+	mu       sync.RWMutex
+	config   *CircuitBreaker
+	breakers map[string]*Breaker
+	clock    Clock
+	onChange func(name string, from, to State)
+}
+
+// NewMiddleware 校验配置并创建中间件，断路器在首次命中时创建
+func NewMiddleware(cfg *CircuitBreaker) (*Middleware, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Middleware{config: cfg.Clone().(*CircuitBreaker), breakers: make(map[string]*Breaker), clock: systemClock{}}, nil
+}
+
+// WithClock 设置时钟，测试中使用 FakeClock
+func (m *Middleware) WithClock(clock Clock) *Middleware {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+	for _, b := range m.breakers {
+		b.WithClock(clock)
+	}
+	return m
+}
+
+// WithStateChange 设置状态变化回调，name 为 "<module-name>:<路径模式>"
+func (m *Middleware) WithStateChange(fn func(name string, from, to State)) *Middleware {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = fn
+	for _, b := range m.breakers {
+		b.WithStateChange(fn)
+	}
+	return m
+}
+
+// Reconfigure 热更新配置：保留现有断路器的状态并更新阈值，移除不再配置的路径模式
+func (m *Middleware) Reconfigure(cfg *CircuitBreaker) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = cfg.Clone().(*CircuitBreaker)
+	patterns := m.patterns()
+	for pattern, b := range m.breakers {
+		if !slices.Contains(patterns, pattern) {
+			delete(m.breakers, pattern)
+			continue
+		}
+		b.Reconfigure(m.settings(pattern))
+	}
+	return nil
+}
+
+// Breaker 返回路径模式对应的断路器，尚未命中过时返回 nil
+func (m *Middleware) Breaker(pattern string) *Breaker {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.breakers[pattern]
+}
+
+// Handler 包装处理器
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := m.breakerFor(r.URL.Path)
+		if b == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		done, err := b.Allow()
+		if err != nil {
+			if retry := b.RetryAfter(); retry > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			}
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		success := false
+		defer func() { done(success) }()
+		next.ServeHTTP(rec, r)
+		success = rec.status < http.StatusInternalServerError
+	})
+}
+
+// breakerFor 返回路径对应的断路器，不受保护时返回 nil
+func (m *Middleware) breakerFor(p string) *Breaker {
+	m.mu.RLock()
+	if !m.config.Enabled || matchAny(m.config.ExcludePaths, p) {
+		m.mu.RUnlock()
+		return nil
+	}
+	pattern, ok := "", false
+	for _, candidate := range m.patterns() {
+		if matchPath(candidate, p) {
+			pattern, ok = candidate, true
+			break
+		}
+	}
+	if !ok {
+		m.mu.RUnlock()
+		return nil
+	}
+	b := m.breakers[pattern]
+	m.mu.RUnlock()
+	if b != nil {
+		return b
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b = m.breakers[pattern]; b == nil {
+		b = NewBreaker(m.settings(pattern)).WithClock(m.clock).WithStateChange(m.onChange)
+		m.breakers[pattern] = b
+	}
+	return b
+}
+
+// patterns 返回防护路径模式，调用方需持有锁
+func (m *Middleware) patterns() []string {
+	if len(m.config.PreventionPaths) == 0 {
+		return []string{allPaths}
+	}
+	return m.config.PreventionPaths
+}
+
+// settings 返回路径模式对应的断路器参数，调用方需持有锁
+func (m *Middleware) settings(pattern string) Settings {
+	s := m.config.Settings()
+	s.Name = m.config.ModuleName + ":" + pattern
+	return s
+}
+
+// statusRecorder 记录响应状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = code, true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// matchPath 匹配路径模式
+//
+// 以 "/**" 结尾的模式匹配该前缀下的所有路径；包含 * ? [ 的模式按 path.Match 逐段匹配；
+// 其余模式匹配自身及其子路径，如 "/api/" 与 "/api" 均匹配 "/api/users"
+func matchPath(pattern, p string) bool {
+	if internal.IsPathGlob(pattern) {
+		return internal.MatchPath(pattern, p)
+	}
+	prefix := strings.TrimSuffix(pattern, "/")
+	return p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// matchAny 路径是否命中任一模式
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\breaker\middleware_test.go
+ * @Description: 断路器 HTTP 中间件测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package breaker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMiddleware(t *testing.T) (*Middleware, *FakeClock, *transitions, http.Handler) {
+	cfg := Default()
+	cfg.FailureThreshold = 2
+	cfg.SuccessThreshold = 1
+	cfg.VolumeThreshold = 2
+	cfg.Timeout = int64(5 * time.Second)
+	cfg.PreventionPaths = []string{"/api/", "/rpc/*/call"}
+	cfg.ExcludePaths = []string{"/api/health/**"}
+
+	clock := NewFakeClock(time.Unix(0, 0))
+	changes := &transitions{}
+	m, err := NewMiddleware(cfg)
+	require.NoError(t, err)
+	m.WithClock(clock).WithStateChange(changes.record)
+
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return m, clock, changes, handler
+}
+
+func serve(h http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestMiddleware_TripsPerPattern(t *testing.T) {
+	m, clock, changes, h := newTestMiddleware(t)
+
+	serve(h, "/api/users?fail=1")
+	serve(h, "/api/orders?fail=1")
+	rec := serve(h, "/api/users")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get("Retry-After"))
+
+	// 排除路径与其他模式不受影响
+	assert.Equal(t, http.StatusOK, serve(h, "/api/health/live").Code)
+	assert.Equal(t, http.StatusOK, serve(h, "/rpc/user/call").Code)
+	assert.Equal(t, http.StatusOK, serve(h, "/static/app.js").Code)
+	assert.Nil(t, m.Breaker("/api/health/**"))
+	assert.Equal(t, StateClosed, m.Breaker("/rpc/*/call").State())
+
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, http.StatusOK, serve(h, "/api/users").Code)
+	assert.Equal(t, StateClosed, m.Breaker("/api/").State())
+	assert.Equal(t, []string{
+		"circuit_breaker:/api/:closed->open",
+		"circuit_breaker:/api/:open->half-open",
+		"circuit_breaker:/api/:half-open->closed",
+	}, changes.get())
+}
+
+func TestMiddleware_PanicCountsAsFailure(t *testing.T) {
+	m, _, _, _ := newTestMiddleware(t)
+	h := m.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+	for i := 0; i < 2; i++ {
+		assert.Panics(t, func() { serve(h, "/api/x") })
+	}
+	assert.Equal(t, StateOpen, m.Breaker("/api/").State())
+}
+
+func TestMiddleware_Reconfigure(t *testing.T) {
+	m, _, _, h := newTestMiddleware(t)
+	serve(h, "/api/a?fail=1")
+	serve(h, "/rpc/a/call")
+	b := m.Breaker("/api/")
+
+	cfg := Default()
+	cfg.FailureThreshold = 1
+	cfg.VolumeThreshold = 1
+	cfg.PreventionPaths = []string{"/api/"}
+	require.NoError(t, m.Reconfigure(cfg))
+	assert.Same(t, b, m.Breaker("/api/"), "保留已有断路器")
+	assert.Equal(t, StateOpen, b.State(), "新阈值立即生效")
+	assert.Nil(t, m.Breaker("/rpc/*/call"), "移除不再配置的模式")
+
+	cfg.Disable()
+	require.NoError(t, m.Reconfigure(cfg))
+	assert.Equal(t, http.StatusOK, serve(h, "/api/a").Code, "禁用后直接放行")
+
+	bad := Default()
+	bad.PreventionPaths = []string{"/api/["}
+	bad.SlidingWindowSize = 0
+	err := m.Reconfigure(bad)
+	assert.ErrorContains(t, err, `invalid path pattern "/api/["`)
+	assert.ErrorContains(t, err, "sliding-window-size must be positive")
+	_, err = NewMiddleware(bad)
+	assert.Error(t, err)
+}
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		matched       bool
+	}{
+		{"/api/", "/api", true},
+		{"/api/", "/api/users/1", true},
+		{"/api", "/apix", false},
+		{"/api/*/detail", "/api/1/detail", true},
+		{"/api/*/detail", "/api/1/2/detail", false},
+		{"/static/**", "/static/js/app.js", true},
+		{"/static/**", "/static", true},
+		{"/**", "/anything", true},
+		{"/v?/ping", "/v1/ping", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.matched, matchPath(c.pattern, c.path), "%s ~ %s", c.pattern, c.path)
+	}
+}
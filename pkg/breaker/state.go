@@ -0,0 +1,384 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\breaker\state.go
+ * @Description: 断路器状态机：closed / open / half-open 与分桶滑动窗口
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// State 断路器状态
+type State int
+
+const (
+	StateClosed   State = iota // 关闭：正常放行并统计
+	StateOpen                  // 打开：拒绝请求直到超时
+	StateHalfOpen              // 半开：放行少量探测请求
+)
+
+// String 返回状态名称
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// 断路器拒绝请求的原因
+var (
+	ErrOpen            = errors.New("circuit breaker is open")
+	ErrTooManyRequests = errors.New("circuit breaker is half-open and probe quota is exhausted")
+)
+
+// 未配置时的默认值
+const (
+	DefaultFailureThreshold = 5
+	DefaultSuccessThreshold = 1
+	DefaultTimeout          = 30 * time.Second
+	DefaultWindowSize       = 10
+	DefaultBucketDuration   = time.Second
+)
+
+// Settings 断路器运行参数，可由 CircuitBreaker、WebSocketBreaker 或 jobs.BreakerCfg 构建
+type Settings struct {
+	Name             string
+	FailureThreshold int           // 窗口内（连续模式下为连续）失败次数达到该值时熔断
+	SuccessThreshold int           // 半开状态成功该次数后恢复，同时也是半开状态的并发探测上限
+	Timeout          time.Duration // 熔断持续时间，之后进入半开状态
+	VolumeThreshold  int           // 窗口内最小请求量，不足时不熔断，连续模式下忽略
+	WindowSize       int           // 滑动窗口桶数量
+	BucketDuration   time.Duration // 每个桶的时长，窗口总时长为 WindowSize * BucketDuration
+	Consecutive      bool          // 按连续失败次数熔断，不使用滑动窗口
+}
+
+// normalize 为零值字段填充默认值
+func (s Settings) normalize() Settings {
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = DefaultFailureThreshold
+	}
+	if s.SuccessThreshold <= 0 {
+		s.SuccessThreshold = DefaultSuccessThreshold
+	}
+	if s.Timeout <= 0 {
+		s.Timeout = DefaultTimeout
+	}
+	if s.WindowSize <= 0 {
+		s.WindowSize = DefaultWindowSize
+	}
+	if s.BucketDuration <= 0 {
+		s.BucketDuration = DefaultBucketDuration
+	}
+	return s
+}
+
+// Settings 由断路器配置构建运行参数，Timeout 与 SlidingWindowBucket 单位为纳秒
+func (cb *CircuitBreaker) Settings() Settings {
+	return Settings{
+		Name:             cb.ModuleName,
+		FailureThreshold: cb.FailureThreshold,
+		SuccessThreshold: cb.SuccessThreshold,
+		Timeout:          time.Duration(cb.Timeout),
+		VolumeThreshold:  cb.VolumeThreshold,
+		WindowSize:       cb.SlidingWindowSize,
+		BucketDuration:   time.Duration(cb.SlidingWindowBucket),
+	}
+}
+
+// Settings 由 WebSocket 断路器配置构建运行参数，按连续失败次数熔断
+func (wb *WebSocketBreaker) Settings() Settings {
+	return Settings{
+		Name:             wb.ModuleName,
+		FailureThreshold: wb.FailureThreshold,
+		SuccessThreshold: wb.SuccessThreshold,
+		Timeout:          time.Duration(wb.Timeout),
+		Consecutive:      true,
+	}
+}
+
+// Counts 当前统计
+type Counts struct {
+	Requests            int // 窗口内请求数
+	Failures            int // 窗口内失败数
+	ConsecutiveFailures int // 连续失败数
+}
+
+// bucket 窗口桶，epoch 为桶序号（时间 / 桶时长）
+type bucket struct {
+	epoch    int64
+	requests int
+	failures int
+}
+
+// window 分桶滑动窗口，过期桶在写入时惰性重置
+type window struct {
+	buckets []bucket
+	size    time.Duration
+}
+
+func newWindow(n int, size time.Duration) *window {
+	return &window{buckets: make([]bucket, n), size: size}
+}
+
+func (w *window) epoch(now time.Time) int64 {
+	return now.UnixNano() / int64(w.size)
+}
+
+func (w *window) add(now time.Time, success bool) {
+	epoch := w.epoch(now)
+	b := &w.buckets[epoch%int64(len(w.buckets))]
+	if b.epoch != epoch {
+		*b = bucket{epoch: epoch}
+	}
+	b.requests++
+	if !success {
+		b.failures++
+	}
+}
+
+func (w *window) sum(now time.Time) (requests, failures int) {
+	current := w.epoch(now)
+	oldest := current - int64(len(w.buckets))
+	for _, b := range w.buckets {
+		if b.epoch > oldest && b.epoch <= current {
+			requests += b.requests
+			failures += b.failures
+		}
+	}
+	return requests, failures
+}
+
+func (w *window) reset() {
+	clear(w.buckets)
+}
+
+// transition 待通知的状态变化
+type transition struct {
+	from, to State
+}
+
+// Breaker 断路器状态机，并发安全
+//
+// closed 状态下窗口内请求量达到 VolumeThreshold 且失败数达到 FailureThreshold 时熔断；
+// open 状态持续 Timeout 后进入 half-open，放行至多 SuccessThreshold 个探测请求，
+// 全部成功则恢复 closed，任一失败则重新 open
+type Breaker struct {
+	mu          sync.Mutex
+	settings    Settings
+	clock       Clock
+	onChange    func(name string, from, to State)
+	state       State
+	generation  uint64 // 每次状态变化递增，丢弃跨状态的过期结果
+	openedAt    time.Time
+	window      *window
+	consecutive int
+	probes      int // 半开状态已放行的探测请求数
+	successes   int // 半开状态的成功数
+	pending     []transition
+}
+
+// NewBreaker 创建断路器，零值参数使用默认值
+func NewBreaker(s Settings) *Breaker {
+	s = s.normalize()
+	return &Breaker{settings: s, clock: systemClock{}, window: newWindow(s.WindowSize, s.BucketDuration)}
+}
+
+// WithClock 设置时钟，测试中使用 FakeClock
+func (b *Breaker) WithClock(clock Clock) *Breaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = clock
+	return b
+}
+
+// WithStateChange 设置状态变化回调，回调在锁外执行
+func (b *Breaker) WithStateChange(fn func(name string, from, to State)) *Breaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = fn
+	return b
+}
+
+// Name 返回断路器名称
+func (b *Breaker) Name() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.settings.Name
+}
+
+// State 返回当前状态，open 超时后返回 half-open
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.unlock()
+	return b.currentState(b.clock.Now())
+}
+
+// Counts 返回当前统计
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	requests, failures := b.window.sum(b.clock.Now())
+	return Counts{Requests: requests, Failures: failures, ConsecutiveFailures: b.consecutive}
+}
+
+// RetryAfter 返回 open 状态剩余时间，其他状态返回 0
+func (b *Breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.unlock()
+	now := b.clock.Now()
+	if b.currentState(now) != StateOpen {
+		return 0
+	}
+	return b.openedAt.Add(b.settings.Timeout).Sub(now)
+}
+
+// Allow 申请执行一次请求，成功时返回的 done 必须以请求结果调用一次
+func (b *Breaker) Allow() (done func(success bool), err error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	switch b.currentState(b.clock.Now()) {
+	case StateOpen:
+		return nil, ErrOpen
+	case StateHalfOpen:
+		if b.probes >= b.settings.SuccessThreshold {
+			return nil, ErrTooManyRequests
+		}
+		b.probes++
+	}
+	generation := b.generation
+	return func(success bool) { b.record(generation, success) }, nil
+}
+
+// Execute 通过断路器执行 fn，fn 返回错误视为失败；被拒绝时返回 ErrOpen 或 ErrTooManyRequests
+func (b *Breaker) Execute(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	success := false
+	defer func() { done(success) }()
+	err = fn()
+	success = err == nil
+	return err
+}
+
+// Reconfigure 热更新阈值，保留当前状态；窗口尺寸变化时清空窗口统计
+func (b *Breaker) Reconfigure(s Settings) {
+	s = s.normalize()
+	b.mu.Lock()
+	defer b.unlock()
+	if s.WindowSize != b.settings.WindowSize || s.BucketDuration != b.settings.BucketDuration {
+		b.window = newWindow(s.WindowSize, s.BucketDuration)
+	}
+	b.settings = s
+	if b.state == StateClosed {
+		b.tripIfNeeded(b.clock.Now())
+	}
+}
+
+// Reset 强制恢复 closed 状态并清空统计
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.unlock()
+	b.setState(StateClosed, b.clock.Now())
+}
+
+// record 记录请求结果，调用方不持有锁
+func (b *Breaker) record(generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	now := b.clock.Now()
+	state := b.currentState(now)
+	if generation != b.generation {
+		return
+	}
+	switch state {
+	case StateClosed:
+		b.window.add(now, success)
+		if success {
+			b.consecutive = 0
+		} else {
+			b.consecutive++
+		}
+		b.tripIfNeeded(now)
+	case StateHalfOpen:
+		if !success {
+			b.setState(StateOpen, now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.settings.SuccessThreshold {
+			b.setState(StateClosed, now)
+		}
+	}
+}
+
+// tripIfNeeded closed 状态下检查是否达到熔断条件，调用方需持有锁
+func (b *Breaker) tripIfNeeded(now time.Time) {
+	if b.settings.Consecutive {
+		if b.consecutive >= b.settings.FailureThreshold {
+			b.setState(StateOpen, now)
+		}
+		return
+	}
+	requests, failures := b.window.sum(now)
+	if requests >= b.settings.VolumeThreshold && failures >= b.settings.FailureThreshold {
+		b.setState(StateOpen, now)
+	}
+}
+
+// currentState 返回当前状态，open 超时后转入 half-open，调用方需持有锁
+func (b *Breaker) currentState(now time.Time) State {
+	if b.state == StateOpen && !now.Before(b.openedAt.Add(b.settings.Timeout)) {
+		b.setState(StateHalfOpen, now)
+	}
+	return b.state
+}
+
+// setState 切换状态并重置对应统计，调用方需持有锁
+func (b *Breaker) setState(to State, now time.Time) {
+	from := b.state
+	b.state = to
+	b.generation++
+	b.probes, b.successes = 0, 0
+	switch to {
+	case StateClosed:
+		b.window.reset()
+		b.consecutive = 0
+	case StateOpen:
+		b.openedAt = now
+	}
+	if from != to {
+		b.pending = append(b.pending, transition{from: from, to: to})
+	}
+}
+
+// unlock 释放锁并在锁外触发状态变化回调
+func (b *Breaker) unlock() {
+	pending, fn, name := b.pending, b.onChange, b.settings.Name
+	b.pending = nil
+	b.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	for _, t := range pending {
+		fn(name, t.from, t.to)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\breaker\state_test.go
+ * @Description: 断路器状态机测试，使用 FakeClock 推进时间
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBackend = errors.New("backend failed")
+
+// transitions 记录状态变化
+type transitions struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (t *transitions) record(name string, from, to State) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.list = append(t.list, name+":"+from.String()+"->"+to.String())
+}
+
+func (t *transitions) get() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.list...)
+}
+
+func fail(b *Breaker) error    { return b.Execute(func() error { return errBackend }) }
+func succeed(b *Breaker) error { return b.Execute(func() error { return nil }) }
+
+func TestBreaker_WindowLifecycle(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC))
+	changes := &transitions{}
+	b := NewBreaker(Settings{
+		Name: "api", FailureThreshold: 3, SuccessThreshold: 2, Timeout: 10 * time.Second,
+		VolumeThreshold: 5, WindowSize: 4, BucketDuration: time.Second,
+	}).WithClock(clock).WithStateChange(changes.record)
+
+	// 请求量不足时不熔断
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, fail(b), errBackend)
+	}
+	assert.Equal(t, StateClosed, b.State())
+	require.NoError(t, succeed(b))
+	assert.Equal(t, Counts{Requests: 4, Failures: 3}, b.Counts())
+
+	// 第 5 个请求达到请求量阈值后熔断
+	require.NoError(t, succeed(b))
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, succeed(b), ErrOpen)
+	assert.Equal(t, 10*time.Second, b.RetryAfter())
+
+	// 超时后半开，探测数量受 SuccessThreshold 限制
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+	done1, err := b.Allow()
+	require.NoError(t, err)
+	done2, err := b.Allow()
+	require.NoError(t, err)
+	_, err = b.Allow()
+	assert.ErrorIs(t, err, ErrTooManyRequests)
+	done1(true)
+	assert.Equal(t, StateHalfOpen, b.State())
+	done2(true)
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, Counts{}, b.Counts(), "恢复时清空窗口")
+
+	// 半开探测失败重新熔断
+	for i := 0; i < 5; i++ {
+		fail(b)
+	}
+	clock.Advance(10 * time.Second)
+	assert.ErrorIs(t, fail(b), errBackend)
+	assert.Equal(t, StateOpen, b.State())
+
+	assert.Equal(t, []string{
+		"api:closed->open", "api:open->half-open", "api:half-open->closed",
+		"api:closed->open", "api:open->half-open", "api:half-open->open",
+	}, changes.get())
+}
+
+func TestBreaker_WindowSlides(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	b := NewBreaker(Settings{FailureThreshold: 3, WindowSize: 3, BucketDuration: time.Second}).WithClock(clock)
+
+	fail(b)
+	fail(b)
+	clock.Advance(3 * time.Second) // 前两次失败所在桶滑出窗口
+	fail(b)
+	assert.Equal(t, Counts{Requests: 1, Failures: 1, ConsecutiveFailures: 3}, b.Counts())
+	assert.Equal(t, StateClosed, b.State())
+
+	clock.Advance(time.Second)
+	fail(b)
+	fail(b)
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestBreaker_ConsecutiveAndStaleResults(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	b := NewBreaker(Settings{FailureThreshold: 2, Timeout: time.Second, Consecutive: true}).WithClock(clock)
+
+	slow, err := b.Allow()
+	require.NoError(t, err)
+	fail(b)
+	succeed(b)
+	fail(b)
+	assert.Equal(t, StateClosed, b.State(), "成功请求重置连续失败计数")
+	fail(b)
+	assert.Equal(t, StateOpen, b.State())
+
+	// 熔断前发出的请求结果不影响新状态
+	clock.Advance(time.Second)
+	slow(true)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	b.Reset()
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_Reconfigure(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	b := NewBreaker(Settings{FailureThreshold: 5, Timeout: time.Minute}).WithClock(clock)
+	fail(b)
+	fail(b)
+
+	// 调低阈值立即生效
+	b.Reconfigure(Settings{FailureThreshold: 2, Timeout: 5 * time.Second})
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, 5*time.Second, b.RetryAfter())
+
+	// 窗口尺寸变化时清空统计
+	b.Reset()
+	fail(b)
+	b.Reconfigure(Settings{FailureThreshold: 2, WindowSize: 20})
+	assert.Equal(t, 0, b.Counts().Requests)
+}
+
+func TestSettingsFromConfig(t *testing.T) {
+	s := Default().Settings()
+	assert.Equal(t, Settings{
+		Name: "circuit_breaker", FailureThreshold: 5, SuccessThreshold: 2, Timeout: 30 * time.Second,
+		VolumeThreshold: 10, WindowSize: 100, BucketDuration: time.Second,
+	}, s)
+
+	ws := DefaultWebSocketBreaker().Settings()
+	assert.True(t, ws.Consecutive)
+	assert.Equal(t, 30*time.Second, ws.Timeout)
+
+	assert.Equal(t, DefaultFailureThreshold, NewBreaker(Settings{}).settings.FailureThreshold)
+}
@@ -12,7 +12,11 @@
 package cors
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/kamalyes/go-config/internal"
+	"github.com/kamalyes/go-logger"
 	"github.com/kamalyes/go-toolbox/pkg/syncx"
 )
 
@@ -74,10 +78,29 @@ func (c *Cors) Set(data interface{}) {
 }
 
 // Validate 检查 Cors 配置的有效性
+// 允许凭证且允许任意来源时只记录警告，以免已有配置无法加载；NewHandler 构建时会拒绝该组合
 func (c *Cors) Validate() error {
+	if err := c.validateCredentials(); err != nil {
+		logger.GetGlobalLogger().Warn("cors: %v, NewHandler will reject this config", err)
+	}
 	return internal.ValidateStruct(c)
 }
 
+// validateCredentials 允许凭证时不能同时允许任意来源，避免任意站点携带凭证访问
+func (c *Cors) validateCredentials() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	anyOrigin := c.AllowedAllOrigins
+	for _, origin := range c.AllowedOrigins {
+		anyOrigin = anyOrigin || strings.TrimSpace(origin) == "*"
+	}
+	if anyOrigin {
+		return fmt.Errorf("allow-credentials cannot be combined with a wildcard origin")
+	}
+	return nil
+}
+
 // DefaultCors 返回默认Cors配置
 func DefaultCors() Cors {
 	return Cors{
@@ -114,6 +114,15 @@ func TestCors_Validate(t *testing.T) {
 	cors := Default()
 	err := cors.Validate()
 	assert.NoError(t, err)
+
+	// 凭证与任意来源同时启用只警告，已有配置仍可加载（构建中间件时拒绝）
+	err = Default().WithAllowedOrigins(nil).WithAllowedAllOrigins(true).WithAllowCredentials(true).Validate()
+	assert.NoError(t, err)
+	assert.Error(t, Default().validateCredentials())
+
+	err = Default().WithAllowedOrigins([]string{"https://app.example.com"}).WithAllowCredentials(true).Validate()
+	assert.NoError(t, err)
+	assert.NoError(t, Default().WithAllowCredentials(false).validateCredentials())
 }
 
 func TestCors_ChainedCalls(t *testing.T) {
@@ -0,0 +1,258 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\cors\handler.go
+ * @Description: CORS 规则评估与 net/http 中间件
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultMethods 未配置 AllowedMethods 时允许的简单方法
+var defaultMethods = []string{http.MethodGet, http.MethodPost, http.MethodHead}
+
+// wildcardOrigin 子域名通配来源，如 https://*.example.com
+type wildcardOrigin struct {
+	prefix, suffix string
+}
+
+// match 通配符部分至少匹配一个字符
+func (w wildcardOrigin) match(origin string) bool {
+	return len(origin) > len(w.prefix)+len(w.suffix) &&
+		strings.HasPrefix(origin, w.prefix) && strings.HasSuffix(origin, w.suffix)
+}
+
+// Evaluator 由 Cors 配置编译的跨域规则
+type Evaluator struct {
+	anyOrigin   bool
+	origins     []string
+	wildcards   []wildcardOrigin
+	anyMethod   bool
+	methods     []string
+	anyHeader   bool
+	headers     []string
+	exposed     string
+	credentials bool
+	maxAge      string
+	status      int
+}
+
+// NewEvaluator 编译 Cors 配置
+//
+// 来源支持精确匹配（忽略大小写）、`*` 与 `https://*.example.com` 形式的子域名通配；
+// AllowCredentials 与任意来源同时启用时返回错误，避免任意站点携带凭证访问
+func NewEvaluator(cfg *Cors) (*Evaluator, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cors config is nil")
+	}
+	if err := cfg.validateCredentials(); err != nil {
+		return nil, err
+	}
+
+	e := &Evaluator{
+		anyOrigin:   cfg.AllowedAllOrigins,
+		anyMethod:   cfg.AllowedAllMethods,
+		credentials: cfg.AllowCredentials,
+		status:      cfg.OptionsResponseCode,
+		exposed:     strings.Join(cfg.ExposedHeaders, ", "),
+	}
+	if e.status == 0 {
+		e.status = http.StatusNoContent
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch n := strings.Count(origin, "*"); {
+		case origin == "":
+		case origin == "*":
+			e.anyOrigin = true
+		case n == 1:
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			e.wildcards = append(e.wildcards, wildcardOrigin{prefix: prefix, suffix: suffix})
+		case n > 1:
+			return nil, fmt.Errorf("invalid allowed origin %q: only one wildcard is allowed", origin)
+		default:
+			e.origins = append(e.origins, origin)
+		}
+	}
+
+	for _, method := range cfg.AllowedMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method == "*" {
+			e.anyMethod = true
+		} else if method != "" {
+			e.methods = append(e.methods, method)
+		}
+	}
+	if len(e.methods) == 0 {
+		e.methods = defaultMethods
+	}
+
+	for _, header := range cfg.AllowedHeaders {
+		header = strings.TrimSpace(header)
+		if header == "*" {
+			e.anyHeader = true
+		} else if header != "" {
+			e.headers = append(e.headers, http.CanonicalHeaderKey(header))
+		}
+	}
+
+	if maxAge := strings.TrimSpace(cfg.MaxAge); maxAge != "" {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			d, derr := time.ParseDuration(maxAge)
+			if derr != nil {
+				return nil, fmt.Errorf("invalid max-age %q: %w", cfg.MaxAge, err)
+			}
+			seconds = int(d / time.Second)
+		}
+		if seconds > 0 {
+			e.maxAge = strconv.Itoa(seconds)
+		}
+	}
+	return e, nil
+}
+
+// NewHandler 根据 Cors 配置创建 net/http 中间件，未启用时原样返回下一个处理器
+func NewHandler(cfg *Cors) (func(http.Handler) http.Handler, error) {
+	if cfg != nil && !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+	e, err := NewEvaluator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return e.Handler, nil
+}
+
+// OriginAllowed 检查来源是否允许
+func (e *Evaluator) OriginAllowed(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	if e.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if slices.Contains(e.origins, origin) {
+		return true
+	}
+	for _, w := range e.wildcards {
+		if w.match(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// MethodAllowed 检查方法是否允许，OPTIONS 始终允许
+func (e *Evaluator) MethodAllowed(method string) bool {
+	method = strings.ToUpper(method)
+	return e.anyMethod || method == http.MethodOptions || slices.Contains(e.methods, method)
+}
+
+// HeadersAllowed 检查预检请求声明的头部是否全部允许
+func (e *Evaluator) HeadersAllowed(headers []string) bool {
+	if e.anyHeader {
+		return true
+	}
+	for _, header := range headers {
+		if !slices.Contains(e.headers, http.CanonicalHeaderKey(header)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler 包装处理器：应答预检请求，并为跨域请求添加响应头
+//
+// 预检请求不会转发给 next；来源、方法或头部不被允许时仅返回状态码而不带 CORS 头，由浏览器拦截
+func (e *Evaluator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			e.preflight(w, r)
+			return
+		}
+		e.actual(w, r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight 应答预检请求
+func (e *Evaluator) preflight(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	origin := r.Header.Get("Origin")
+	method := r.Header.Get("Access-Control-Request-Method")
+	headers := parseHeaderList(r.Header.Values("Access-Control-Request-Headers"))
+	if !e.OriginAllowed(origin) || !e.MethodAllowed(method) || !e.HeadersAllowed(headers) {
+		w.WriteHeader(e.status)
+		return
+	}
+
+	e.setOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", strings.ToUpper(method))
+	if len(headers) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	}
+	if e.maxAge != "" {
+		h.Set("Access-Control-Max-Age", e.maxAge)
+	}
+	w.WriteHeader(e.status)
+}
+
+// actual 为简单请求与实际请求添加响应头
+func (e *Evaluator) actual(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	if !e.anyOrigin {
+		h.Add("Vary", "Origin")
+	}
+	origin := r.Header.Get("Origin")
+	if !e.OriginAllowed(origin) || !e.MethodAllowed(r.Method) {
+		return
+	}
+	e.setOrigin(h, origin)
+	if e.exposed != "" {
+		h.Set("Access-Control-Expose-Headers", e.exposed)
+	}
+}
+
+// setOrigin 写入允许的来源与凭证头；任意来源时返回 `*`
+func (e *Evaluator) setOrigin(h http.Header, origin string) {
+	if e.anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if e.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// parseHeaderList 解析逗号分隔的头部列表
+func parseHeaderList(values []string) []string {
+	var headers []string
+	for _, value := range values {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, http.CanonicalHeaderKey(header))
+			}
+		}
+	}
+	return headers
+}
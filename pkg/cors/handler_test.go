@@ -0,0 +1,253 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\cors\handler_test.go
+ * @Description: CORS 中间件测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig() *Cors {
+	return Default().
+		WithAllowedOrigins([]string{"https://app.example.com", "https://*.example.org", "http://localhost:*"}).
+		WithAllowedMethods([]string{"GET", "POST", "put"}).
+		WithAllowedHeaders([]string{"content-type", "Authorization"}).
+		WithExposedHeaders([]string{"X-Request-ID", "X-Total"}).
+		WithMaxAge("10m").
+		WithOptionsResponseCode(http.StatusNoContent).
+		WithAllowCredentials(true)
+}
+
+func TestNewHandler(t *testing.T) {
+	anyOrigin := newTestConfig().WithAllowedOrigins([]string{"*"}).WithAllowCredentials(false).WithAllowedHeaders([]string{"*"})
+
+	cases := []struct {
+		name      string
+		cfg       *Cors
+		method    string
+		headers   map[string]string
+		status    int
+		called    bool
+		want      map[string]string
+		vary      []string
+		noHeaders []string
+	}{
+		{
+			name:   "预检-精确来源",
+			cfg:    newTestConfig(),
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "content-type, authorization",
+			},
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Methods":     "PUT",
+				"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+			vary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "预检-子域名通配",
+			cfg:    newTestConfig(),
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://api.eu.example.org",
+				"Access-Control-Request-Method": "GET",
+			},
+			status: http.StatusNoContent,
+			want:   map[string]string{"Access-Control-Allow-Origin": "https://api.eu.example.org"},
+			vary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:   "预检-通配符不匹配裸域名",
+			cfg:    newTestConfig(),
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://example.org",
+				"Access-Control-Request-Method": "GET",
+			},
+			status:    http.StatusNoContent,
+			vary:      []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			noHeaders: []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods"},
+		},
+		{
+			name:   "预检-方法不允许",
+			cfg:    newTestConfig(),
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			status:    http.StatusNoContent,
+			noHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:   "预检-头部不允许",
+			cfg:    newTestConfig(),
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			status:    http.StatusNoContent,
+			noHeaders: []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Headers"},
+		},
+		{
+			name:   "预检-任意来源与任意头部",
+			cfg:    anyOrigin,
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://evil.test",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "x-custom",
+			},
+			status: http.StatusNoContent,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":  "*",
+				"Access-Control-Allow-Headers": "X-Custom",
+			},
+			noHeaders: []string{"Access-Control-Allow-Credentials"},
+		},
+		{
+			name:    "简单请求-允许的来源",
+			cfg:     newTestConfig(),
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "http://localhost:5173"},
+			status:  http.StatusOK,
+			called:  true,
+			want: map[string]string{
+				"Access-Control-Allow-Origin":      "http://localhost:5173",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID, X-Total",
+			},
+			vary:      []string{"Origin"},
+			noHeaders: []string{"Access-Control-Max-Age"},
+		},
+		{
+			name:      "简单请求-来源不允许",
+			cfg:       newTestConfig(),
+			method:    http.MethodPost,
+			headers:   map[string]string{"Origin": "https://app.example.com.evil.test"},
+			status:    http.StatusOK,
+			called:    true,
+			vary:      []string{"Origin"},
+			noHeaders: []string{"Access-Control-Allow-Origin", "Access-Control-Expose-Headers"},
+		},
+		{
+			name:      "非跨域请求",
+			cfg:       newTestConfig(),
+			method:    http.MethodGet,
+			status:    http.StatusOK,
+			called:    true,
+			vary:      []string{"Origin"},
+			noHeaders: []string{"Access-Control-Allow-Origin"},
+		},
+		{
+			name:      "OPTIONS 非预检请求转发",
+			cfg:       newTestConfig(),
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://app.example.com"},
+			status:    http.StatusOK,
+			called:    true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "https://app.example.com"},
+			noHeaders: []string{"Access-Control-Allow-Methods"},
+		},
+		{
+			name:      "简单请求-任意来源",
+			cfg:       anyOrigin,
+			method:    http.MethodGet,
+			headers:   map[string]string{"Origin": "https://any.test"},
+			status:    http.StatusOK,
+			called:    true,
+			want:      map[string]string{"Access-Control-Allow-Origin": "*"},
+			noHeaders: []string{"Vary"},
+		},
+		{
+			name:      "未启用",
+			cfg:       Default().WithEnabled(false),
+			method:    http.MethodOptions,
+			headers:   map[string]string{"Origin": "https://app.example.com", "Access-Control-Request-Method": "GET"},
+			status:    http.StatusOK,
+			called:    true,
+			noHeaders: []string{"Access-Control-Allow-Origin", "Vary"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			middleware, err := NewHandler(tc.cfg)
+			require.NoError(t, err)
+
+			called := false
+			handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			}))
+			req := httptest.NewRequest(tc.method, "/api/items", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+			assert.Equal(t, tc.called, called)
+			for k, v := range tc.want {
+				assert.Equal(t, v, rec.Header().Get(k), k)
+			}
+			if tc.vary != nil {
+				assert.Equal(t, tc.vary, rec.Header().Values("Vary"))
+			}
+			for _, k := range tc.noHeaders {
+				assert.Empty(t, rec.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+func TestNewHandler_WithoutCredentials(t *testing.T) {
+	h, err := NewHandler(Default().WithAllowCredentials(false))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Origin", "https://any.example.com")
+	h(http.NotFoundHandler()).ServeHTTP(rec, r)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestNewHandler_InvalidConfig(t *testing.T) {
+	_, err := NewHandler(Default())
+	assert.ErrorContains(t, err, "allow-credentials cannot be combined with a wildcard origin")
+
+	_, err = NewHandler(newTestConfig().WithAllowedOrigins(nil).WithAllowedAllOrigins(true))
+	assert.Error(t, err, "allowed-all-origins 同样视为任意来源")
+
+	_, err = NewHandler(newTestConfig().WithAllowedOrigins([]string{"https://*.*.example.com"}))
+	assert.ErrorContains(t, err, "only one wildcard")
+
+	_, err = NewHandler(newTestConfig().WithMaxAge("forever"))
+	assert.ErrorContains(t, err, "invalid max-age")
+
+	_, err = NewHandler(nil)
+	assert.Error(t, err)
+}
@@ -100,15 +100,26 @@ type DatabaseProvider interface {
 	SetDBName(dbName string)
 }
 
+// DSNProvider 支持连接串构建与解析的提供商，内置提供商均已实现
+// 独立于 DatabaseProvider 以免破坏外部实现，使用时通过类型断言获取
+type DSNProvider interface {
+	// BuildDSN 构建驱动连接串
+	BuildDSN() string
+
+	// ParseDSN 解析连接串并写入连接字段
+	ParseDSN(dsn string) error
+}
+
 // Database 数据库统一配置结构
 type Database struct {
-	Type        DBType       `mapstructure:"type" yaml:"type" json:"type"`                      // 数据库类型
-	Enabled     bool         `mapstructure:"enabled" yaml:"enabled" json:"enabled"`             // 是否启用
-	Default     string       `mapstructure:"default" yaml:"default" json:"default"`             // 默认使用的数据库
-	MySQL       *MySQL       `mapstructure:"mysql" yaml:"mysql" json:"mysql"`                   // MySQL配置
-	PostgreSQL  *PostgreSQL  `mapstructure:"postgresql" yaml:"postgresql" json:"postgresql"`    // PostgreSQL配置
-	SQLite      *SQLite      `mapstructure:"sqlite" yaml:"sqlite" json:"sqlite"`                // SQLite配置
-	CockroachDB *CockroachDB `mapstructure:"cockroachdb" yaml:"cockroachdb" json:"cockroachdb"` // CockroachDB配置
+	Type        DBType                 `mapstructure:"type" yaml:"type" json:"type"`                      // 数据库类型
+	Enabled     bool                   `mapstructure:"enabled" yaml:"enabled" json:"enabled"`             // 是否启用
+	Default     string                 `mapstructure:"default" yaml:"default" json:"default"`             // 默认使用的数据库
+	MySQL       *MySQL                 `mapstructure:"mysql" yaml:"mysql" json:"mysql"`                   // MySQL配置
+	PostgreSQL  *PostgreSQL            `mapstructure:"postgresql" yaml:"postgresql" json:"postgresql"`    // PostgreSQL配置
+	SQLite      *SQLite                `mapstructure:"sqlite" yaml:"sqlite" json:"sqlite"`                // SQLite配置
+	CockroachDB *CockroachDB           `mapstructure:"cockroachdb" yaml:"cockroachdb" json:"cockroachdb"` // CockroachDB配置
+	Sources     map[string]*DataSource `mapstructure:"sources" yaml:"sources" json:"sources"`             // 命名数据源（支持读写分离），Default 可引用其名称
 }
 
 // NewDatabase 创建新的数据库配置管理器
@@ -152,10 +163,9 @@ func (c *Database) GetProvider(dbType DBType) (DatabaseProvider, error) {
 	}
 }
 
-// GetDefaultProvider 获取默认的数据库提供商
+// GetDefaultProvider 获取默认的数据库提供商（Default 引用命名数据源时返回其主库）
 func (c *Database) GetDefaultProvider() (DatabaseProvider, error) {
-	defaultType := DBType(c.Default)
-	return c.GetProvider(defaultType)
+	return c.Resolve(c.Default, AccessWrite)
 }
 
 // SetDefaultProvider 设置默认数据库提供商
@@ -240,7 +250,17 @@ func (c *Database) Validate() error {
 		return fmt.Errorf("default database provider not specified")
 	}
 
-	defaultType := DBType(c.Default)
+	if err := c.ValidateSources(); err != nil {
+		return err
+	}
+	if _, ok := c.Sources[c.Default]; ok {
+		return nil
+	}
+
+	defaultType, err := ParseDBType(c.Default)
+	if err != nil {
+		return fmt.Errorf("default datasource %q not found in sources", c.Default)
+	}
 	if err := c.ValidateProvider(defaultType); err != nil {
 		return fmt.Errorf("default provider validation failed: %w", err)
 	}
@@ -291,9 +311,9 @@ func (c *Database) BeforeLoad() error {
 func (c *Database) AfterLoad() error {
 	c.EnsureDefaults()
 
-	// 验证默认提供商配置的有效性
-	if c.Default != "" {
-		if err := c.ValidateProvider(DBType(c.Default)); err != nil {
+	// 验证默认提供商配置的有效性（引用命名数据源时由 Validate 负责校验）
+	if _, ok := c.Sources[c.Default]; c.Default != "" && !ok {
+		if dbType, err := ParseDBType(c.Default); err != nil || c.ValidateProvider(dbType) != nil {
 			// 如果默认提供商配置无效，回退到MySQL
 			c.Default = string(DBTypeMySQL)
 			c.Type = DBTypeMySQL
@@ -0,0 +1,616 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\database\datasource.go
+ * @Description: 命名数据源与读写分离 - 主库/只读副本、权重与副本选择策略
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kamalyes/go-config/internal"
+)
+
+// AccessMode 数据源访问模式
+type AccessMode string
+
+const (
+	AccessWrite AccessMode = "write" // 写：始终路由到主库
+	AccessRead  AccessMode = "read"  // 读：按策略路由到只读副本，无副本时回落主库
+)
+
+// ReplicaPolicy 只读副本选择策略
+type ReplicaPolicy string
+
+const (
+	PolicyRandom       ReplicaPolicy = "random"        // 按权重随机
+	PolicyRoundRobin   ReplicaPolicy = "round-robin"   // 平滑加权轮询
+	PolicyLeastLatency ReplicaPolicy = "least-latency" // 按上报延迟（除以权重）取最小
+)
+
+// latencyDecay 延迟滑动平均中新样本的权重
+const latencyDecay = 0.3
+
+// DataSource 命名数据源：一个主库及若干只读副本
+// 主库连接信息取自与 Type 对应的配置块，DSN 非空时覆盖其中的连接字段
+type DataSource struct {
+	Type        DBType        `mapstructure:"type" yaml:"type" json:"type"`                      // 数据库类型，为空时根据 DSN 或唯一的配置块推断
+	DSN         string        `mapstructure:"dsn" yaml:"dsn" json:"dsn"`                         // 主库连接串
+	MySQL       *MySQL        `mapstructure:"mysql" yaml:"mysql" json:"mysql"`                   // MySQL配置
+	PostgreSQL  *PostgreSQL   `mapstructure:"postgresql" yaml:"postgresql" json:"postgresql"`    // PostgreSQL配置
+	SQLite      *SQLite       `mapstructure:"sqlite" yaml:"sqlite" json:"sqlite"`                // SQLite配置
+	CockroachDB *CockroachDB  `mapstructure:"cockroachdb" yaml:"cockroachdb" json:"cockroachdb"` // CockroachDB配置
+	Replicas    []*Replica    `mapstructure:"replicas" yaml:"replicas" json:"replicas"`          // 只读副本
+	Policy      ReplicaPolicy `mapstructure:"policy" yaml:"policy" json:"policy"`                // 副本选择策略，默认 random
+
+	balancer *replicaBalancer // 副本选择状态（轮询位置、延迟统计），不参与序列化
+}
+
+// Replica 只读副本，未填写的连接字段继承主库
+type Replica struct {
+	Name   string `mapstructure:"name" yaml:"name" json:"name"`       // 副本名称，默认 replica-<序号>
+	DSN    string `mapstructure:"dsn" yaml:"dsn" json:"dsn"`          // 副本连接串，类型需与主库一致
+	Host   string `mapstructure:"host" yaml:"host" json:"host"`       // 主机地址
+	Port   string `mapstructure:"port" yaml:"port" json:"port"`       // 端口
+	Weight int    `mapstructure:"weight" yaml:"weight" json:"weight"` // 权重，<=0 视为 1
+}
+
+// balancerMu 保护 DataSource.balancer 的延迟初始化
+var balancerMu sync.Mutex
+
+// replicaBalancer 副本选择状态
+type replicaBalancer struct {
+	mu        sync.Mutex
+	current   []int              // 平滑加权轮询的当前权重
+	latencies map[string]float64 // 副本连接串 -> 延迟滑动平均（纳秒）
+	keys      []string           // 各副本连接串缓存，解析失败的副本为空串；配置重载后重建
+	rand      *rand.Rand
+}
+
+// NewDataSource 创建指定类型的数据源，主库使用该类型的默认配置
+func NewDataSource(dbType DBType) *DataSource {
+	s := &DataSource{Type: dbType, Policy: PolicyRandom}
+	switch dbType {
+	case DBTypeMySQL:
+		s.MySQL = DefaultMySQL()
+	case DBTypePostgreSQL:
+		s.PostgreSQL = DefaultPostgreSQL()
+	case DBTypeSQLite:
+		s.SQLite = DefaultSQLite()
+	case DBTypeCockroachDB:
+		s.CockroachDB = DefaultCockroachDB()
+	}
+	return s
+}
+
+// WithDSN 设置主库连接串
+func (s *DataSource) WithDSN(dsn string) *DataSource {
+	s.DSN = dsn
+	s.Refresh()
+	return s
+}
+
+// WithPolicy 设置副本选择策略
+func (s *DataSource) WithPolicy(policy ReplicaPolicy) *DataSource {
+	s.Policy = policy
+	return s
+}
+
+// AddReplica 添加只读副本
+func (s *DataSource) AddReplica(replica *Replica) *DataSource {
+	s.Replicas = append(s.Replicas, replica)
+	s.Refresh()
+	return s
+}
+
+// Refresh 丢弃已解析的副本缓存，原地修改主库或副本连接信息后调用；热更新时自动调用
+func (s *DataSource) Refresh() {
+	b := s.getBalancer()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keys = nil
+}
+
+// GetDBType 获取数据源类型
+func (s *DataSource) GetDBType() (DBType, error) {
+	if s.Type != "" {
+		return ParseDBType(string(s.Type))
+	}
+	if strings.TrimSpace(s.DSN) != "" {
+		return DetectDSNType(s.DSN)
+	}
+
+	var found []DBType
+	for dbType, configured := range map[DBType]bool{
+		DBTypeMySQL:       s.MySQL != nil,
+		DBTypePostgreSQL:  s.PostgreSQL != nil,
+		DBTypeSQLite:      s.SQLite != nil,
+		DBTypeCockroachDB: s.CockroachDB != nil,
+	} {
+		if configured {
+			found = append(found, dbType)
+		}
+	}
+	if len(found) != 1 {
+		return "", fmt.Errorf("datasource type not specified")
+	}
+	return found[0], nil
+}
+
+// Primary 返回主库的提供商（独立副本，修改不影响配置）
+func (s *DataSource) Primary() (DatabaseProvider, error) {
+	dbType, err := s.GetDBType()
+	if err != nil {
+		return nil, err
+	}
+
+	base := NewDatabase()
+	if s.MySQL != nil {
+		base.MySQL = s.MySQL
+	}
+	if s.PostgreSQL != nil {
+		base.PostgreSQL = s.PostgreSQL
+	}
+	if s.SQLite != nil {
+		base.SQLite = s.SQLite
+	}
+	if s.CockroachDB != nil {
+		base.CockroachDB = s.CockroachDB
+	}
+	provider, err := base.GetProvider(dbType)
+	if err != nil {
+		return nil, err
+	}
+	provider = provider.Clone().(DatabaseProvider)
+
+	if dsn := strings.TrimSpace(s.DSN); dsn != "" {
+		if err := parseProviderDSN(provider, dsn); err != nil {
+			return nil, fmt.Errorf("invalid dsn: %w", err)
+		}
+	}
+	return provider, nil
+}
+
+// Replica 返回第 index 个只读副本的提供商，未填写的字段继承主库
+func (s *DataSource) Replica(index int) (DatabaseProvider, error) {
+	if index < 0 || index >= len(s.Replicas) {
+		return nil, fmt.Errorf("replica index %d out of range", index)
+	}
+	replica := s.Replicas[index]
+	if replica == nil {
+		return nil, fmt.Errorf("replica %d is nil", index)
+	}
+
+	provider, err := s.Primary()
+	if err != nil {
+		return nil, err
+	}
+	if dsn := strings.TrimSpace(replica.DSN); dsn != "" {
+		if dbType, err := DetectDSNType(dsn); err == nil && dbType != provider.GetDBType() {
+			return nil, fmt.Errorf("replica %s: dsn type %s does not match primary type %s", s.replicaName(index), dbType, provider.GetDBType())
+		}
+		if err := parseProviderDSN(provider, dsn); err != nil {
+			return nil, fmt.Errorf("replica %s: invalid dsn: %w", s.replicaName(index), err)
+		}
+	}
+	if replica.Host != "" {
+		provider.SetHost(replica.Host)
+	}
+	if replica.Port != "" {
+		provider.SetPort(replica.Port)
+	}
+	return provider, nil
+}
+
+// Resolve 按访问模式返回提供商：写返回主库，读按策略选择副本
+func (s *DataSource) Resolve(mode AccessMode) (DatabaseProvider, error) {
+	switch mode {
+	case AccessWrite, "":
+		return s.Primary()
+	case AccessRead:
+		if len(s.Replicas) == 0 {
+			return s.Primary()
+		}
+		return s.Replica(s.pickReplica())
+	default:
+		return nil, fmt.Errorf("unsupported access mode: %s", mode)
+	}
+}
+
+// ReportLatency 上报一次访问延迟，供 least-latency 策略使用
+// provider 为 Resolve 返回的提供商，按其连接串（未实现 DSNProvider 时为 主机:端口/库名）归并统计
+func (s *DataSource) ReportLatency(provider DatabaseProvider, latency time.Duration) {
+	if provider == nil || latency < 0 {
+		return
+	}
+	b := s.getBalancer()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := providerKey(provider)
+	if prev, ok := b.latencies[key]; ok {
+		b.latencies[key] = prev + latencyDecay*(float64(latency)-prev)
+		return
+	}
+	b.latencies[key] = float64(latency)
+}
+
+// Validate 验证数据源配置
+func (s *DataSource) Validate() error {
+	primary, err := s.Primary()
+	if err != nil {
+		return err
+	}
+	if err := primary.Validate(); err != nil {
+		return fmt.Errorf("primary: %w", err)
+	}
+
+	switch s.Policy {
+	case "", PolicyRandom, PolicyRoundRobin, PolicyLeastLatency:
+	default:
+		return fmt.Errorf("unsupported replica policy: %s", s.Policy)
+	}
+
+	names := make(map[string]bool, len(s.Replicas))
+	for i, replica := range s.Replicas {
+		if replica == nil {
+			return fmt.Errorf("replica %d is nil", i)
+		}
+		name := s.replicaName(i)
+		if names[name] {
+			return fmt.Errorf("duplicate replica name: %s", name)
+		}
+		names[name] = true
+		if replica.Weight < 0 {
+			return fmt.Errorf("replica %s: weight must not be negative", name)
+		}
+		provider, err := s.Replica(i)
+		if err != nil {
+			return err
+		}
+		if err := provider.Validate(); err != nil {
+			return fmt.Errorf("replica %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// replicaName 返回副本名称，未设置时为 replica-<序号>
+func (s *DataSource) replicaName(index int) string {
+	if replica := s.Replicas[index]; replica != nil && replica.Name != "" {
+		return replica.Name
+	}
+	return fmt.Sprintf("replica-%d", index)
+}
+
+// replicaWeight 返回副本权重，<=0 视为 1
+func (s *DataSource) replicaWeight(index int) int {
+	if replica := s.Replicas[index]; replica != nil && replica.Weight > 0 {
+		return replica.Weight
+	}
+	return 1
+}
+
+// getBalancer 返回副本选择状态，首次使用时初始化
+func (s *DataSource) getBalancer() *replicaBalancer {
+	balancerMu.Lock()
+	defer balancerMu.Unlock()
+	if s.balancer == nil {
+		s.balancer = &replicaBalancer{
+			latencies: make(map[string]float64),
+			rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+	}
+	return s.balancer
+}
+
+// pickReplica 按策略选择副本序号
+func (s *DataSource) pickReplica() int {
+	if len(s.Replicas) == 1 {
+		return 0
+	}
+	b := s.getBalancer()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch s.Policy {
+	case PolicyRoundRobin:
+		return s.pickRoundRobin(b)
+	case PolicyLeastLatency:
+		return s.pickLeastLatency(b)
+	default:
+		return s.pickRandom(b)
+	}
+}
+
+// pickRandom 按权重随机选择
+func (s *DataSource) pickRandom(b *replicaBalancer) int {
+	total := 0
+	for i := range s.Replicas {
+		total += s.replicaWeight(i)
+	}
+	n := b.rand.Intn(total)
+	for i := range s.Replicas {
+		n -= s.replicaWeight(i)
+		if n < 0 {
+			return i
+		}
+	}
+	return len(s.Replicas) - 1
+}
+
+// pickRoundRobin 平滑加权轮询（与 nginx 一致），权重 5:1:1 时序列为 a a b a c a a
+func (s *DataSource) pickRoundRobin(b *replicaBalancer) int {
+	if len(b.current) != len(s.Replicas) {
+		b.current = make([]int, len(s.Replicas))
+	}
+	total, best := 0, 0
+	for i := range s.Replicas {
+		weight := s.replicaWeight(i)
+		b.current[i] += weight
+		total += weight
+		if b.current[i] > b.current[best] {
+			best = i
+		}
+	}
+	b.current[best] -= total
+	return best
+}
+
+// pickLeastLatency 选择 延迟/权重 最小的副本，尚无统计的副本优先
+func (s *DataSource) pickLeastLatency(b *replicaBalancer) int {
+	best, bestScore := 0, -1.0
+	for i, key := range s.replicaKeys(b) {
+		if key == "" {
+			continue
+		}
+		latency, ok := b.latencies[key]
+		if !ok {
+			return i
+		}
+		score := latency / float64(s.replicaWeight(i))
+		if bestScore < 0 || score < bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// replicaKeys 返回各副本连接串，首次使用或副本数量变化时解析并缓存，调用方需持有 b.mu
+func (s *DataSource) replicaKeys(b *replicaBalancer) []string {
+	if b.keys != nil && len(b.keys) == len(s.Replicas) {
+		return b.keys
+	}
+	b.keys = make([]string, len(s.Replicas))
+	for i := range s.Replicas {
+		if provider, err := s.Replica(i); err == nil {
+			b.keys[i] = providerKey(provider)
+		}
+	}
+	return b.keys
+}
+
+// ========== Database 数据源方法 ==========
+
+// SourceChangeAction 数据源变更类型
+type SourceChangeAction string
+
+const (
+	SourceAdded          SourceChangeAction = "added"           // 新增
+	SourceRemoved        SourceChangeAction = "removed"         // 删除
+	SourceUpdated        SourceChangeAction = "updated"         // 连接或副本配置变化
+	SourceDefaultChanged SourceChangeAction = "default-changed" // 成为新的默认数据源
+)
+
+// SourceChange 一个数据源的变更
+type SourceChange struct {
+	Name   string             `json:"name"`   // 数据源名称，内置配置块为 mysql/postgres/sqlite/cockroachdb
+	Action SourceChangeAction `json:"action"` // 变更类型
+}
+
+// Resolve 按名称与访问模式返回提供商
+// name 为空时使用 Default；优先匹配 Sources 中的命名数据源，否则按数据库类型匹配内置配置块（读写均返回该配置块）
+func (c *Database) Resolve(name string, mode AccessMode) (DatabaseProvider, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if source, ok := c.Sources[name]; ok {
+		if source == nil {
+			return nil, fmt.Errorf("datasource %q is nil", name)
+		}
+		provider, err := source.Resolve(mode)
+		if err != nil {
+			return nil, fmt.Errorf("datasource %q: %w", name, err)
+		}
+		return provider, nil
+	}
+
+	switch mode {
+	case AccessWrite, AccessRead, "":
+	default:
+		return nil, fmt.Errorf("unsupported access mode: %s", mode)
+	}
+	dbType, err := ParseDBType(name)
+	if err != nil {
+		return nil, fmt.Errorf("datasource %q not found", name)
+	}
+	return c.GetProvider(dbType)
+}
+
+// GetSource 获取命名数据源
+func (c *Database) GetSource(name string) (*DataSource, bool) {
+	source, ok := c.Sources[name]
+	return source, ok && source != nil
+}
+
+// WithSource 添加或替换命名数据源
+func (c *Database) WithSource(name string, source *DataSource) *Database {
+	if c.Sources == nil {
+		c.Sources = make(map[string]*DataSource)
+	}
+	c.Sources[name] = source
+	return c
+}
+
+// SourceNames 返回排序后的命名数据源名称
+func (c *Database) SourceNames() []string {
+	names := make([]string, 0, len(c.Sources))
+	for name := range c.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateSources 验证所有命名数据源
+func (c *Database) ValidateSources() error {
+	for _, name := range c.SourceNames() {
+		source := c.Sources[name]
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("datasource name must not be empty")
+		}
+		if source == nil {
+			return fmt.Errorf("datasource %q is nil", name)
+		}
+		if err := source.Validate(); err != nil {
+			return fmt.Errorf("datasource %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// MetadataSourceChanges 配置变更事件中数据源变更列表（[]SourceChange）的元数据键
+const MetadataSourceChanges = "database_changes"
+
+func init() {
+	internal.RegisterChangeDetector(detectSourceChanges)
+	internal.RegisterApplyHook(refreshSources)
+}
+
+// detectSourceChanges 热更新变更检测器：比较新旧配置中的数据库配置，返回变化的数据源
+func detectSourceChanges(oldConfig, newConfig interface{}) (string, interface{}, bool) {
+	oldDB, newDB := findDatabaseConfig(oldConfig), findDatabaseConfig(newConfig)
+	if oldDB == nil && newDB == nil {
+		return "", nil, false
+	}
+	changes := DiffSources(oldDB, newDB)
+	return MetadataSourceChanges, changes, len(changes) > 0
+}
+
+// refreshSources 热更新应用钩子：新配置生效时丢弃各数据源的副本缓存
+func refreshSources(config interface{}) {
+	db := findDatabaseConfig(config)
+	if db == nil {
+		return
+	}
+	for _, source := range db.Sources {
+		if source != nil {
+			source.Refresh()
+		}
+	}
+}
+
+// findDatabaseConfig 查找配置本身或其顶层字段中的 *Database
+func findDatabaseConfig(config interface{}) *Database {
+	if db, ok := config.(*Database); ok {
+		return db
+	}
+	v := reflect.ValueOf(config)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).IsExported() {
+			continue
+		}
+		if db, ok := v.Field(i).Interface().(*Database); ok && db != nil {
+			return db
+		}
+	}
+	return nil
+}
+
+// DiffSources 比较两份配置，返回按名称排序的数据源变更
+// 内置配置块以数据库类型为名参与比较，Default 变化时报告新默认数据源
+func DiffSources(oldConfig, newConfig *Database) []SourceChange {
+	oldSources, newSources := oldConfig.sourceSnapshots(), newConfig.sourceSnapshots()
+
+	names := make([]string, 0, len(oldSources)+len(newSources))
+	for name := range oldSources {
+		names = append(names, name)
+	}
+	for name := range newSources {
+		if _, ok := oldSources[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []SourceChange
+	for _, name := range names {
+		before, hadBefore := oldSources[name]
+		after, hasAfter := newSources[name]
+		switch {
+		case !hadBefore:
+			changes = append(changes, SourceChange{Name: name, Action: SourceAdded})
+		case !hasAfter:
+			changes = append(changes, SourceChange{Name: name, Action: SourceRemoved})
+		case before != after:
+			changes = append(changes, SourceChange{Name: name, Action: SourceUpdated})
+		}
+	}
+
+	var oldDefault, newDefault string
+	if oldConfig != nil {
+		oldDefault = oldConfig.Default
+	}
+	if newConfig != nil {
+		newDefault = newConfig.Default
+	}
+	if oldDefault != newDefault && newDefault != "" {
+		changes = append(changes, SourceChange{Name: newDefault, Action: SourceDefaultChanged})
+	}
+	return changes
+}
+
+// sourceSnapshots 以 JSON 序列化结果作为各数据源的比较快照
+func (c *Database) sourceSnapshots() map[string]string {
+	snapshots := make(map[string]string)
+	if c == nil {
+		return snapshots
+	}
+	add := func(name string, value interface{}) {
+		if data, err := json.Marshal(value); err == nil {
+			snapshots[name] = string(data)
+		}
+	}
+	if c.MySQL != nil {
+		add(string(DBTypeMySQL), c.MySQL)
+	}
+	if c.PostgreSQL != nil {
+		add(string(DBTypePostgreSQL), c.PostgreSQL)
+	}
+	if c.SQLite != nil {
+		add(string(DBTypeSQLite), c.SQLite)
+	}
+	if c.CockroachDB != nil {
+		add(string(DBTypeCockroachDB), c.CockroachDB)
+	}
+	for name, source := range c.Sources {
+		add(name, source)
+	}
+	return snapshots
+}
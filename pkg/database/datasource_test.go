@@ -0,0 +1,278 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\database\datasource_test.go
+ * @Description: 命名数据源与读写分离测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOrdersSource 创建带两个副本的 MySQL 数据源，权重 2:1
+func newOrdersSource(policy ReplicaPolicy) *DataSource {
+	return NewDataSource(DBTypeMySQL).
+		WithDSN("app:secret@tcp(primary:3306)/orders?charset=utf8mb4").
+		WithPolicy(policy).
+		AddReplica(&Replica{Name: "r1", Host: "replica1", Weight: 2}).
+		AddReplica(&Replica{Name: "r2", DSN: "reader:ro@tcp(replica2:3307)/orders?charset=utf8mb4", Weight: 1})
+}
+
+func TestDataSource_PrimaryAndReplica(t *testing.T) {
+	source := newOrdersSource(PolicyRoundRobin)
+
+	primary, err := source.Resolve(AccessWrite)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", primary.GetHost())
+	assert.Equal(t, "orders", primary.GetDBName())
+	assert.Equal(t, "secret", primary.GetPassword())
+
+	r1, err := source.Replica(0)
+	require.NoError(t, err)
+	assert.Equal(t, "replica1", r1.GetHost())
+	assert.Equal(t, "3306", r1.GetPort())
+	assert.Equal(t, "app", r1.GetUsername(), "未填写的字段继承主库")
+
+	r2, err := source.Replica(1)
+	require.NoError(t, err)
+	assert.Equal(t, "replica2", r2.GetHost())
+	assert.Equal(t, "3307", r2.GetPort())
+	assert.Equal(t, "reader", r2.GetUsername())
+
+	// 返回的是独立副本
+	primary.SetHost("changed")
+	again, err := source.Primary()
+	require.NoError(t, err)
+	assert.Equal(t, "primary", again.GetHost())
+
+	_, err = source.Replica(2)
+	assert.Error(t, err)
+}
+
+func TestDataSource_ReadWithoutReplicasFallsBackToPrimary(t *testing.T) {
+	source := NewDataSource(DBTypePostgreSQL)
+	provider, err := source.Resolve(AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, DBTypePostgreSQL, provider.GetDBType())
+	assert.Equal(t, "localhost", provider.GetHost())
+
+	_, err = source.Resolve(AccessMode("admin"))
+	assert.Error(t, err)
+}
+
+func TestDataSource_RoundRobinWeights(t *testing.T) {
+	source := newOrdersSource(PolicyRoundRobin)
+
+	var hosts []string
+	for i := 0; i < 6; i++ {
+		provider, err := source.Resolve(AccessRead)
+		require.NoError(t, err)
+		hosts = append(hosts, provider.GetHost())
+	}
+	assert.Equal(t, []string{"replica1", "replica2", "replica1", "replica1", "replica2", "replica1"}, hosts)
+}
+
+func TestDataSource_RandomWeights(t *testing.T) {
+	source := newOrdersSource(PolicyRandom)
+	source.Replicas[1].Weight = 0 // 视为 1
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		provider, err := source.Resolve(AccessRead)
+		require.NoError(t, err)
+		counts[provider.GetHost()]++
+	}
+	assert.Len(t, counts, 2)
+	assert.Greater(t, counts["replica1"], counts["replica2"])
+}
+
+func TestDataSource_LeastLatency(t *testing.T) {
+	source := newOrdersSource(PolicyLeastLatency)
+
+	// 尚无统计的副本优先被选中
+	first, err := source.Resolve(AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, "replica1", first.GetHost())
+	source.ReportLatency(first, 50*time.Millisecond)
+
+	second, err := source.Resolve(AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, "replica2", second.GetHost())
+	source.ReportLatency(second, 10*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		provider, err := source.Resolve(AccessRead)
+		require.NoError(t, err)
+		assert.Equal(t, "replica2", provider.GetHost())
+	}
+
+	// replica2 变慢后切回 replica1（50ms/2 < 40ms/1）
+	for i := 0; i < 10; i++ {
+		source.ReportLatency(second, 80*time.Millisecond)
+	}
+	provider, err := source.Resolve(AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, "replica1", provider.GetHost())
+}
+
+func TestDataSource_ReplicaKeysCached(t *testing.T) {
+	source := newOrdersSource(PolicyLeastLatency)
+	b := source.getBalancer()
+	keys := source.replicaKeys(b)
+	require.Len(t, keys, 2)
+	assert.Contains(t, keys[0], "replica1")
+
+	// 原地修改后缓存不变，Refresh 后重新解析
+	source.Replicas[0].Host = "replica3"
+	assert.Equal(t, keys, source.replicaKeys(b))
+	source.Refresh()
+	assert.Contains(t, source.replicaKeys(b)[0], "replica3")
+
+	// 新增副本时自动重建
+	source.AddReplica(&Replica{Host: "replica4"})
+	assert.Len(t, source.replicaKeys(b), 3)
+
+	// 变更检测器不修改状态，由热更新应用钩子丢弃缓存
+	source.Replicas[0].Host = "replica5"
+	db := NewDatabase()
+	db.Sources = map[string]*DataSource{"orders": source}
+	detectSourceChanges(db, db)
+	assert.Contains(t, source.replicaKeys(b)[0], "replica3")
+	internal.RunApplyHooks(&struct{ Database *Database }{Database: db})
+	assert.Contains(t, source.replicaKeys(b)[0], "replica5")
+}
+
+func TestDataSource_GetDBType(t *testing.T) {
+	dbType, err := (&DataSource{DSN: "postgres://u@h/db"}).GetDBType()
+	require.NoError(t, err)
+	assert.Equal(t, DBTypePostgreSQL, dbType)
+
+	dbType, err = (&DataSource{SQLite: DefaultSQLite()}).GetDBType()
+	require.NoError(t, err)
+	assert.Equal(t, DBTypeSQLite, dbType)
+
+	_, err = (&DataSource{}).GetDBType()
+	assert.Error(t, err)
+	_, err = (&DataSource{MySQL: DefaultMySQL(), SQLite: DefaultSQLite()}).GetDBType()
+	assert.Error(t, err)
+}
+
+func TestDataSource_Validate(t *testing.T) {
+	assert.NoError(t, newOrdersSource(PolicyLeastLatency).Validate())
+
+	source := newOrdersSource("fastest")
+	assert.ErrorContains(t, source.Validate(), "unsupported replica policy")
+
+	source = newOrdersSource(PolicyRandom)
+	source.Replicas[1].Name = "r1"
+	assert.ErrorContains(t, source.Validate(), "duplicate replica name")
+
+	source = newOrdersSource(PolicyRandom)
+	source.Replicas[0].Weight = -1
+	assert.ErrorContains(t, source.Validate(), "weight")
+
+	source = newOrdersSource(PolicyRandom)
+	source.Replicas[1].DSN = "postgres://u@h/db"
+	assert.ErrorContains(t, source.Validate(), "does not match")
+}
+
+func TestDatabase_Resolve(t *testing.T) {
+	db := NewDatabase().
+		WithSource("orders", newOrdersSource(PolicyRoundRobin)).
+		WithSource("audit", NewDataSource(DBTypePostgreSQL).WithDSN("postgres://audit:pw@audit-db/audit")).
+		WithDefault("orders")
+
+	provider, err := db.Resolve("", AccessWrite)
+	require.NoError(t, err)
+	assert.Equal(t, "primary", provider.GetHost())
+
+	provider, err = db.GetDefaultProvider()
+	require.NoError(t, err)
+	assert.Equal(t, "primary", provider.GetHost())
+
+	provider, err = db.Resolve("orders", AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, "replica1", provider.GetHost())
+
+	provider, err = db.Resolve("audit", AccessRead)
+	require.NoError(t, err)
+	assert.Equal(t, "audit-db", provider.GetHost())
+
+	// 内置配置块按类型名解析
+	provider, err = db.Resolve("postgresql", AccessRead)
+	require.NoError(t, err)
+	assert.Same(t, db.PostgreSQL, provider)
+
+	_, err = db.Resolve("missing", AccessWrite)
+	assert.ErrorContains(t, err, `datasource "missing" not found`)
+
+	source, ok := db.GetSource("audit")
+	require.True(t, ok)
+	assert.Equal(t, DBTypePostgreSQL, source.Type)
+	assert.Equal(t, []string{"audit", "orders"}, db.SourceNames())
+}
+
+func TestDatabase_ValidateSources(t *testing.T) {
+	db := NewDatabase().EnableDatabase().
+		WithSource("orders", newOrdersSource(PolicyRandom)).
+		WithDefault("orders")
+	assert.NoError(t, db.Validate())
+
+	db.WithDefault("reporting")
+	assert.ErrorContains(t, db.Validate(), `default datasource "reporting" not found`)
+
+	db.WithDefault("orders")
+	db.Sources["orders"].Policy = "unknown"
+	assert.ErrorContains(t, db.Validate(), `datasource "orders"`)
+
+	// 内置配置块作为默认值仍然有效
+	db = NewDatabase().EnableDatabase().EnablePostgreSQL()
+	assert.NoError(t, db.Validate())
+}
+
+func TestDatabase_AfterLoadKeepsSourceDefault(t *testing.T) {
+	db := NewDatabase().WithSource("orders", newOrdersSource(PolicyRandom)).WithDefault("orders")
+	require.NoError(t, db.AfterLoad())
+	assert.Equal(t, "orders", db.Default)
+
+	db.WithDefault("missing")
+	require.NoError(t, db.AfterLoad())
+	assert.Equal(t, string(DBTypeMySQL), db.Default)
+}
+
+func TestDiffSources(t *testing.T) {
+	oldConfig := NewDatabase().
+		WithSource("orders", newOrdersSource(PolicyRandom)).
+		WithSource("audit", NewDataSource(DBTypePostgreSQL)).
+		WithDefault("orders")
+
+	newConfig := oldConfig.Clone().(*Database)
+	assert.Empty(t, DiffSources(oldConfig, newConfig))
+
+	newConfig.Sources["orders"].Replicas[0].Weight = 5
+	delete(newConfig.Sources, "audit")
+	newConfig.WithSource("metrics", NewDataSource(DBTypeSQLite))
+	newConfig.MySQL.Host = "mysql-new"
+	newConfig.WithDefault("metrics")
+
+	assert.Equal(t, []SourceChange{
+		{Name: "audit", Action: SourceRemoved},
+		{Name: "metrics", Action: SourceAdded},
+		{Name: string(DBTypeMySQL), Action: SourceUpdated},
+		{Name: "orders", Action: SourceUpdated},
+		{Name: "metrics", Action: SourceDefaultChanged},
+	}, DiffSources(oldConfig, newConfig))
+
+	assert.Len(t, DiffSources(nil, oldConfig), 7)
+}
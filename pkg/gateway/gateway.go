@@ -285,7 +285,8 @@ func (c *Gateway) Validate() error {
 		}
 	}
 
-	return nil
+	// 跨模块引用校验，一次性报告所有问题
+	return c.ValidateReferences()
 }
 
 // WithModuleName 设置模块名称
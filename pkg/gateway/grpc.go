@@ -40,22 +40,27 @@ func (c GRPCCompressType) String() string {
 
 // GRPCServer GRPC服务端配置
 type GRPCServer struct {
-	Enable             bool             `mapstructure:"enable" yaml:"enable" json:"enable"`                                         // 是否启用GRPC服务
-	Host               string           `mapstructure:"host" yaml:"host" json:"host"`                                               // 主机地址
-	Port               int              `mapstructure:"port" yaml:"port" json:"port"`                                               // 端口
-	Network            string           `mapstructure:"network" yaml:"network" json:"network"`                                      // 网络类型 (tcp, tcp4, tcp6, unix)
-	MaxRecvMsgSize     int              `mapstructure:"max-recv-msg-size" yaml:"max-recv-msg-size" json:"maxRecvMsgSize"`           // 最大接收消息大小(字节)
-	MaxSendMsgSize     int              `mapstructure:"max-send-msg-size" yaml:"max-send-msg-size" json:"maxSendMsgSize"`           // 最大发送消息大小(字节)
-	KeepaliveTime      int              `mapstructure:"keepalive-time" yaml:"keepalive-time" json:"keepaliveTime"`                  // Keepalive时间(秒)
-	KeepaliveTimeout   int              `mapstructure:"keepalive-timeout" yaml:"keepalive-timeout" json:"keepaliveTimeout"`         // Keepalive超时(秒)
-	ConnectionTimeout  int              `mapstructure:"connection-timeout" yaml:"connection-timeout" json:"connectionTimeout"`      // 连接超时(秒)
-	EnableReflection   bool             `mapstructure:"enable-reflection" yaml:"enable-reflection" json:"enableReflection"`         // 是否启用反射
-	EnableProtobufResp bool             `mapstructure:"enable-protobuf-resp" yaml:"enable-protobuf-resp" json:"enableProtobufResp"` // 是否启用protobuf格式响应（grpc-gateway场景下默认返回JSON，开启后返回原始protobuf）
-	EnableCompression  bool             `mapstructure:"enable-compression" yaml:"enable-compression" json:"enableCompression"`      // 是否启用压缩
-	CompressionType    GRPCCompressType `mapstructure:"compression-type" yaml:"compression-type" json:"compressionType"`            // 压缩算法类型 (gzip, snappy, zstd)
-	CompressionLevel   int              `mapstructure:"compression-level" yaml:"compression-level" json:"compressionLevel"`         // 压缩级别 (gzip: 1-9, zstd: 1-22, snappy: 忽略此值)
-	MinCompressSize    int              `mapstructure:"min-compress-size" yaml:"min-compress-size" json:"minCompressSize"`          // 最小压缩大小(字节)，小于此大小不压缩
-	Endpoint           string           `mapstructure:"-" yaml:"-" json:""`                                                         // 完整的服务端点地址（自动计算）
+	Enable                       bool             `mapstructure:"enable" yaml:"enable" json:"enable"`                                                                         // 是否启用GRPC服务
+	Host                         string           `mapstructure:"host" yaml:"host" json:"host"`                                                                               // 主机地址
+	Port                         int              `mapstructure:"port" yaml:"port" json:"port"`                                                                               // 端口
+	Network                      string           `mapstructure:"network" yaml:"network" json:"network"`                                                                      // 网络类型 (tcp, tcp4, tcp6, unix)
+	MaxRecvMsgSize               int              `mapstructure:"max-recv-msg-size" yaml:"max-recv-msg-size" json:"maxRecvMsgSize"`                                           // 最大接收消息大小(字节)
+	MaxSendMsgSize               int              `mapstructure:"max-send-msg-size" yaml:"max-send-msg-size" json:"maxSendMsgSize"`                                           // 最大发送消息大小(字节)
+	KeepaliveTime                int              `mapstructure:"keepalive-time" yaml:"keepalive-time" json:"keepaliveTime"`                                                  // Keepalive时间(秒)
+	KeepaliveTimeout             int              `mapstructure:"keepalive-timeout" yaml:"keepalive-timeout" json:"keepaliveTimeout"`                                         // Keepalive超时(秒)
+	ConnectionTimeout            int              `mapstructure:"connection-timeout" yaml:"connection-timeout" json:"connectionTimeout"`                                      // 连接超时(秒)
+	EnableReflection             bool             `mapstructure:"enable-reflection" yaml:"enable-reflection" json:"enableReflection"`                                         // 是否启用反射
+	EnableProtobufResp           bool             `mapstructure:"enable-protobuf-resp" yaml:"enable-protobuf-resp" json:"enableProtobufResp"`                                 // 是否启用protobuf格式响应（grpc-gateway场景下默认返回JSON，开启后返回原始protobuf）
+	EnableCompression            bool             `mapstructure:"enable-compression" yaml:"enable-compression" json:"enableCompression"`                                      // 是否启用压缩
+	CompressionType              GRPCCompressType `mapstructure:"compression-type" yaml:"compression-type" json:"compressionType"`                                            // 压缩算法类型 (gzip, snappy, zstd)
+	CompressionLevel             int              `mapstructure:"compression-level" yaml:"compression-level" json:"compressionLevel"`                                         // 压缩级别 (gzip: 1-9, zstd: 1-22, snappy: 忽略此值)
+	MinCompressSize              int              `mapstructure:"min-compress-size" yaml:"min-compress-size" json:"minCompressSize"`                                          // 最小压缩大小(字节)，小于此大小不压缩
+	MaxConcurrentStreams         uint32           `mapstructure:"max-concurrent-streams" yaml:"max-concurrent-streams" json:"maxConcurrentStreams"`                           // 每个连接的最大并发流数，0 表示不限制
+	KeepaliveMinTime             int              `mapstructure:"keepalive-min-time" yaml:"keepalive-min-time" json:"keepaliveMinTime"`                                       // 允许客户端 ping 的最小间隔(秒)，0 使用 gRPC 默认值(5分钟)
+	KeepalivePermitWithoutStream bool             `mapstructure:"keepalive-permit-without-stream" yaml:"keepalive-permit-without-stream" json:"keepalivePermitWithoutStream"` // 是否允许客户端在无活动流时 ping
+	EnableHealthCheck            bool             `mapstructure:"enable-health-check" yaml:"enable-health-check" json:"enableHealthCheck"`                                    // 是否注册 grpc.health.v1 健康检查服务
+	TLS                          *TLS             `mapstructure:"tls" yaml:"tls" json:"tls"`                                                                                  // TLS配置
+	Endpoint                     string           `mapstructure:"-" yaml:"-" json:"-"`                                                                                        // 完整的服务端点地址（自动计算）
 }
 
 // GRPCClient GRPC客户端配置
@@ -319,6 +324,48 @@ func (g *GRPCServer) WithMinCompressSize(size int) *GRPCServer {
 	return g
 }
 
+// WithMaxConcurrentStreams 设置每个连接的最大并发流数
+func (g *GRPCServer) WithMaxConcurrentStreams(n uint32) *GRPCServer {
+	g.MaxConcurrentStreams = n
+	return g
+}
+
+// WithKeepaliveEnforcement 设置客户端 ping 的限制策略
+func (g *GRPCServer) WithKeepaliveEnforcement(minTime int, permitWithoutStream bool) *GRPCServer {
+	g.KeepaliveMinTime = minTime
+	g.KeepalivePermitWithoutStream = permitWithoutStream
+	return g
+}
+
+// EnableHealthService 启用健康检查服务
+func (g *GRPCServer) EnableHealthService() *GRPCServer {
+	g.EnableHealthCheck = true
+	return g
+}
+
+// DisableHealthService 禁用健康检查服务
+func (g *GRPCServer) DisableHealthService() *GRPCServer {
+	g.EnableHealthCheck = false
+	return g
+}
+
+// WithServerTLS 设置服务端TLS配置，caFile 非空时校验客户端证书
+func (g *GRPCServer) WithServerTLS(certFile, keyFile, caFile string) *GRPCServer {
+	if g.TLS == nil {
+		g.TLS = &TLS{}
+	}
+	g.TLS.Enabled = true
+	g.TLS.CertFile = certFile
+	g.TLS.KeyFile = keyFile
+	g.TLS.CAFile = caFile
+	return g
+}
+
+// TLSEnabled 是否启用TLS
+func (g *GRPCServer) TLSEnabled() bool {
+	return g.TLS != nil && g.TLS.Enabled
+}
+
 // AddClient 添加GRPC客户端配置
 func (g *GRPC) AddClient(name string, client *GRPCClient) *GRPC {
 	if g.Clients == nil {
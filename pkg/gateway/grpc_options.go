@@ -0,0 +1,218 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\grpc_options.go
+ * @Description: 由 GRPC 配置构建服务端与客户端选项
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// ServerOptions 将服务端配置转换为 grpc.ServerOption
+//
+// 启用压缩时，客户端声明支持该算法则响应使用 CompressionType 压缩，请求可使用任意已注册的算法；
+// 压缩器需预先注册（导入 grpccompress 包），CompressionLevel 与 MinCompressSize 不作用于 gRPC 压缩器
+func (g *GRPCServer) ServerOptions() ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    seconds(g.KeepaliveTime),
+			Timeout: seconds(g.KeepaliveTimeout),
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             seconds(g.KeepaliveMinTime),
+			PermitWithoutStream: g.KeepalivePermitWithoutStream,
+		}),
+	}
+	if g.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(g.MaxRecvMsgSize))
+	}
+	if g.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(g.MaxSendMsgSize))
+	}
+	if g.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(g.MaxConcurrentStreams))
+	}
+	if g.ConnectionTimeout > 0 {
+		opts = append(opts, grpc.ConnectionTimeout(seconds(g.ConnectionTimeout)))
+	}
+	if g.TLSEnabled() {
+		cfg, err := g.TLS.ServerConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg)))
+	}
+	if g.EnableCompression {
+		name, err := compressorName(g.CompressionType)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				setSendCompressor(ctx, name)
+				return handler(ctx, req)
+			}),
+			grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				setSendCompressor(ss.Context(), name)
+				return handler(srv, ss)
+			}),
+		)
+	}
+	return opts, nil
+}
+
+// RegisterServices 按配置在 s 上注册反射与健康检查服务
+//
+// 返回的健康检查服务用于更新各服务的状态，未启用时返回 nil
+func (g *GRPCServer) RegisterServices(s *grpc.Server) *health.Server {
+	if g.EnableReflection {
+		reflection.Register(s)
+	}
+	if !g.EnableHealthCheck {
+		return nil
+	}
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	return hs
+}
+
+// DialOptions 将客户端配置转换为 grpc.DialOption，不包含目标地址
+func (g *GRPCClient) DialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if g.EnableTLS {
+		tlsConfig := &TLS{CertFile: g.TLSCertFile, KeyFile: g.TLSKeyFile, CAFile: g.TLSCAFile}
+		cfg, err := tlsConfig.ClientConfig("")
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	callOpts := []grpc.CallOption{grpc.WaitForReady(g.WaitForReady)}
+	if g.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(g.MaxRecvMsgSize))
+	}
+	if g.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(g.MaxSendMsgSize))
+	}
+	if g.EnableCompression {
+		name, err := compressorName(g.CompressionType)
+		if err != nil {
+			return nil, err
+		}
+		callOpts = append(callOpts, grpc.UseCompressor(name))
+	}
+	opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+
+	if g.KeepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                seconds(g.KeepaliveTime),
+			Timeout:             seconds(g.KeepaliveTimeout),
+			PermitWithoutStream: true,
+		}))
+	}
+	if g.InitialWindowSize > 0 {
+		opts = append(opts, grpc.WithInitialWindowSize(g.InitialWindowSize))
+	}
+	if g.InitialConnWindowSize > 0 {
+		opts = append(opts, grpc.WithInitialConnWindowSize(g.InitialConnWindowSize))
+	}
+	if g.ConnectionTimeout > 0 {
+		opts = append(opts, grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: seconds(g.ConnectionTimeout),
+		}))
+	}
+	if network := g.Network; network != "" && network != "tcp" {
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		}))
+	}
+	if sc := g.serviceConfig(); sc != "" {
+		opts = append(opts, grpc.WithDefaultServiceConfig(sc))
+	}
+	return opts, nil
+}
+
+// serviceConfig 生成负载均衡与重试的默认服务配置，两者均未启用时返回空
+func (g *GRPCClient) serviceConfig() string {
+	sc := map[string]any{}
+	if g.EnableLoadBalance && g.LoadBalancePolicy != "" {
+		sc["loadBalancingConfig"] = []map[string]any{{g.LoadBalancePolicy: map[string]any{}}}
+	}
+	if g.RetryTimes > 0 {
+		// gRPC 最多允许 5 次尝试
+		attempts := min(g.RetryTimes+1, 5)
+		sc["methodConfig"] = []map[string]any{{
+			"name": []map[string]any{{}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          attempts,
+				"initialBackoff":       "0.1s",
+				"maxBackoff":           "1s",
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+	if len(sc) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(sc)
+	return string(data)
+}
+
+// compressorName 返回压缩器名称，未设置时使用 gzip
+//
+// 压缩器需已向 gRPC 注册，snappy 与 zstd 由 grpccompress 包注册
+func compressorName(c GRPCCompressType) (string, error) {
+	if c == "" {
+		c = GRPCCompressGzip
+	}
+	switch c {
+	case GRPCCompressGzip, GRPCCompressSnappy, GRPCCompressZstd:
+	default:
+		return "", fmt.Errorf("unsupported grpc compression type: %q", c)
+	}
+	if encoding.GetCompressor(c.String()) == nil {
+		return "", fmt.Errorf("grpc compressor %q is not registered, import _ \"github.com/kamalyes/go-config/pkg/gateway/grpccompress\"", c)
+	}
+	return c.String(), nil
+}
+
+// setSendCompressor 客户端声明支持时设置响应压缩算法
+func setSendCompressor(ctx context.Context, name string) {
+	supported, err := grpc.ClientSupportedCompressors(ctx)
+	if err != nil || !slices.Contains(supported, name) {
+		return
+	}
+	_ = grpc.SetSendCompressor(ctx, name)
+}
+
+// seconds 将秒数转换为 time.Duration
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
@@ -0,0 +1,254 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\grpc_options_test.go
+ * @Description: GRPC 服务端与客户端选项测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package gateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/kamalyes/go-config/pkg/gateway/grpccompress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// compressionRecorder 记录收到的消息使用的压缩算法
+type compressionRecorder struct {
+	mu  sync.Mutex
+	got []string
+}
+
+func (r *compressionRecorder) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (r *compressionRecorder) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if h, ok := s.(*stats.InHeader); ok {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.got = append(r.got, h.Compression)
+	}
+}
+
+func (r *compressionRecorder) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (r *compressionRecorder) HandleConn(context.Context, stats.ConnStats) {}
+
+func (r *compressionRecorder) last() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.got) == 0 {
+		return ""
+	}
+	return r.got[len(r.got)-1]
+}
+
+// startServer 按配置在 bufconn 上启动服务端
+func startServer(t *testing.T, cfg *GRPCServer, extra ...grpc.ServerOption) (*bufconn.Listener, *grpc.Server) {
+	t.Helper()
+	opts, err := cfg.ServerOptions()
+	require.NoError(t, err)
+	srv := grpc.NewServer(append(opts, extra...)...)
+	cfg.EnableHealthService().RegisterServices(srv)
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis, srv
+}
+
+// dial 按配置连接 bufconn 服务端
+func dial(t *testing.T, lis *bufconn.Listener, target string, cfg *GRPCClient, extra ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+	opts, err := cfg.DialOptions()
+	require.NoError(t, err)
+	opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}))
+	conn, err := grpc.NewClient("passthrough:///"+target, append(opts, extra...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestGRPCOptions_Compression(t *testing.T) {
+	tests := []struct {
+		client, server GRPCCompressType
+	}{
+		{GRPCCompressGzip, GRPCCompressGzip},
+		{GRPCCompressZstd, GRPCCompressSnappy},
+		{GRPCCompressSnappy, GRPCCompressZstd},
+	}
+	for _, tt := range tests {
+		t.Run(tt.client.String()+"-"+tt.server.String(), func(t *testing.T) {
+			serverStats, clientStats := &compressionRecorder{}, &compressionRecorder{}
+			lis, _ := startServer(t, DefaultGRPCServer().WithCompression(tt.server, 5, 0), grpc.StatsHandler(serverStats))
+			client := dial(t, lis, "bufnet",
+				DefaultGRPCClient("test", nil).WithClientCompression(tt.client), grpc.WithStatsHandler(clientStats))
+
+			resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+			assert.Equal(t, tt.client.String(), serverStats.last(), "请求按客户端配置压缩")
+			assert.Equal(t, tt.server.String(), clientStats.last(), "响应按服务端配置压缩")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			require.NoError(t, err)
+			assert.Equal(t, tt.server.String(), clientStats.last())
+		})
+	}
+
+	_, err := DefaultGRPCServer().WithCompression("lz4", 1, 0).ServerOptions()
+	assert.Error(t, err)
+	_, err = DefaultGRPCClient("test", nil).WithClientCompression("brotli").DialOptions()
+	assert.Error(t, err)
+}
+
+func TestGRPCOptions_MessageSize(t *testing.T) {
+	lis, _ := startServer(t, DefaultGRPCServer().WithMaxMsgSize(1024, 1024))
+	client := dial(t, lis, "bufnet", DefaultGRPCClient("test", nil))
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 2048)})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "超过服务端接收上限")
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	small := DefaultGRPCClient("test", nil)
+	small.MaxSendMsgSize = 512
+	client = dial(t, lis, "bufnet", small)
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("x", 600)})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "超过客户端发送上限")
+}
+
+func TestGRPCOptions_KeepaliveEnforcement(t *testing.T) {
+	lis, _ := startServer(t, DefaultGRPCServer().WithKeepaliveEnforcement(60, false))
+
+	// 客户端 keepalive 间隔最小为 10 秒，直接发送 HTTP/2 PING 帧模拟频繁 ping
+	conn, err := lis.Dial()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Write([]byte(http2.ClientPreface))
+	require.NoError(t, err)
+	framer := http2.NewFramer(conn, conn)
+	require.NoError(t, framer.WriteSettings())
+	for i := range 5 {
+		require.NoError(t, framer.WritePing(false, [8]byte{byte(i)}))
+	}
+
+	for {
+		frame, err := framer.ReadFrame()
+		require.NoError(t, err, "服务端应以 GOAWAY 拒绝频繁 ping")
+		if goAway, ok := frame.(*http2.GoAwayFrame); ok {
+			assert.Equal(t, http2.ErrCodeEnhanceYourCalm, goAway.ErrCode)
+			assert.Equal(t, "too_many_pings", string(goAway.DebugData()))
+			return
+		}
+	}
+}
+
+func TestGRPCOptions_ServicesAndTLS(t *testing.T) {
+	srv := grpc.NewServer()
+	assert.NotNil(t, DefaultGRPCServer().EnableHealthService().RegisterServices(srv))
+	assert.Contains(t, srv.GetServiceInfo(), "grpc.health.v1.Health")
+	assert.Contains(t, srv.GetServiceInfo(), "grpc.reflection.v1.ServerReflection")
+
+	srv = grpc.NewServer()
+	assert.Nil(t, DefaultGRPCServer().RegisterServices(srv), "默认不注册健康检查服务")
+	assert.NotContains(t, srv.GetServiceInfo(), "grpc.health.v1.Health")
+
+	srv = grpc.NewServer()
+	assert.Nil(t, DefaultGRPCServer().DisableReflectionService().RegisterServices(srv))
+	assert.Empty(t, srv.GetServiceInfo())
+
+	certFile, keyFile := writeSelfSignedCert(t, "localhost")
+	lis, _ := startServer(t, DefaultGRPCServer().WithServerTLS(certFile, keyFile, ""))
+
+	client := dial(t, lis, "localhost", DefaultGRPCClient("test", nil).WithTLS("", "", certFile))
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	plain := dial(t, lis, "localhost", DefaultGRPCClient("test", nil))
+	_, err = plain.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Error(t, err, "明文客户端无法连接 TLS 服务端")
+
+	_, err = DefaultGRPCServer().WithServerTLS(filepath.Join(t.TempDir(), "missing.pem"), keyFile, "").ServerOptions()
+	assert.Error(t, err)
+
+	assert.True(t, DefaultGRPCServer().WithServerTLS(certFile, keyFile, "").TLS.Enabled)
+	assert.False(t, (&GRPCServer{TLS: &TLS{CertFile: certFile, KeyFile: keyFile}}).TLSEnabled(), "只配置证书不启用 TLS")
+
+	tlsOnly := DefaultGRPCServer()
+	tlsOnly.TLS = &TLS{Enabled: true, CertFile: certFile, KeyFile: keyFile}
+	assert.True(t, tlsOnly.TLSEnabled())
+	lis, _ = startServer(t, tlsOnly)
+	client = dial(t, lis, "localhost", DefaultGRPCClient("test", nil).WithTLS("", "", certFile))
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err, "仅设置 tls.enabled 时同样启用 TLS")
+	_, err = DefaultGRPCClient("test", nil).WithTLS("", "", keyFile).DialOptions()
+	assert.Error(t, err, "CA 文件中没有证书")
+}
+
+// writeSelfSignedCert 生成自签名证书，返回证书与私钥文件路径
+func writeSelfSignedCert(t *testing.T, host string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: host},
+		DNSNames:              []string{host},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
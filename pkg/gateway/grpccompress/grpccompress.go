@@ -0,0 +1,156 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\grpccompress\grpccompress.go
+ * @Description: gRPC gzip/snappy/zstd 压缩器注册，按需以 _ 导入
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+// Package grpccompress 在 init 阶段向 gRPC 注册 gzip、snappy 与 zstd 压缩器
+//
+// 压缩器按名称全局注册，会覆盖同名的已注册实现，因此不由 gateway 包自动导入；
+// 启用 GRPCServer/GRPCClient 的 snappy 或 zstd 压缩时需在 main 包中导入：
+//
+//	import _ "github.com/kamalyes/go-config/pkg/gateway/grpccompress"
+package grpccompress
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // 注册 gzip 压缩器
+)
+
+// 压缩器名称，与 gateway.GRPCCompressType 取值一致
+const (
+	Snappy = "snappy"
+	Zstd   = "zstd"
+)
+
+// gRPC 要求压缩器在 init 阶段注册
+func init() {
+	encoding.RegisterCompressor(&snappyCompressor{})
+	encoding.RegisterCompressor(&zstdCompressor{})
+}
+
+// snappyCompressor snappy 帧格式压缩器
+type snappyCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func (c *snappyCompressor) Name() string {
+	return Snappy
+}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	sw, ok := c.writers.Get().(*snappyWriter)
+	if !ok {
+		return &snappyWriter{Writer: snappy.NewBufferedWriter(w), pool: &c.writers}, nil
+	}
+	sw.Reset(w)
+	return sw, nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	sr, ok := c.readers.Get().(*snappyReader)
+	if !ok {
+		return &snappyReader{Reader: snappy.NewReader(r), pool: &c.readers}, nil
+	}
+	sr.Reset(r)
+	return sr, nil
+}
+
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+// Close 刷新缓冲数据后放回池中
+func (w *snappyWriter) Close() error {
+	defer w.pool.Put(w)
+	return w.Writer.Close()
+}
+
+type snappyReader struct {
+	*snappy.Reader
+	pool *sync.Pool
+}
+
+// Read 读取结束后放回池中
+func (r *snappyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r)
+	}
+	return n, err
+}
+
+// zstdCompressor zstd 压缩器，编解码器按单协程模式复用
+type zstdCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func (c *zstdCompressor) Name() string {
+	return Zstd
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	zw, ok := c.writers.Get().(*zstdWriter)
+	if !ok {
+		enc, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return &zstdWriter{Encoder: enc, pool: &c.writers}, nil
+	}
+	zw.Reset(w)
+	return zw, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	zr, ok := c.readers.Get().(*zstdReader)
+	if !ok {
+		dec, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return &zstdReader{Decoder: dec, pool: &c.readers}, nil
+	}
+	if err := zr.Reset(r); err != nil {
+		return nil, err
+	}
+	return zr, nil
+}
+
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+// Close 写入帧尾后放回池中
+func (w *zstdWriter) Close() error {
+	defer w.pool.Put(w)
+	return w.Encoder.Close()
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+// Read 读取结束后放回池中
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r)
+	}
+	return n, err
+}
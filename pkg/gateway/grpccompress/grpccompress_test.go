@@ -0,0 +1,47 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\grpccompress\grpccompress_test.go
+ * @Description: gRPC 压缩器注册测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package grpccompress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestCompressors_RoundTrip(t *testing.T) {
+	payload := []byte(strings.Repeat("go-config grpc compression ", 200))
+	for _, name := range []string{"gzip", Snappy, Zstd} {
+		c := encoding.GetCompressor(name)
+		require.NotNil(t, c, name)
+		// 多次往返以覆盖池中复用的编解码器
+		for range 3 {
+			var buf bytes.Buffer
+			w, err := c.Compress(&buf)
+			require.NoError(t, err)
+			_, err = w.Write(payload)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			assert.Less(t, buf.Len(), len(payload), name)
+
+			r, err := c.Decompress(&buf)
+			require.NoError(t, err)
+			got, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, payload, got, name)
+		}
+	}
+}
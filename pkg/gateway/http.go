@@ -93,10 +93,10 @@ type HTTPServer struct {
 	WriteTimeout         int               `mapstructure:"write-timeout" yaml:"write-timeout" json:"writeTimeout"`                           // 写入超时(秒)
 	IdleTimeout          int               `mapstructure:"idle-timeout" yaml:"idle-timeout" json:"idleTimeout"`                              // 空闲超时(秒)
 	MaxHeaderBytes       int               `mapstructure:"max-header-bytes" yaml:"max-header-bytes" json:"maxHeaderBytes"`                   // 最大请求头字节数
-	EnableTls            bool              `mapstructure:"enable-tls" yaml:"enable-tls" json:"enableTls"`                                    // 是否启用TLS
+	EnableTls            bool              `mapstructure:"enable-tls" yaml:"enable-tls" json:"enableTls"`                                    // 是否启用TLS（已废弃，使用 tls.enabled）
 	TLS                  *TLS              `mapstructure:"tls" yaml:"tls" json:"tls"`                                                        // TLS配置
 	Headers              map[string]string `mapstructure:"headers" yaml:"headers" json:"headers"`                                            // 自定义头部
-	Endpoint             string            `mapstructure:"-" yaml:"-" json:""`                                                               // 完整的服务端点地址（自动计算，不从配置文件读取）
+	Endpoint             string            `mapstructure:"-" yaml:"-" json:"-"`                                                              // 完整的服务端点地址（自动计算，不从配置文件读取）
 	EnableGzipCompress   bool              `mapstructure:"enable-gzip-compress" yaml:"enable-gzip-compress" json:"enableGzipCompress"`       // 是否启用Gzip压缩
 	GzipCompressionLevel int               `mapstructure:"gzip-compression-level" yaml:"gzip-compression-level" json:"gzipCompressionLevel"` // Gzip压缩级别 (1-9, 默认5)
 	GzipMinSize          int               `mapstructure:"gzip-min-size" yaml:"gzip-min-size" json:"gzipMinSize"`                            // Gzip最小压缩大小（字节），小于此大小不压缩（默认1024）
@@ -108,8 +108,9 @@ type HTTPServer struct {
 
 // TLS TLS配置
 type TLS struct {
+	Enabled             bool           `mapstructure:"enabled" yaml:"enabled" json:"enabled"`                                         // 是否启用TLS
 	CertFile            string         `mapstructure:"cert-file" yaml:"cert-file" json:"certFile"`                                    // 证书文件路径
-	KeyFile             string         `mapstructure:"key-file" yaml:"key-file" json:"keyFile"`                                        // 私钥文件路径
+	KeyFile             string         `mapstructure:"key-file" yaml:"key-file" json:"keyFile"`                                       // 私钥文件路径
 	CAFile              string         `mapstructure:"ca-file" yaml:"ca-file" json:"caFile"`                                          // CA文件路径
 	MinVersion          TLSVersion     `mapstructure:"min-version" yaml:"min-version" json:"minVersion"`                              // 最小TLS版本
 	PreferServerCiphers bool           `mapstructure:"prefer-server-ciphers" yaml:"prefer-server-ciphers" json:"preferServerCiphers"` // 优先使用服务器密码套件
@@ -156,6 +157,7 @@ func DefaultHTTPServer() *HTTPServer {
 		},
 		EnableTls: false,
 		TLS: &TLS{
+			Enabled:             false,
 			CertFile:            "",
 			KeyFile:             "",
 			CAFile:              "",
@@ -272,9 +274,17 @@ func (h *HTTPServer) EnableTLSService() *HTTPServer {
 // DisableTLS 禁用TLS
 func (h *HTTPServer) DisableTLS() *HTTPServer {
 	h.EnableTls = false
+	if h.TLS != nil {
+		h.TLS.Enabled = false
+	}
 	return h
 }
 
+// TLSEnabled 是否启用TLS，兼容已废弃的 enable-tls
+func (h *HTTPServer) TLSEnabled() bool {
+	return h.EnableTls || (h.TLS != nil && h.TLS.Enabled)
+}
+
 // EnableGzip 启用Gzip压缩
 func (h *HTTPServer) EnableGzip() *HTTPServer {
 	h.EnableGzipCompress = true
@@ -322,11 +332,11 @@ func (h *HTTPServer) WithHTTP2Config(maxStreams uint32, windowSize, connWindowSi
 // Listener 命名监听器配置，用于在同一进程中启动多个 HTTP 监听端口
 // 典型场景：Ops 内网端口 + Tenant 公网端口
 type Listener struct {
-	Name     string `mapstructure:"name" yaml:"name" json:"name"`           // 监听器名称（如 "ops", "tenant"）
-	Host     string `mapstructure:"host" yaml:"host" json:"host"`           // 主机地址
-	Port     int    `mapstructure:"port" yaml:"port" json:"port"`           // 端口
-	Network  string `mapstructure:"network" yaml:"network" json:"network"`  // 网络类型: tcp, tcp4, tcp6
-	Endpoint string `mapstructure:"-" yaml:"-" json:""`                     // 完整端点地址（自动计算）
+	Name     string `mapstructure:"name" yaml:"name" json:"name"`          // 监听器名称（如 "ops", "tenant"）
+	Host     string `mapstructure:"host" yaml:"host" json:"host"`          // 主机地址
+	Port     int    `mapstructure:"port" yaml:"port" json:"port"`          // 端口
+	Network  string `mapstructure:"network" yaml:"network" json:"network"` // 网络类型: tcp, tcp4, tcp6
+	Endpoint string `mapstructure:"-" yaml:"-" json:"-"`                   // 完整端点地址（自动计算）
 }
 
 // DefaultListener 创建默认监听器配置
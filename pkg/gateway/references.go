@@ -0,0 +1,258 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\references.go
+ * @Description: Gateway 跨模块引用校验（端口冲突、悬空引用、路径重叠）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kamalyes/go-logger"
+)
+
+// ReferenceRule 跨模块校验规则
+type ReferenceRule string
+
+const (
+	RulePortConflict      ReferenceRule = "port-conflict"      // 端口冲突
+	RuleDanglingReference ReferenceRule = "dangling-reference" // 引用不存在的对象
+	RulePathOverlap       ReferenceRule = "path-overlap"       // 同一服务上的路由重叠
+	RuleGzipSkipPath      ReferenceRule = "gzip-skip-path"     // 指标/健康路径未跳过压缩
+)
+
+// ReferenceSeverity 跨模块校验问题的严重程度
+type ReferenceSeverity string
+
+const (
+	ReferenceSeverityError   ReferenceSeverity = "error"   // 校验失败
+	ReferenceSeverityWarning ReferenceSeverity = "warning" // 仅提示，不影响校验结果
+)
+
+// Severity 规则的严重程度：悬空引用为错误，其余规则为建议性警告
+func (r ReferenceRule) Severity() ReferenceSeverity {
+	if r == RuleDanglingReference {
+		return ReferenceSeverityError
+	}
+	return ReferenceSeverityWarning
+}
+
+// ReferenceViolation 单条跨模块校验问题
+type ReferenceViolation struct {
+	Path     string            `json:"path"`     // 点分配置路径，如 monitoring.prometheus.port
+	Rule     ReferenceRule     `json:"rule"`     // 违反的规则
+	Severity ReferenceSeverity `json:"severity"` // 严重程度
+	Message  string            `json:"message"`  // 问题描述
+}
+
+// String 返回 "路径: 描述 [规则]" 格式，警告在规则后标注 warning
+func (v ReferenceViolation) String() string {
+	if v.Severity == ReferenceSeverityWarning {
+		return fmt.Sprintf("%s: %s [%s, warning]", v.Path, v.Message, v.Rule)
+	}
+	return fmt.Sprintf("%s: %s [%s]", v.Path, v.Message, v.Rule)
+}
+
+// ReferenceErrors 跨模块校验的全部问题，作为 error 一次性返回
+type ReferenceErrors []ReferenceViolation
+
+// Error 实现 error 接口，每条问题占一行
+func (e ReferenceErrors) Error() string {
+	lines := make([]string, 0, len(e)+1)
+	lines = append(lines, fmt.Sprintf("gateway reference validation failed with %d violation(s):", len(e)))
+	for _, v := range e {
+		lines = append(lines, "  "+v.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// boundPort 某个配置项占用的端口
+type boundPort struct {
+	path string
+	port int
+}
+
+// mountedRoute 挂载在主 HTTP 服务上的路由
+type mountedRoute struct {
+	path  string
+	route string
+}
+
+// ValidateReferences 校验模块之间的引用关系，只有错误级别的问题（悬空引用）返回 ReferenceErrors，
+// 端口冲突、路由重叠等警告写入日志
+func (c *Gateway) ValidateReferences() error {
+	var errs ReferenceErrors
+	for _, v := range c.CheckReferences() {
+		if v.Severity == ReferenceSeverityWarning {
+			logger.GetGlobalLogger().Warn("gateway: %s", v)
+			continue
+		}
+		errs = append(errs, v)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CheckReferences 收集所有跨模块问题（含警告），不会在第一个问题处中断
+//
+// 健康检查、Prometheus、PProf 的端口与 http.port 相同时视为挂载在主服务上；
+// 端口为 0 同样视为挂载在主服务上，其余情况视为独立监听
+func (c *Gateway) CheckReferences() []ReferenceViolation {
+	var violations []ReferenceViolation
+	add := func(path string, rule ReferenceRule, format string, args ...any) {
+		violations = append(violations, ReferenceViolation{Path: path, Rule: rule, Severity: rule.Severity(), Message: fmt.Sprintf(format, args...)})
+	}
+
+	mainPort := 0
+	if c.HTTPServer != nil {
+		mainPort = c.HTTPServer.Port
+	}
+
+	// 独立监听的端口：主服务、命名监听器、gRPC 服务端以及单独端口的辅助服务
+	var ports []boundPort
+	if mainPort > 0 {
+		ports = append(ports, boundPort{path: "http.port", port: mainPort})
+	}
+	for i, l := range c.Listeners {
+		if l != nil && l.Port > 0 {
+			ports = append(ports, boundPort{path: fmt.Sprintf("listeners[%d].port", i), port: l.Port})
+		}
+	}
+	if c.GRPC != nil && c.GRPC.Server != nil && c.GRPC.Server.Enable && c.GRPC.Server.Port > 0 {
+		ports = append(ports, boundPort{path: "grpc.server.port", port: c.GRPC.Server.Port})
+	}
+
+	var routes []mountedRoute
+	auxiliary := func(path string, port int, routePath, route string) {
+		if port <= 0 || port == mainPort {
+			if route != "" {
+				routes = append(routes, mountedRoute{path: routePath, route: route})
+			}
+			return
+		}
+		ports = append(ports, boundPort{path: path, port: port})
+	}
+
+	healthEnabled := c.Health != nil && c.Health.Enabled
+	if healthEnabled {
+		auxiliary("health.port", c.Health.Port, "health.path", c.Health.Path)
+	}
+	metricsEnabled := c.Monitoring != nil && c.Monitoring.Enabled && c.Monitoring.Prometheus != nil && c.Monitoring.Prometheus.Enabled
+	if metricsEnabled {
+		auxiliary("monitoring.prometheus.port", c.Monitoring.Prometheus.Port, "monitoring.prometheus.path", c.Monitoring.Prometheus.Path)
+	}
+	pprofEnabled := c.Middleware != nil && c.Middleware.PProf != nil && c.Middleware.PProf.Enabled
+	if pprofEnabled {
+		auxiliary("middleware.pprof.port", c.Middleware.PProf.Port, "middleware.pprof.path-prefix", c.Middleware.PProf.PathPrefix)
+	}
+	if c.Swagger != nil && c.Swagger.Enabled && c.Swagger.UIPath != "" {
+		routes = append(routes, mountedRoute{path: "swagger.ui-path", route: c.Swagger.UIPath})
+	}
+
+	// 端口冲突：同一端口只能被第一个配置项占用
+	owners := make(map[int]string, len(ports))
+	for _, p := range ports {
+		if owner, exists := owners[p.port]; exists {
+			add(p.path, RulePortConflict, "port %d is already bound by %s", p.port, owner)
+			continue
+		}
+		owners[p.port] = p.path
+	}
+
+	// 路由重叠：主服务上的路由不能相同或互为前缀
+	for i := 0; i < len(routes); i++ {
+		for j := i + 1; j < len(routes); j++ {
+			if routesOverlap(routes[i].route, routes[j].route) {
+				add(routes[j].path, RulePathOverlap, "route %q overlaps %s (%q)", routes[j].route, routes[i].path, routes[i].route)
+			}
+		}
+	}
+
+	// 服务保护必须指向已启用的服务
+	if c.Security != nil && c.Security.Protection != nil {
+		protection := c.Security.Protection
+		if protection.PProf != nil && protection.PProf.Enabled {
+			switch {
+			case !pprofEnabled:
+				add("security.protection.pprof", RuleDanglingReference, "pprof protection is enabled but middleware.pprof is disabled")
+			case c.Middleware.PProf.PathPrefix == "":
+				add("security.protection.pprof", RuleDanglingReference, "pprof protection is enabled but middleware.pprof.path-prefix is empty")
+			}
+		}
+		if protection.Metrics != nil && protection.Metrics.Enabled && !metricsEnabled {
+			add("security.protection.metrics", RuleDanglingReference, "metrics protection is enabled but monitoring.prometheus is disabled")
+		}
+		if protection.Health != nil && protection.Health.Enabled && !healthEnabled {
+			add("security.protection.health", RuleDanglingReference, "health protection is enabled but health is disabled")
+		}
+	}
+
+	// 聚合文档的数据源必须引用已声明的服务
+	if c.Swagger != nil && c.Swagger.Aggregate != nil {
+		services := make(map[string]struct{}, len(c.Swagger.Aggregate.Services))
+		for _, s := range c.Swagger.Aggregate.Services {
+			if s != nil {
+				services[s.Name] = struct{}{}
+			}
+		}
+		for i, doc := range c.Swagger.Aggregate.Documents {
+			if doc == nil {
+				continue
+			}
+			for j, src := range doc.Sources {
+				if src == nil {
+					continue
+				}
+				if _, ok := services[src.Service]; !ok {
+					add(fmt.Sprintf("swagger.aggregate.documents[%d].sources[%d].service", i, j), RuleDanglingReference,
+						"service %q is not declared in swagger.aggregate.services", src.Service)
+				}
+			}
+		}
+	}
+
+	// 主服务开启压缩时，挂载在主服务上的指标与健康路径需要跳过压缩
+	if c.HTTPServer != nil && c.HTTPServer.EnableGzipCompress {
+		for _, r := range routes {
+			if r.path != "health.path" && r.path != "monitoring.prometheus.path" {
+				continue
+			}
+			if !hasPathPrefix(c.HTTPServer.GzipSkipPaths, r.route) {
+				add("http.gzip-skip-paths", RuleGzipSkipPath, "%q (%s) is not skipped by gzip compression", r.route, r.path)
+			}
+		}
+	}
+
+	return violations
+}
+
+// routesOverlap 两个路由相同或一个是另一个的路径段前缀
+func routesOverlap(a, b string) bool {
+	a, b = strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/")
+	if a == b {
+		return true
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return strings.HasPrefix(b, a+"/")
+}
+
+// hasPathPrefix 路径是否被任一前缀覆盖
+func hasPathPrefix(prefixes []string, path string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
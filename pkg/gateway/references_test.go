@@ -0,0 +1,99 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\references_test.go
+ * @Description: Gateway 跨模块引用校验测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kamalyes/go-config/pkg/swagger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckReferences_Default(t *testing.T) {
+	assert.Empty(t, Default().CheckReferences())
+	assert.NoError(t, Default().ValidateReferences())
+}
+
+func TestCheckReferences_ReportsAllViolations(t *testing.T) {
+	c := Default()
+	c.Listeners = []*Listener{DefaultListener("ops"), DefaultListener("tenant").WithListenerPort(9100)}
+	c.Monitoring.Enabled = true
+	c.Monitoring.Prometheus.Enabled = true
+	c.Monitoring.Prometheus.Port = 9100
+	c.Middleware.PProf.Enabled = true
+	c.Middleware.PProf.Port = 0
+	c.Middleware.PProf.PathPrefix = "/health/pprof"
+	c.Security.Protection.Metrics.Enabled = true
+	c.HTTPServer.GzipSkipPaths = []string{"/ws"}
+	c.Swagger.Aggregate.Services = []*swagger.ServiceSpec{{Name: "user"}}
+	c.Swagger.Aggregate.Documents = []*swagger.DocumentSpec{{
+		Name:    "open",
+		Sources: []*swagger.DocumentSource{{Service: "user"}, {Service: "order"}},
+	}}
+
+	violations := c.CheckReferences()
+	got := make(map[string]ReferenceRule, len(violations))
+	for _, v := range violations {
+		got[v.Path] = v.Rule
+	}
+	assert.Equal(t, map[string]ReferenceRule{
+		"listeners[0].port":                                 RulePortConflict,
+		"monitoring.prometheus.port":                        RulePortConflict,
+		"middleware.pprof.path-prefix":                      RulePathOverlap,
+		"swagger.aggregate.documents[0].sources[1].service": RuleDanglingReference,
+		"http.gzip-skip-paths":                              RuleGzipSkipPath,
+	}, got)
+
+	for _, v := range violations {
+		assert.Equal(t, v.Rule.Severity(), v.Severity, v.Path)
+	}
+	assert.Contains(t, violations[1].String(), "monitoring.prometheus.port: port 9100 is already bound by listeners[1].port [port-conflict, warning]")
+
+	// 只有悬空引用导致校验失败，其余问题仅作为警告
+	err := c.ValidateReferences()
+	var refErrs ReferenceErrors
+	require.True(t, errors.As(err, &refErrs))
+	require.Len(t, refErrs, 1)
+	assert.Equal(t, RuleDanglingReference, refErrs[0].Rule)
+	assert.NotContains(t, err.Error(), "gzip")
+
+	c.Swagger.Aggregate.Services = append(c.Swagger.Aggregate.Services, &swagger.ServiceSpec{Name: "order"})
+	assert.NoError(t, c.ValidateReferences())
+}
+
+func TestCheckReferences_Protection(t *testing.T) {
+	c := Default()
+	c.Security.Protection.PProf.Enabled = true
+	c.Security.Protection.Health.Enabled = true
+	c.Health.Enabled = false
+
+	violations := c.CheckReferences()
+	require.Len(t, violations, 2)
+	assert.Equal(t, "security.protection.pprof", violations[0].Path)
+	assert.Contains(t, violations[0].Message, "middleware.pprof is disabled")
+	assert.Equal(t, "security.protection.health", violations[1].Path)
+
+	c.Middleware.PProf.Enabled = true
+	c.Middleware.PProf.PathPrefix = ""
+	violations = c.CheckReferences()
+	require.Len(t, violations, 2)
+	assert.Contains(t, violations[0].Message, "path-prefix is empty")
+}
+
+func TestRoutesOverlap(t *testing.T) {
+	assert.True(t, routesOverlap("/metrics", "/metrics/"))
+	assert.True(t, routesOverlap("/debug", "/debug/pprof"))
+	assert.False(t, routesOverlap("/health", "/healthz"))
+	assert.False(t, routesOverlap("/swagger", "/metrics"))
+}
@@ -0,0 +1,88 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\gateway\tls.go
+ * @Description: 由 TLS 配置构建 tls.Config
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package gateway
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// ServerConfig 构建服务端 tls.Config
+//
+// 配置了 CAFile 且未指定 ClientAuth 时要求并校验客户端证书
+func (t *TLS) ServerConfig() (*tls.Config, error) {
+	if t == nil || t.CertFile == "" || t.KeyFile == "" {
+		return nil, fmt.Errorf("tls cert-file and key-file are required")
+	}
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls key pair: %w", err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   t.MinVersion.ToUint16(),
+		NextProtos:   t.NextProtos,
+		ClientAuth:   t.ClientAuth.ToTLSClientAuth(),
+	}
+	if t.CAFile != "" {
+		if cfg.ClientCAs, err = loadCertPool(t.CAFile); err != nil {
+			return nil, err
+		}
+		if t.ClientAuth == "" {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return cfg, nil
+}
+
+// ClientConfig 构建客户端 tls.Config，CertFile/KeyFile 用于双向认证，CAFile 为空时使用系统根证书
+func (t *TLS) ClientConfig(serverName string) (*tls.Config, error) {
+	if t == nil {
+		t = &TLS{}
+	}
+	cfg := &tls.Config{
+		ServerName:         serverName,
+		MinVersion:         t.MinVersion.ToUint16(),
+		NextProtos:         t.NextProtos,
+		InsecureSkipVerify: t.InsecureSkipVerify, // 仅用于开发环境
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if t.CAFile != "" {
+		pool, err := loadCertPool(t.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// loadCertPool 读取 PEM 格式的 CA 证书
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read tls ca file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in tls ca file %s", file)
+	}
+	return pool, nil
+}
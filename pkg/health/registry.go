@@ -0,0 +1,365 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\health\registry.go
+ * @Description: 健康检查注册表与 livez/readyz/详情探针
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 固定的探针路径
+const (
+	LivezPath  = "/livez"
+	ReadyzPath = "/readyz"
+)
+
+// Status 检查状态
+type Status string
+
+const (
+	StatusUp       Status = "up"       // 正常
+	StatusDegraded Status = "degraded" // 非关键检查失败，不影响就绪
+	StatusDown     Status = "down"     // 关键检查失败
+)
+
+// Checker 组件健康检查
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 函数形式的 Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check 实现 Checker
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check 注册到 Registry 的检查项
+type Check struct {
+	Name     string        // 名称，redis/mysql 对应配置中的同名子项
+	Checker  Checker       // 检查逻辑
+	Timeout  time.Duration // 单次超时，0 时使用同名子项或 Health.Timeout
+	Critical bool          // 失败时是否影响就绪
+	Interval time.Duration // 结果缓存时间，0 表示每次探测都执行
+}
+
+// NewCheck 创建检查项，默认为关键检查
+func NewCheck(name string, checker Checker) *Check {
+	return &Check{Name: name, Checker: checker, Critical: true}
+}
+
+// WithTimeout 设置单次超时
+func (c *Check) WithTimeout(timeout time.Duration) *Check {
+	c.Timeout = timeout
+	return c
+}
+
+// WithCritical 设置是否为关键检查
+func (c *Check) WithCritical(critical bool) *Check {
+	c.Critical = critical
+	return c
+}
+
+// WithInterval 设置结果缓存时间
+func (c *Check) WithInterval(interval time.Duration) *Check {
+	c.Interval = interval
+	return c
+}
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Name        string     `json:"name"`
+	Status      Status     `json:"status"`
+	Critical    bool       `json:"critical"`
+	LatencyMs   float64    `json:"latencyMs"`
+	Error       string     `json:"error,omitempty"`       // 本次检查的错误
+	LastError   string     `json:"lastError,omitempty"`   // 最近一次失败的错误
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"` // 最近一次失败的时间
+	CheckedAt   time.Time  `json:"checkedAt"`
+	Cached      bool       `json:"cached"` // 是否为缓存结果
+}
+
+// Report 汇总检查结果
+type Report struct {
+	Status    Status                  `json:"status"`
+	Timestamp time.Time               `json:"timestamp"`
+	Checks    map[string]*CheckResult `json:"checks"`
+}
+
+// Ready 是否就绪：没有关键检查失败
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// entry 检查项及其缓存状态
+type entry struct {
+	check       *Check
+	timeout     time.Duration
+	mu          sync.Mutex // 串行执行同一检查，避免并发探测重复访问依赖
+	last        *CheckResult
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// Registry 健康检查注册表
+type Registry struct {
+	mu      sync.RWMutex
+	cfg     *Health
+	entries map[string]*entry
+	now     func() time.Time
+}
+
+// NewRegistry 根据配置创建注册表
+func NewRegistry(cfg *Health) *Registry {
+	if cfg == nil {
+		cfg = Default()
+	}
+	return &Registry{
+		cfg:     cfg.Clone().(*Health),
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// WithClock 设置时间源，便于测试
+func (r *Registry) WithClock(now func() time.Time) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = now
+	return r
+}
+
+// Register 注册检查项，名称不能重复
+func (r *Registry) Register(check *Check) error {
+	if check == nil || check.Name == "" || check.Checker == nil {
+		return fmt.Errorf("health check requires name and checker")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.entries[check.Name]; exists {
+		return fmt.Errorf("health check already registered: %s", check.Name)
+	}
+	cloned := *check
+	r.entries[check.Name] = &entry{check: &cloned, timeout: r.timeoutFor(&cloned)}
+	return nil
+}
+
+// Unregister 移除检查项
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, name)
+}
+
+// timeoutFor 计算检查项的超时：自身设置 > 同名子项配置 > Health.Timeout
+func (r *Registry) timeoutFor(check *Check) time.Duration {
+	if check.Timeout > 0 {
+		return check.Timeout
+	}
+	seconds := r.cfg.Timeout
+	switch {
+	case check.Name == "redis" && r.cfg.Redis != nil && r.cfg.Redis.Timeout > 0:
+		seconds = r.cfg.Redis.Timeout
+	case check.Name == "mysql" && r.cfg.MySQL != nil && r.cfg.MySQL.Timeout > 0:
+		seconds = r.cfg.MySQL.Timeout
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Run 并发执行指定检查项，names 为空时执行全部
+func (r *Registry) Run(ctx context.Context, names ...string) *Report {
+	r.mu.RLock()
+	var entries []*entry
+	for name, e := range r.entries {
+		if len(names) == 0 || slices.Contains(names, name) {
+			entries = append(entries, e)
+		}
+	}
+	now := r.now
+	r.mu.RUnlock()
+
+	results := make([]*CheckResult, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			results[i] = e.run(ctx, now)
+		}(i, e)
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusUp, Timestamp: now(), Checks: make(map[string]*CheckResult, len(results))}
+	for _, res := range results {
+		report.Checks[res.Name] = res
+		switch {
+		case res.Status == StatusDown:
+			report.Status = StatusDown
+		case res.Status == StatusDegraded && report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run 执行单个检查，缓存未过期时直接返回缓存结果
+func (e *entry) run(ctx context.Context, now func() time.Time) *CheckResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	start := now()
+	if e.last != nil && e.check.Interval > 0 && start.Sub(e.last.CheckedAt) < e.check.Interval {
+		cached := *e.last
+		cached.Cached = true
+		return &cached
+	}
+
+	err := e.call(ctx)
+	res := &CheckResult{
+		Name:      e.check.Name,
+		Status:    StatusUp,
+		Critical:  e.check.Critical,
+		LatencyMs: float64(now().Sub(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		res.Status = StatusDegraded
+		if e.check.Critical {
+			res.Status = StatusDown
+		}
+		res.Error = err.Error()
+		e.lastError, e.lastErrorAt = res.Error, start
+	}
+	if e.lastError != "" {
+		at := e.lastErrorAt
+		res.LastError, res.LastErrorAt = e.lastError, &at
+	}
+	e.last = res
+	copied := *res
+	return &copied
+}
+
+// call 在超时内执行检查；检查未响应 ctx 时同样按超时返回，panic 视为失败
+func (e *entry) call(ctx context.Context) error {
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("health check panicked: %v", p)
+			}
+		}()
+		done <- e.check.Checker.Check(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out: %w", ctx.Err())
+	}
+}
+
+// Handler 返回包含全部探针的处理器：
+//
+//	/livez      进程存活，始终返回 200
+//	/readyz     执行全部检查，存在失败的关键检查时返回 503
+//	Health.Path 详细 JSON 报告
+//
+// Redis/MySQL 子项启用时，其 Path 返回同名检查项的详细报告；健康检查未启用时全部返回 404
+func (r *Registry) Handler() http.Handler {
+	mux := http.NewServeMux()
+	if !r.cfg.Enabled {
+		return mux
+	}
+	mux.HandleFunc(LivezPath, r.Livez)
+	mux.HandleFunc(ReadyzPath, r.Readyz)
+	if r.cfg.Path != "" && r.cfg.Path != LivezPath && r.cfg.Path != ReadyzPath {
+		mux.Handle(r.cfg.Path, r.DetailHandler())
+	}
+	if r.cfg.Redis != nil && r.cfg.Redis.Enabled && r.cfg.Redis.Path != "" {
+		mux.Handle(r.cfg.Redis.Path, r.DetailHandler("redis"))
+	}
+	if r.cfg.MySQL != nil && r.cfg.MySQL.Enabled && r.cfg.MySQL.Path != "" {
+		mux.Handle(r.cfg.MySQL.Path, r.DetailHandler("mysql"))
+	}
+	return mux
+}
+
+// Livez 存活探针
+func (r *Registry) Livez(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte("ok\n"))
+}
+
+// Readyz 就绪探针，逐行输出各检查项状态，降级的非关键检查不影响结果
+func (r *Registry) Readyz(w http.ResponseWriter, req *http.Request) {
+	report := r.Run(req.Context())
+	var b strings.Builder
+	for _, name := range sortedNames(report.Checks) {
+		res := report.Checks[name]
+		mark := "+"
+		if res.Status != StatusUp {
+			mark = "-"
+		}
+		fmt.Fprintf(&b, "[%s]%s %s", mark, name, res.Status)
+		if res.Error != "" {
+			fmt.Fprintf(&b, ": %s", res.Error)
+		}
+		b.WriteByte('\n')
+	}
+	code := http.StatusOK
+	if report.Ready() {
+		b.WriteString("readyz check passed\n")
+	} else {
+		code = http.StatusServiceUnavailable
+		b.WriteString("readyz check failed\n")
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	w.Write([]byte(b.String()))
+}
+
+// DetailHandler 返回 JSON 详细报告，names 为空时包含全部检查项；存在失败的关键检查时返回 503
+func (r *Registry) DetailHandler(names ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Run(req.Context(), names...)
+		code := http.StatusOK
+		if !report.Ready() {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// sortedNames 返回排序后的检查项名称
+func sortedNames(checks map[string]*CheckResult) []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,173 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\health\registry_test.go
+ * @Description: 健康检查注册表测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChecker 返回预设错误并记录调用次数
+type fakeChecker struct {
+	mu    sync.Mutex
+	err   error
+	calls atomic.Int32
+	delay time.Duration
+}
+
+func (f *fakeChecker) Check(ctx context.Context) error {
+	f.calls.Add(1)
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *fakeChecker) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func get(t *testing.T, srv *httptest.Server, path string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestRegistry_Probes(t *testing.T) {
+	db, cache := &fakeChecker{}, &fakeChecker{}
+	r := NewRegistry(Default())
+	require.NoError(t, r.Register(NewCheck("db", db)))
+	require.NoError(t, r.Register(NewCheck("cache", cache).WithCritical(false)))
+	assert.Error(t, r.Register(NewCheck("db", db)))
+	assert.Error(t, r.Register(NewCheck("", db)))
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+
+	code, body := get(t, srv, LivezPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok\n", body)
+
+	code, body = get(t, srv, ReadyzPath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+]cache up\n[+]db up\nreadyz check passed\n", body)
+
+	cache.fail(errors.New("connection refused"))
+	code, body = get(t, srv, ReadyzPath)
+	assert.Equal(t, http.StatusOK, code, "非关键检查降级不影响就绪")
+	assert.Contains(t, body, "[-]cache degraded: connection refused")
+
+	db.fail(errors.New("too many connections"))
+	code, body = get(t, srv, ReadyzPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, body, "readyz check failed")
+
+	code, body = get(t, srv, "/health")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	var report Report
+	require.NoError(t, json.Unmarshal([]byte(body), &report))
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, StatusDegraded, report.Checks["cache"].Status)
+	assert.Equal(t, "too many connections", report.Checks["db"].Error)
+	assert.True(t, report.Checks["db"].Critical)
+
+	db.fail(nil)
+	report = *r.Run(context.Background(), "db")
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Len(t, report.Checks, 1)
+	assert.Empty(t, report.Checks["db"].Error)
+	assert.Equal(t, "too many connections", report.Checks["db"].LastError, "恢复后保留最近一次错误")
+	assert.NotNil(t, report.Checks["db"].LastErrorAt)
+}
+
+func TestRegistry_ConcurrentWithTimeouts(t *testing.T) {
+	r := NewRegistry(Default())
+	slow := &fakeChecker{delay: time.Second}
+	require.NoError(t, r.Register(NewCheck("slow", slow).WithTimeout(50*time.Millisecond)))
+	require.NoError(t, r.Register(NewCheck("a", &fakeChecker{delay: 30 * time.Millisecond})))
+	require.NoError(t, r.Register(NewCheck("b", &fakeChecker{delay: 30 * time.Millisecond})))
+	require.NoError(t, r.Register(NewCheck("panic", CheckerFunc(func(context.Context) error { panic("boom") })).WithCritical(false)))
+
+	start := time.Now()
+	report := r.Run(context.Background())
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "检查并发执行且按超时返回")
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Contains(t, report.Checks["slow"].Error, "timed out")
+	assert.Equal(t, StatusUp, report.Checks["a"].Status)
+	assert.Equal(t, StatusDegraded, report.Checks["panic"].Status)
+	assert.Contains(t, report.Checks["panic"].Error, "boom")
+}
+
+func TestRegistry_CacheInterval(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	r := NewRegistry(Default()).WithClock(func() time.Time { return now })
+	checker := &fakeChecker{}
+	require.NoError(t, r.Register(NewCheck("db", checker).WithInterval(10*time.Second)))
+
+	assert.False(t, r.Run(context.Background()).Checks["db"].Cached)
+	now = now.Add(5 * time.Second)
+	assert.True(t, r.Run(context.Background()).Checks["db"].Cached)
+	assert.Equal(t, int32(1), checker.calls.Load())
+
+	now = now.Add(5 * time.Second)
+	assert.False(t, r.Run(context.Background()).Checks["db"].Cached)
+	assert.Equal(t, int32(2), checker.calls.Load())
+}
+
+func TestRegistry_ComponentConfig(t *testing.T) {
+	cfg := Default().WithTimeout(7).WithRedisCheck(true, "/health/redis", 2)
+	r := NewRegistry(cfg)
+	assert.Equal(t, 2*time.Second, r.timeoutFor(NewCheck("redis", &fakeChecker{})))
+	assert.Equal(t, 5*time.Second, r.timeoutFor(NewCheck("mysql", &fakeChecker{})))
+	assert.Equal(t, 7*time.Second, r.timeoutFor(NewCheck("other", &fakeChecker{})))
+	assert.Equal(t, time.Second, r.timeoutFor(NewCheck("redis", &fakeChecker{}).WithTimeout(time.Second)))
+
+	redis := &fakeChecker{}
+	redis.fail(errors.New("no route to host"))
+	require.NoError(t, r.Register(NewCheck("redis", redis)))
+	require.NoError(t, r.Register(NewCheck("db", &fakeChecker{})))
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+	code, body := get(t, srv, "/health/redis")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	var report Report
+	require.NoError(t, json.Unmarshal([]byte(body), &report))
+	assert.Len(t, report.Checks, 1)
+
+	code, _ = get(t, srv, "/health/mysql")
+	assert.Equal(t, http.StatusNotFound, code, "未启用的子项不注册路径")
+
+	disabled := httptest.NewServer(NewRegistry(Default().Disable()).Handler())
+	defer disabled.Close()
+	code, _ = get(t, disabled, ReadyzPath)
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,511 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\i18n\catalog.go
+ * @Description: 多语言消息目录：加载 YAML/JSON/TOML 消息文件、复数、插值、回退与缺失键检查
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// messageExtensions 支持的消息文件扩展名
+var messageExtensions = []string{".yaml", ".yml", ".json", ".toml"}
+
+// placeholderPattern 命名参数占位符，如 {name}
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// message 单条消息，复数消息按类别保存各形式
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+// Catalog 多语言消息目录，并发安全
+//
+// 消息来源按以下顺序合并，后者覆盖前者：
+//  1. MessagesPath 下的 <lang>.yaml|yml|json|toml
+//  2. MessagesPath/<lang>/ 目录下的全部消息文件
+//  3. CustomMessagePaths 中该语言对应的文件或目录
+//  4. MessageLoader 返回的消息
+//
+// 嵌套结构以 "." 拼接为键；叶子键为 zero/one/two/few/many/other 的映射视为复数消息
+type Catalog struct {
+	mu       sync.RWMutex
+	config   *I18N
+	messages map[string]map[string]*message // 语言 -> 键 -> 消息
+	debounce time.Duration
+	onReload func(err error)
+}
+
+// NewCatalog 创建并加载消息目录
+func NewCatalog(cfg *I18N) (*Catalog, error) {
+	c := &Catalog{config: cfg, debounce: 200 * time.Millisecond}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WithDebounce 设置热更新防抖时间
+func (c *Catalog) WithDebounce(d time.Duration) *Catalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.debounce = d
+	return c
+}
+
+// WithReloadHook 设置热更新完成回调，err 为加载失败原因（失败时保留原消息）
+func (c *Catalog) WithReloadHook(fn func(err error)) *Catalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onReload = fn
+	return c
+}
+
+// Config 返回目录使用的配置
+func (c *Catalog) Config() *I18N {
+	return c.config
+}
+
+// Reload 重新加载全部消息，失败时保留原消息
+func (c *Catalog) Reload() error {
+	messages, err := c.load()
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.messages = messages
+	c.mu.Unlock()
+	return nil
+}
+
+// Languages 返回已加载消息的语言，按字母排序
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	langs := make([]string, 0, len(c.messages))
+	for lang := range c.messages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Has 消息是否存在于指定语言（不含回退）
+func (c *Catalog) Has(lang, key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.messages[strings.ToLower(lang)][key]
+	return ok
+}
+
+// T 翻译消息，按回退链查找并替换 {name} 占位符；找不到时返回 key
+//
+// 复数消息使用 other 形式，需要按数量选择时使用 Plural
+func (c *Catalog) T(lang, key string, args map[string]any) string {
+	msg, _ := c.lookup(lang, key)
+	if msg == nil {
+		return key
+	}
+	text := msg.text
+	if msg.plural != nil {
+		text = msg.plural[PluralOther]
+	}
+	return interpolate(text, args)
+}
+
+// Plural 按数量选择复数形式并插值，count 会以 "count" 参数传入（args 中已存在时不覆盖）
+//
+// 复数类别取自命中消息所在语言的 CLDR 规则，该形式缺失时使用 other
+func (c *Catalog) Plural(lang, key string, count any, args map[string]any) string {
+	msg, found := c.lookup(lang, key)
+	if msg == nil {
+		return key
+	}
+	n, err := toFloat(count)
+	if err != nil {
+		return key
+	}
+	if _, ok := args["count"]; !ok {
+		merged := make(map[string]any, len(args)+1)
+		for k, v := range args {
+			merged[k] = v
+		}
+		merged["count"] = count
+		args = merged
+	}
+	if msg.plural == nil {
+		return interpolate(msg.text, args)
+	}
+	text, ok := msg.plural[PluralCategory(found, n)]
+	if !ok {
+		text = msg.plural[PluralOther]
+	}
+	return interpolate(text, args)
+}
+
+// FallbackChain 返回语言的查找顺序：原语言、映射后的语言、基础语言，启用回退时追加默认语言
+func (c *Catalog) FallbackChain(lang string) []string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	mapped := c.config.mapLanguage(lang)
+	chain := make([]string, 0, 5)
+	for _, candidate := range []string{lang, mapped, baseLanguage(mapped), baseLanguage(lang)} {
+		if candidate != "" && !slices.Contains(chain, candidate) {
+			chain = append(chain, candidate)
+		}
+	}
+	if c.config.EnableFallback && c.config.DefaultLanguage != "" {
+		if def := strings.ToLower(c.config.DefaultLanguage); !slices.Contains(chain, def) {
+			chain = append(chain, def)
+		}
+	}
+	return chain
+}
+
+// lookup 按回退链查找消息，返回消息及其所在语言
+func (c *Catalog) lookup(lang, key string) (*message, string) {
+	chain := c.FallbackChain(lang)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, candidate := range chain {
+		if msg, ok := c.messages[candidate][key]; ok {
+			return msg, candidate
+		}
+	}
+	return nil, ""
+}
+
+// LintIssue 消息检查问题
+type LintIssue struct {
+	Language string
+	Key      string
+	Problem  string
+}
+
+// String 返回问题描述
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Language, i.Key, i.Problem)
+}
+
+// Lint 检查各语言缺失的键，以及复数消息缺少该语言所需的复数形式
+//
+// 检查范围为已加载的语言与 SupportedLanguages 的并集，结果按键、语言排序
+func (c *Catalog) Lint() []LintIssue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	langs := make([]string, 0, len(c.messages))
+	for lang := range c.messages {
+		langs = append(langs, lang)
+	}
+	for _, lang := range c.config.SupportedLanguages {
+		if lang = strings.ToLower(lang); !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+
+	keys := map[string]bool{}
+	for _, msgs := range c.messages {
+		for key := range msgs {
+			keys[key] = true
+		}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	var issues []LintIssue
+	for _, key := range sortedKeys {
+		for _, lang := range langs {
+			msg, ok := c.messages[lang][key]
+			if !ok {
+				issues = append(issues, LintIssue{Language: lang, Key: key, Problem: "missing translation"})
+				continue
+			}
+			if msg.plural == nil {
+				continue
+			}
+			var missing []string
+			for _, category := range PluralCategories(lang) {
+				if _, ok := msg.plural[category]; !ok {
+					missing = append(missing, category)
+				}
+			}
+			if len(missing) > 0 {
+				issues = append(issues, LintIssue{Language: lang, Key: key, Problem: "missing plural forms: " + strings.Join(missing, ", ")})
+			}
+		}
+	}
+	return issues
+}
+
+// load 读取全部消息来源
+func (c *Catalog) load() (map[string]map[string]*message, error) {
+	cfg := c.config
+	all := map[string]map[string]*message{}
+	merge := func(lang string, flat map[string]string) {
+		lang = strings.ToLower(lang)
+		if all[lang] == nil {
+			all[lang] = map[string]*message{}
+		}
+		addMessages(all[lang], flat)
+	}
+
+	var errs []error
+	if cfg.MessagesPath != "" {
+		entries, err := readMessageDir(cfg.MessagesPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("failed to read messages path %s: %w", cfg.MessagesPath, err))
+		}
+		// 先加载 <lang>.<ext>，再加载 <lang>/ 目录
+		for _, entry := range entries {
+			if lang, ok := messageFileLanguage(entry.name); ok && !entry.isDir {
+				flat, err := loadMessageFile(filepath.Join(cfg.MessagesPath, entry.name))
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				merge(lang, flat)
+			}
+		}
+		for _, entry := range entries {
+			if entry.isDir {
+				flat, err := loadMessagePath(filepath.Join(cfg.MessagesPath, entry.name))
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				merge(entry.name, flat)
+			}
+		}
+	}
+
+	customLangs := make([]string, 0, len(cfg.CustomMessagePaths))
+	for lang := range cfg.CustomMessagePaths {
+		customLangs = append(customLangs, lang)
+	}
+	sort.Strings(customLangs)
+	for _, lang := range customLangs {
+		flat, err := loadMessagePath(cfg.CustomMessagePaths[lang])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		merge(lang, flat)
+	}
+
+	if cfg.MessageLoader != nil {
+		for _, lang := range cfg.SupportedLanguages {
+			flat, err := cfg.MessageLoader.LoadMessages(lang)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("message loader failed for %q: %w", lang, err))
+				continue
+			}
+			merge(lang, flat)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return all, nil
+}
+
+// messageFileLanguage 由文件名解析语言，如 zh-cn.yaml -> zh-cn
+func messageFileLanguage(name string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !slices.Contains(messageExtensions, ext) {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name))), true
+}
+
+// loadMessagePath 加载单个消息文件，或按文件名顺序加载目录下的全部消息文件
+func loadMessagePath(path string) (map[string]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat message path %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return loadMessageFile(path)
+	}
+	entries, err := readMessageDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message dir %s: %w", path, err)
+	}
+	merged := map[string]string{}
+	for _, entry := range entries {
+		if _, ok := messageFileLanguage(entry.name); !ok || entry.isDir {
+			continue
+		}
+		flat, err := loadMessageFile(filepath.Join(path, entry.name))
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range flat {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
+
+// messageEntry 消息目录项，isDir 已解析符号链接
+type messageEntry struct {
+	name  string
+	isDir bool
+}
+
+// readMessageDir 列出目录项，跳过以 "." 开头的隐藏项（如 ConfigMap 挂载的 ..data 与版本目录），
+// 符号链接按其目标判断是否为目录，以支持 ConfigMap 中 <lang> -> ..data/<lang> 的布局
+func readMessageDir(dir string) ([]messageEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]messageEntry, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			info, err := os.Stat(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			isDir = info.IsDir()
+		}
+		result = append(result, messageEntry{name: entry.Name(), isDir: isDir})
+	}
+	return result, nil
+}
+
+// loadMessageFile 按扩展名解析消息文件并展开为扁平键
+func loadMessageFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message file %s: %w", path, err)
+	}
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message file %s: %w", path, err)
+	}
+	flat := map[string]string{}
+	flatten("", raw, flat)
+	return flat, nil
+}
+
+// flatten 将嵌套结构展开为以 "." 连接的键
+func flatten(prefix string, value any, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, child := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(key, child, out)
+		}
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+// addMessages 将扁平消息加入语言消息表，键以复数类别结尾时归入复数消息
+func addMessages(msgs map[string]*message, flat map[string]string) {
+	for key, text := range flat {
+		if idx := strings.LastIndex(key, "."); idx > 0 && slices.Contains(pluralCategories, key[idx+1:]) {
+			base, category := key[:idx], key[idx+1:]
+			msg := msgs[base]
+			if msg == nil || msg.plural == nil {
+				msg = &message{plural: map[string]string{}}
+				msgs[base] = msg
+			}
+			msg.plural[category] = text
+			continue
+		}
+		msgs[key] = &message{text: text}
+	}
+}
+
+// interpolate 替换 {name} 占位符，未提供的参数保持原样
+func interpolate(text string, args map[string]any) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if v, ok := args[match[1:len(match)-1]]; ok {
+			return fmt.Sprint(v)
+		}
+		return match
+	})
+}
+
+// toFloat 将数量转换为 float64
+func toFloat(count any) (float64, error) {
+	switch v := count.(type) {
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("unsupported plural count type %T", count)
+	}
+}
@@ -0,0 +1,230 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\i18n\catalog_test.go
+ * @Description: 消息目录加载、复数、回退、检查与热更新测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package i18n
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapLoader 内存消息加载器
+type mapLoader map[string]map[string]string
+
+func (l mapLoader) LoadMessages(language string) (map[string]string, error) {
+	if language == "broken" {
+		return nil, errors.New("loader down")
+	}
+	return l[language], nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func newTestCatalog(t *testing.T) (*Catalog, string) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "en.yaml"), `
+greeting: "Hello, {name}!"
+cart:
+  items:
+    one: "{count} item"
+    other: "{count} items"
+only_en: "English only"
+`)
+	writeFile(t, filepath.Join(dir, "zh.json"), `{"greeting": "你好，{name}！", "cart": {"items": {"other": "{count} 件商品"}}}`)
+	writeFile(t, filepath.Join(dir, "ru.toml"), `
+greeting = "Привет, {name}!"
+[cart.items]
+one = "{count} товар"
+few = "{count} товара"
+other = "{count} товаров"
+`)
+	// 语言子目录中的文件覆盖同名键
+	writeFile(t, filepath.Join(dir, "zh", "errors.yml"), "errors:\n  not_found: 未找到\n")
+	custom := filepath.Join(t.TempDir(), "zh-tw.yaml")
+	writeFile(t, custom, "greeting: 您好，{name}！\n")
+
+	cfg := Default().
+		WithMessagesPath(dir).
+		WithSupportedLanguages([]string{"en", "zh", "ru", "zh-tw"}).
+		AddLanguageMapping("zh-hk", "zh-tw")
+	cfg.CustomMessagePaths = map[string]string{"zh-tw": custom}
+	cfg.MessageLoader = mapLoader{"en": {"errors.not_found": "Not found", "errors.busy": "Busy"}}
+
+	c, err := NewCatalog(cfg)
+	require.NoError(t, err)
+	return c, dir
+}
+
+func TestCatalog_LoadAndTranslate(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	assert.Equal(t, []string{"en", "ru", "zh", "zh-tw"}, c.Languages())
+
+	assert.Equal(t, "Hello, Ada!", c.T("en", "greeting", map[string]any{"name": "Ada"}))
+	assert.Equal(t, "你好，{name}！", c.T("zh", "greeting", nil), "缺少参数时保留占位符")
+	assert.Equal(t, "未找到", c.T("zh", "errors.not_found", nil))
+	assert.Equal(t, "Not found", c.T("en", "errors.not_found", nil), "MessageLoader 的消息")
+	assert.Equal(t, "missing.key", c.T("en", "missing.key", nil))
+
+	// 回退链：zh-hk -> zh-tw（映射）-> zh（基础语言）-> en（默认语言）
+	assert.Equal(t, []string{"zh-hk", "zh-tw", "zh", "en"}, c.FallbackChain("zh-HK"))
+	assert.Equal(t, "您好，Li！", c.T("zh-HK", "greeting", map[string]any{"name": "Li"}))
+	assert.Equal(t, "未找到", c.T("zh-tw", "errors.not_found", nil))
+	assert.Equal(t, "English only", c.T("ru", "only_en", nil))
+
+	c.Config().EnableFallback = false
+	assert.Equal(t, "only_en", c.T("ru", "only_en", nil))
+}
+
+func TestCatalog_Plural(t *testing.T) {
+	c, _ := newTestCatalog(t)
+
+	assert.Equal(t, "1 item", c.Plural("en", "cart.items", 1, nil))
+	assert.Equal(t, "0 items", c.Plural("en", "cart.items", 0, nil))
+	assert.Equal(t, "1.5 items", c.Plural("en", "cart.items", 1.5, nil))
+	assert.Equal(t, "1 件商品", c.Plural("zh", "cart.items", 1, nil))
+	assert.Equal(t, "21 товар", c.Plural("ru", "cart.items", 21, nil))
+	assert.Equal(t, "3 товара", c.Plural("ru", "cart.items", 3, nil))
+	assert.Equal(t, "11 товаров", c.Plural("ru", "cart.items", 11, nil), "many 缺失时使用 other")
+	assert.Equal(t, "several items", c.Plural("en", "cart.items", 2, map[string]any{"count": "several"}))
+	assert.Equal(t, "{count} items", c.T("en", "cart.items", nil), "T 使用 other 形式")
+	assert.Equal(t, "cart.items", c.Plural("en", "cart.items", struct{}{}, nil))
+}
+
+func TestCatalog_Lint(t *testing.T) {
+	c, _ := newTestCatalog(t)
+	var issues []string
+	for _, issue := range c.Lint() {
+		issues = append(issues, issue.String())
+	}
+	assert.Equal(t, []string{
+		"ru: cart.items: missing plural forms: many",
+		"zh-tw: cart.items: missing translation",
+		"ru: errors.busy: missing translation",
+		"zh: errors.busy: missing translation",
+		"zh-tw: errors.busy: missing translation",
+		"ru: errors.not_found: missing translation",
+		"zh-tw: errors.not_found: missing translation",
+		"ru: only_en: missing translation",
+		"zh: only_en: missing translation",
+		"zh-tw: only_en: missing translation",
+	}, issues)
+}
+
+func TestCatalog_LoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "en.json"), `{"broken": `)
+
+	cfg := Default().WithMessagesPath(dir)
+	cfg.CustomMessagePaths = map[string]string{"fr": filepath.Join(dir, "missing.yaml")}
+	cfg.MessageLoader = mapLoader{}
+	cfg.SupportedLanguages = []string{"broken"}
+	_, err := NewCatalog(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse message file")
+	assert.Contains(t, err.Error(), "failed to stat message path")
+	assert.Contains(t, err.Error(), `message loader failed for "broken": loader down`)
+
+	// 消息目录不存在时视为空目录
+	c, err := NewCatalog(Default().WithMessagesPath(filepath.Join(dir, "none")))
+	require.NoError(t, err)
+	assert.Empty(t, c.Languages())
+}
+
+func TestCatalog_Watch(t *testing.T) {
+	c, dir := newTestCatalog(t)
+	reloaded := make(chan error, 10)
+	c.WithDebounce(20 * time.Millisecond).WithReloadHook(func(err error) { reloaded <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, c.Watch(ctx))
+
+	wait := func() error {
+		select {
+		case err := <-reloaded:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload")
+			return nil
+		}
+	}
+
+	writeFile(t, filepath.Join(dir, "en.yaml"), "greeting: Hi, {name}\n")
+	require.NoError(t, wait())
+	assert.Equal(t, "Hi, Bo", c.T("en", "greeting", map[string]any{"name": "Bo"}))
+
+	// 新建语言目录自动加入监控
+	writeFile(t, filepath.Join(dir, "fr", "main.yaml"), "greeting: Bonjour\n")
+	require.NoError(t, wait())
+	assert.Eventually(t, func() bool { return c.Has("fr", "greeting") }, 5*time.Second, 20*time.Millisecond)
+
+	// 丢弃新建目录可能引起的重复加载通知
+	time.Sleep(100 * time.Millisecond)
+	for len(reloaded) > 0 {
+		<-reloaded
+	}
+
+	// 文件损坏时保留原消息
+	writeFile(t, filepath.Join(dir, "en.yaml"), "greeting: [\n")
+	assert.Error(t, wait())
+	assert.Equal(t, "Hi, Bo", c.T("en", "greeting", map[string]any{"name": "Bo"}))
+}
+
+func TestCatalog_WatchConfigMapSwap(t *testing.T) {
+	// 模拟 Kubernetes ConfigMap 挂载：en.yaml -> ..data/en.yaml，zh -> ..data/zh，..data -> ..<version>
+	dir := t.TempDir()
+	swap := func(version, en, zh string) {
+		writeFile(t, filepath.Join(dir, version, "en.yaml"), en)
+		writeFile(t, filepath.Join(dir, version, "zh", "main.yaml"), zh)
+		tmpLink := filepath.Join(dir, "..data_tmp")
+		require.NoError(t, os.Symlink(version, tmpLink))
+		require.NoError(t, os.Rename(tmpLink, filepath.Join(dir, "..data")))
+	}
+	swap("..v1", "greeting: Hello\n", "greeting: 你好\n")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "en.yaml"), filepath.Join(dir, "en.yaml")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "zh"), filepath.Join(dir, "zh")))
+
+	c, err := NewCatalog(Default().WithMessagesPath(dir))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"en", "zh"}, c.Languages())
+	assert.Equal(t, "Hello", c.T("en", "greeting", nil))
+	assert.Equal(t, "你好", c.T("zh", "greeting", nil))
+
+	reloaded := make(chan error, 10)
+	c.WithDebounce(20 * time.Millisecond).WithReloadHook(func(err error) { reloaded <- err })
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, c.Watch(ctx))
+
+	swap("..v2", "greeting: Hi\n", "greeting: 您好\n")
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "..v1")))
+	select {
+	case err := <-reloaded:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+	assert.Eventually(t, func() bool {
+		return c.T("en", "greeting", nil) == "Hi" && c.T("zh", "greeting", nil) == "您好"
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.ElementsMatch(t, []string{"en", "zh"}, c.Languages())
+}
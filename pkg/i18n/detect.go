@@ -0,0 +1,75 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\i18n\detect.go
+ * @Description: 按 DetectionOrder 从 HTTP 请求检测语言
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package i18n
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DetectLanguage 按 DetectionOrder 依次从请求头、查询参数、Cookie 检测语言
+//
+// 每个来源的值经 ResolutionOrder 映射后须在 SupportedLanguages 中才会采用；
+// 遇到 default 或全部来源都未命中时返回 DefaultLanguage
+func (i *I18N) DetectLanguage(r *http.Request) string {
+	for _, detection := range i.DetectionOrder {
+		var values []string
+		switch detection {
+		case DetectionHeader:
+			header := i.LanguageHeader
+			if header == "" {
+				header = "Accept-Language"
+			}
+			values = i.parseLanguageList(r.Header.Get(header))
+		case DetectionQuery:
+			if i.LanguageParam != "" {
+				values = []string{r.URL.Query().Get(i.LanguageParam)}
+			}
+		case DetectionCookie:
+			name := i.CookieName
+			if name == "" {
+				name = i.LanguageParam
+			}
+			if cookie, err := r.Cookie(name); err == nil {
+				values = []string{cookie.Value}
+			}
+		case DetectionDefault:
+			return i.DefaultLanguage
+		}
+		for _, value := range values {
+			if lang, ok := i.matchLanguage(value); ok {
+				return lang
+			}
+		}
+	}
+	return i.DefaultLanguage
+}
+
+// matchLanguage 映射语言代码并检查是否受支持
+func (i *I18N) matchLanguage(lang string) (string, bool) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return "", false
+	}
+	if mapped := i.mapLanguage(lang); i.IsSupportedLanguage(mapped) {
+		return mapped, true
+	}
+	return "", false
+}
+
+// mapLanguage 按 ResolutionOrder 依次应用映射，不检查是否受支持
+func (i *I18N) mapLanguage(lang string) string {
+	for _, mappingType := range i.ResolutionOrder {
+		lang = i.applyMapping(lang, mappingType)
+	}
+	return lang
+}
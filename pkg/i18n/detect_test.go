@@ -0,0 +1,65 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\i18n\detect_test.go
+ * @Description: 请求语言检测与复数规则测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestI18N_DetectLanguage(t *testing.T) {
+	cfg := Default().AddLegacyLanguageMapping("cn", "zh")
+	request := func(target, acceptLang, cookie string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if acceptLang != "" {
+			r.Header.Set("Accept-Language", acceptLang)
+		}
+		if cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "language", Value: cookie})
+		}
+		return r
+	}
+
+	assert.Equal(t, "zh", cfg.DetectLanguage(request("/", "fr, zh-CN;q=0.8", "")))
+	assert.Equal(t, "zh", cfg.DetectLanguage(request("/?lang=cn", "fr", "en")), "请求头未命中时使用查询参数")
+	assert.Equal(t, "zh", cfg.DetectLanguage(request("/?lang=de", "", "zh-cn")), "再使用 Cookie")
+	assert.Equal(t, "en", cfg.DetectLanguage(request("/?lang=de", "ja", "ko")))
+
+	cfg.DetectionOrder = []DetectionType{DetectionCookie, DetectionDefault, DetectionHeader}
+	assert.Equal(t, "zh", cfg.DetectLanguage(request("/", "en", "zh")))
+	assert.Equal(t, "en", cfg.DetectLanguage(request("/", "zh", "")), "default 之后的来源不再检测")
+}
+
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		lang     string
+		count    float64
+		category string
+	}{
+		{"en", 1, PluralOne}, {"en", 0, PluralOther}, {"en-US", 1.5, PluralOther},
+		{"zh", 1, PluralOther},
+		{"fr", 0, PluralOne}, {"fr", 1.7, PluralOne}, {"fr", 2, PluralOther},
+		{"ru", 1, PluralOne}, {"ru", 11, PluralMany}, {"ru", 22, PluralFew}, {"ru", 25, PluralMany}, {"ru", 1.5, PluralOther},
+		{"pl", 1, PluralOne}, {"pl", 21, PluralMany}, {"pl", 24, PluralFew},
+		{"cs", 3, PluralFew}, {"cs", 5, PluralOther}, {"cs", 0.5, PluralMany},
+		{"ar", 0, PluralZero}, {"ar", 2, PluralTwo}, {"ar", 105, PluralFew}, {"ar", 111, PluralMany}, {"ar", 100, PluralOther},
+		{"he", 2, PluralTwo},
+		{"xx", 1, PluralOne},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.category, PluralCategory(c.lang, c.count), "%s %v", c.lang, c.count)
+	}
+	assert.Equal(t, []string{PluralOne, PluralFew, PluralMany, PluralOther}, PluralCategories("ru_RU"))
+}
@@ -74,7 +74,7 @@ type I18N struct {
 	MessagesPath          string            `mapstructure:"messages-path" yaml:"messages-path" json:"messagesPath"`                              // 消息文件路径
 	CustomMessagePaths    map[string]string `mapstructure:"custom-message-paths" yaml:"custom-message-paths" json:"customMessagePaths"`          // 自定义消息文件路径映射
 	EnableFallback        bool              `mapstructure:"enable-fallback" yaml:"enable-fallback" json:"enableFallback"`                        // 是否启用回退到默认语言
-	MessageLoader         MessageLoader     `mapstructure:"-" yaml:"-" json:""`                                                                  // 自定义消息加载器
+	MessageLoader         MessageLoader     `mapstructure:"-" yaml:"-" json:"-"`                                                                 // 自定义消息加载器
 	CookieName            string            `mapstructure:"cookie-name" yaml:"cookie-name" json:"cookieName"`                                    // Cookie名称
 }
 
@@ -235,9 +235,7 @@ func (i *I18N) AddLegacyLanguageMappings(mappings map[string]string) *I18N {
 //   - 最终的标准语言代码（如 "zh", "en"）
 func (i *I18N) ResolveLanguage(lang string) string {
 	// 按照配置的顺序依次应用映射
-	for _, resolverType := range i.ResolutionOrder {
-		lang = i.applyMapping(lang, resolverType)
-	}
+	lang = i.mapLanguage(lang)
 
 	// 验证是否在支持的语言列表中
 	if i.IsSupportedLanguage(lang) {
@@ -0,0 +1,189 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\i18n\plural.go
+ * @Description: CLDR 复数规则（基数），覆盖常用语言
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package i18n
+
+import (
+	"math"
+	"strings"
+)
+
+// CLDR 复数类别
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// pluralCategories 全部复数类别，消息文件中以这些名称作为叶子键时视为复数消息
+var pluralCategories = []string{PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther}
+
+// pluralRule 复数规则，i 为整数部分，integer 表示没有小数部分（CLDR 操作数 v = 0）
+type pluralRule struct {
+	categories []string
+	choose     func(n float64, i int64, integer bool) string
+}
+
+var (
+	ruleNone = pluralRule{[]string{PluralOther}, func(float64, int64, bool) string { return PluralOther }}
+
+	// ruleOneInteger 英语等：i = 1 且 v = 0 为 one
+	ruleOneInteger = pluralRule{[]string{PluralOne, PluralOther}, func(_ float64, i int64, integer bool) string {
+		if i == 1 && integer {
+			return PluralOne
+		}
+		return PluralOther
+	}}
+
+	// ruleOneExact 西班牙语等：n = 1 为 one
+	ruleOneExact = pluralRule{[]string{PluralOne, PluralOther}, func(n float64, _ int64, _ bool) string {
+		if n == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	}}
+
+	// ruleZeroOne 法语、葡萄牙语：i = 0,1 为 one
+	ruleZeroOne = pluralRule{[]string{PluralOne, PluralOther}, func(_ float64, i int64, _ bool) string {
+		if i == 0 || i == 1 {
+			return PluralOne
+		}
+		return PluralOther
+	}}
+
+	// ruleEastSlavic 俄语、乌克兰语、白俄罗斯语
+	ruleEastSlavic = pluralRule{[]string{PluralOne, PluralFew, PluralMany, PluralOther}, func(_ float64, i int64, integer bool) string {
+		if !integer {
+			return PluralOther
+		}
+		mod10, mod100 := i%10, i%100
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	}}
+
+	// rulePolish 波兰语
+	rulePolish = pluralRule{[]string{PluralOne, PluralFew, PluralMany, PluralOther}, func(_ float64, i int64, integer bool) string {
+		if !integer {
+			return PluralOther
+		}
+		mod10, mod100 := i%10, i%100
+		switch {
+		case i == 1:
+			return PluralOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+	}}
+
+	// ruleWestSlavic 捷克语、斯洛伐克语
+	ruleWestSlavic = pluralRule{[]string{PluralOne, PluralFew, PluralMany, PluralOther}, func(_ float64, i int64, integer bool) string {
+		switch {
+		case !integer:
+			return PluralMany
+		case i == 1:
+			return PluralOne
+		case i >= 2 && i <= 4:
+			return PluralFew
+		default:
+			return PluralOther
+		}
+	}}
+
+	// ruleArabic 阿拉伯语
+	ruleArabic = pluralRule{pluralCategories, func(n float64, i int64, integer bool) string {
+		if !integer {
+			return PluralOther
+		}
+		mod100 := i % 100
+		switch {
+		case n == 0:
+			return PluralZero
+		case n == 1:
+			return PluralOne
+		case n == 2:
+			return PluralTwo
+		case mod100 >= 3 && mod100 <= 10:
+			return PluralFew
+		case mod100 >= 11:
+			return PluralMany
+		default:
+			return PluralOther
+		}
+	}}
+
+	// ruleHebrew 希伯来语
+	ruleHebrew = pluralRule{[]string{PluralOne, PluralTwo, PluralOther}, func(_ float64, i int64, integer bool) string {
+		switch {
+		case i == 1 && integer:
+			return PluralOne
+		case i == 2 && integer:
+			return PluralTwo
+		default:
+			return PluralOther
+		}
+	}}
+)
+
+// pluralRules 按基础语言代码索引的复数规则，未列出的语言使用英语规则
+var pluralRules = map[string]pluralRule{
+	"zh": ruleNone, "ja": ruleNone, "ko": ruleNone, "vi": ruleNone, "th": ruleNone,
+	"id": ruleNone, "ms": ruleNone, "lo": ruleNone, "my": ruleNone, "km": ruleNone,
+	"en": ruleOneInteger, "de": ruleOneInteger, "nl": ruleOneInteger, "sv": ruleOneInteger,
+	"da": ruleOneInteger, "nb": ruleOneInteger, "no": ruleOneInteger, "fi": ruleOneInteger,
+	"et": ruleOneInteger, "it": ruleOneInteger, "ca": ruleOneInteger,
+	"es": ruleOneExact, "el": ruleOneExact, "hu": ruleOneExact, "tr": ruleOneExact, "bg": ruleOneExact,
+	"fr": ruleZeroOne, "pt": ruleZeroOne,
+	"ru": ruleEastSlavic, "uk": ruleEastSlavic, "be": ruleEastSlavic,
+	"pl": rulePolish,
+	"cs": ruleWestSlavic, "sk": ruleWestSlavic,
+	"ar": ruleArabic,
+	"he": ruleHebrew,
+}
+
+// ruleFor 返回语言对应的复数规则
+func ruleFor(lang string) pluralRule {
+	if rule, ok := pluralRules[baseLanguage(lang)]; ok {
+		return rule
+	}
+	return ruleOneInteger
+}
+
+// PluralCategory 返回数量在指定语言下的 CLDR 复数类别
+func PluralCategory(lang string, count float64) string {
+	n := math.Abs(count)
+	i := int64(n)
+	return ruleFor(lang).choose(n, i, n == math.Trunc(n))
+}
+
+// PluralCategories 返回语言使用的全部复数类别
+func PluralCategories(lang string) []string {
+	return append([]string(nil), ruleFor(lang).categories...)
+}
+
+// baseLanguage 返回基础语言代码，如 zh-cn、zh_TW -> zh
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(lang)
+	if idx := strings.IndexAny(lang, "-_"); idx > 0 {
+		return lang[:idx]
+	}
+	return lang
+}
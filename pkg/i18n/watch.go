@@ -0,0 +1,168 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\i18n\watch.go
+ * @Description: 消息文件热更新，基于 fsnotify 监控目录并防抖重新加载
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kamalyes/go-toolbox/pkg/syncx"
+)
+
+// Watch 监控消息目录及其语言子目录、CustomMessagePaths，文件变化时防抖后重新加载
+//
+// 与配置热更新一致，监控的是目录而不是文件本身，以捕获编辑器"写临时文件 + 重命名"的保存方式；
+// 运行期间新建的语言目录会自动加入监控。挂载 Kubernetes ConfigMap 时只有 ..data 被原子替换，
+// 因此其它文件事件会重新解析消息路径的符号链接，目标变化时重新加入监控并加载。ctx 结束时停止监控
+func (c *Catalog) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create message watcher: %w", err)
+	}
+	for _, dir := range c.watchDirs() {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch message dir %s: %w", dir, err)
+		}
+	}
+	targets := c.linkTargets()
+
+	c.mu.RLock()
+	debounce := c.debounce
+	c.mu.RUnlock()
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	schedule := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(debounce, func() { c.notify(c.Reload()) })
+	}
+
+	syncx.NewEventLoop(ctx).
+		OnChannel(watcher.Events, func(event fsnotify.Event) {
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				return
+			}
+			name := filepath.Base(event.Name)
+			if !strings.HasPrefix(name, ".") {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if event.Has(fsnotify.Create) {
+						watcher.Add(event.Name)
+						schedule()
+					}
+					return
+				}
+				if _, ok := messageFileLanguage(name); ok {
+					schedule()
+					return
+				}
+			}
+			// 非消息文件事件（如 ..data 被替换）：符号链接目标变化时重新监控并加载
+			if current := c.linkTargets(); !maps.Equal(current, targets) {
+				targets = current
+				for _, dir := range c.watchDirs() {
+					watcher.Add(dir)
+				}
+				schedule()
+			}
+		}).
+		OnChannel(watcher.Errors, func(err error) {
+			c.notify(fmt.Errorf("message watcher: %w", err))
+		}).
+		OnShutdown(func() {
+			mu.Lock()
+			if timer != nil {
+				timer.Stop()
+			}
+			mu.Unlock()
+			watcher.Close()
+		}).
+		RunAsync()
+	return nil
+}
+
+// watchDirs 返回需要监控的目录：消息目录、其子目录与自定义消息路径（文件取所在目录）
+func (c *Catalog) watchDirs() []string {
+	var dirs []string
+	add := func(dir string) {
+		if abs, err := filepath.Abs(dir); err == nil && !slices.Contains(dirs, abs) {
+			dirs = append(dirs, abs)
+		}
+	}
+
+	if path := c.config.MessagesPath; path != "" {
+		if entries, err := readMessageDir(path); err == nil {
+			add(path)
+			for _, entry := range entries {
+				if entry.isDir {
+					add(filepath.Join(path, entry.name))
+				}
+			}
+		}
+	}
+	for _, path := range c.config.CustomMessagePaths {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			continue
+		case info.IsDir():
+			add(path)
+		default:
+			add(filepath.Dir(path))
+		}
+	}
+	return dirs
+}
+
+// linkTargets 解析消息目录项与自定义消息路径的真实路径，用于发现符号链接目标的替换
+func (c *Catalog) linkTargets() map[string]string {
+	targets := map[string]string{}
+	resolve := func(path string) {
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			targets[path] = real
+		}
+	}
+	if path := c.config.MessagesPath; path != "" {
+		resolve(path)
+		if entries, err := readMessageDir(path); err == nil {
+			for _, entry := range entries {
+				resolve(filepath.Join(path, entry.name))
+			}
+		}
+	}
+	for _, path := range c.config.CustomMessagePaths {
+		resolve(path)
+	}
+	return targets
+}
+
+// notify 调用热更新回调
+func (c *Catalog) notify(err error) {
+	c.mu.RLock()
+	fn := c.onReload
+	c.mu.RUnlock()
+	if fn != nil {
+		fn(err)
+	}
+}
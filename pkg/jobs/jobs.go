@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/kamalyes/go-config/internal"
+	"github.com/kamalyes/go-config/pkg/breaker"
 	"github.com/kamalyes/go-toolbox/pkg/syncx"
 )
 
@@ -206,6 +207,17 @@ func (b *BreakerCfg) Validate(taskName string) error {
 	return nil
 }
 
+// Settings 构建任务断路器运行参数，按连续失败次数熔断
+func (b *BreakerCfg) Settings(taskName string) breaker.Settings {
+	return breaker.Settings{
+		Name:             taskName,
+		FailureThreshold: b.MaxFailures,
+		SuccessThreshold: b.HalfOpenSuccesses,
+		Timeout:          time.Duration(b.ResetTimeout) * time.Second,
+		Consecutive:      true,
+	}
+}
+
 // GetTimeZoneLocation 获取时区Location
 func (c *Jobs) GetTimeZoneLocation() (*time.Location, error) {
 	return time.LoadLocation(c.TimeZone)
@@ -12,8 +12,11 @@
 package jobs
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/kamalyes/go-config/pkg/breaker"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -534,3 +537,21 @@ func TestJobs_GetSet(t *testing.T) {
 	assert.Equal(t, newJobs.Enabled, jobs.Enabled)
 	assert.Equal(t, newJobs.TimeZone, jobs.TimeZone)
 }
+
+func TestBreakerCfg_Settings(t *testing.T) {
+	cfg, ok := Default().GetTaskConfig("data-sync")
+	assert.True(t, ok)
+
+	s := cfg.Breaker.Settings("data-sync")
+	assert.Equal(t, "data-sync", s.Name)
+	assert.Equal(t, 3, s.FailureThreshold)
+	assert.Equal(t, 2, s.SuccessThreshold)
+	assert.Equal(t, time.Minute, s.Timeout)
+	assert.True(t, s.Consecutive)
+
+	b := breaker.NewBreaker(s)
+	for i := 0; i < 3; i++ {
+		b.Execute(func() error { return errors.New("sync failed") })
+	}
+	assert.Equal(t, breaker.StateOpen, b.State())
+}
@@ -0,0 +1,128 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\jwt\claims.go
+ * @Description: JWT 声明
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Audience 接收者，JSON 中可以是字符串或字符串数组
+type Audience []string
+
+// MarshalJSON 单个接收者编码为字符串
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON 兼容字符串与字符串数组
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Claims JWT 声明，时间字段为 Unix 秒
+type Claims struct {
+	Issuer           string         `json:"iss,omitempty"`
+	Subject          string         `json:"sub,omitempty"`
+	Audience         Audience       `json:"aud,omitempty"`
+	ExpiresAt        int64          `json:"exp,omitempty"`
+	NotBefore        int64          `json:"nbf,omitempty"`
+	IssuedAt         int64          `json:"iat,omitempty"`
+	ID               string         `json:"jti,omitempty"`
+	OriginalIssuedAt int64          `json:"orig_iat,omitempty"` // 首次签发时间，刷新时保持不变
+	Extra            map[string]any `json:"-"`                  // 自定义声明，与标准声明同级编码
+}
+
+// registeredClaims 标准声明名称
+var registeredClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti", "orig_iat"}
+
+// claimsAlias 避免 MarshalJSON 递归
+type claimsAlias Claims
+
+// MarshalJSON 将 Extra 展开到顶层，标准声明优先
+func (c Claims) MarshalJSON() ([]byte, error) {
+	std, err := json.Marshal(claimsAlias(c))
+	if err != nil || len(c.Extra) == 0 {
+		return std, err
+	}
+	merged := make(map[string]any, len(c.Extra)+len(registeredClaims))
+	for k, v := range c.Extra {
+		if !slices.Contains(registeredClaims, k) {
+			merged[k] = v
+		}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(std, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON 解析标准声明，其余字段放入 Extra
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	var alias claimsAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	var extra map[string]any
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return err
+	}
+	for _, k := range registeredClaims {
+		delete(extra, k)
+	}
+	*c = Claims(alias)
+	if len(extra) > 0 {
+		c.Extra = extra
+	}
+	return nil
+}
+
+// Get 读取自定义声明
+func (c *Claims) Get(key string) (any, bool) {
+	v, ok := c.Extra[key]
+	return v, ok
+}
+
+// Set 设置自定义声明，支持链式调用
+func (c *Claims) Set(key string, value any) *Claims {
+	if c.Extra == nil {
+		c.Extra = make(map[string]any)
+	}
+	c.Extra[key] = value
+	return c
+}
+
+// clone 返回副本
+func (c *Claims) clone() *Claims {
+	cloned := *c
+	cloned.Audience = slices.Clone(c.Audience)
+	cloned.Extra = maps.Clone(c.Extra)
+	return &cloned
+}
@@ -0,0 +1,166 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\jwt\keys.go
+ * @Description: JWT 签名密钥：HS256、RS256、EdDSA
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 支持的签名算法
+const (
+	AlgorithmHS256 = "HS256" // HMAC-SHA256，SigningKey 为共享密钥
+	AlgorithmRS256 = "RS256" // RSA PKCS#1 v1.5 + SHA256，SigningKey 为 PEM 私钥或公钥
+	AlgorithmEdDSA = "EdDSA" // Ed25519，SigningKey 为 PEM 私钥或公钥
+)
+
+// Key 带 kid 的签名密钥
+//
+// 仅有公钥的 RS256/EdDSA 密钥只能用于验签
+type Key struct {
+	ID        string
+	Algorithm string
+	secret    []byte
+	private   crypto.Signer
+	public    crypto.PublicKey
+	retireAt  time.Time // 轮换后停止验签的时间，零值表示不限
+}
+
+// NewKey 创建签名密钥，id 为空时根据算法与密钥内容生成
+//
+// HS256 的 material 为共享密钥；RS256/EdDSA 为 PEM 编码的私钥（PKCS#8/PKCS#1）或公钥（PKIX/PKCS#1）
+func NewKey(id, algorithm string, material []byte) (*Key, error) {
+	alg, err := normalizeAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if len(material) == 0 {
+		return nil, fmt.Errorf("signing key is empty")
+	}
+
+	k := &Key{ID: id, Algorithm: alg}
+	if alg == AlgorithmHS256 {
+		k.secret = append([]byte(nil), material...)
+	} else if err := k.parsePEM(material); err != nil {
+		return nil, err
+	}
+	if k.ID == "" {
+		sum := sha256.Sum256(append([]byte(alg+":"), material...))
+		k.ID = hex.EncodeToString(sum[:8])
+	}
+	return k, nil
+}
+
+// CanSign 是否可用于签发
+func (k *Key) CanSign() bool {
+	return k.secret != nil || k.private != nil
+}
+
+// RetireAt 返回密钥停止验签的时间，零值表示仍在使用
+func (k *Key) RetireAt() time.Time {
+	return k.retireAt
+}
+
+// parsePEM 解析 PEM 私钥或公钥并检查与算法匹配
+func (k *Key) parsePEM(material []byte) error {
+	block, _ := pem.Decode(material)
+	if block == nil {
+		return fmt.Errorf("signing key for %s must be PEM encoded", k.Algorithm)
+	}
+
+	var parsed any
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		parsed = key
+	} else if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		parsed = key
+	} else if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		parsed = key
+	} else if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		parsed = key
+	} else {
+		return fmt.Errorf("failed to parse %s key: unsupported PEM block %q", k.Algorithm, block.Type)
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.private, k.public = key, &key.PublicKey
+	case *rsa.PublicKey:
+		k.public = key
+	case ed25519.PrivateKey:
+		k.private, k.public = key, key.Public()
+	case ed25519.PublicKey:
+		k.public = key
+	default:
+		return fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	_, isRSA := k.public.(*rsa.PublicKey)
+	if (k.Algorithm == AlgorithmRS256) != isRSA {
+		return fmt.Errorf("key type %T does not match algorithm %s", k.public, k.Algorithm)
+	}
+	return nil
+}
+
+// sign 对签名输入计算签名
+func (k *Key) sign(input []byte) ([]byte, error) {
+	switch {
+	case k.Algorithm == AlgorithmHS256 && k.secret != nil:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case k.private == nil:
+		return nil, fmt.Errorf("key %s has no private key", k.ID)
+	case k.Algorithm == AlgorithmRS256:
+		digest := sha256.Sum256(input)
+		return rsa.SignPKCS1v15(nil, k.private.(*rsa.PrivateKey), crypto.SHA256, digest[:])
+	default:
+		return ed25519.Sign(k.private.(ed25519.PrivateKey), input), nil
+	}
+}
+
+// verify 校验签名
+func (k *Key) verify(input, signature []byte) bool {
+	switch k.Algorithm {
+	case AlgorithmHS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(input)
+		return hmac.Equal(signature, mac.Sum(nil))
+	case AlgorithmRS256:
+		digest := sha256.Sum256(input)
+		return rsa.VerifyPKCS1v15(k.public.(*rsa.PublicKey), crypto.SHA256, digest[:], signature) == nil
+	default:
+		return ed25519.Verify(k.public.(ed25519.PublicKey), input, signature)
+	}
+}
+
+// normalizeAlgorithm 规范化算法名称（忽略大小写，Ed25519 视为 EdDSA），空值默认为 HS256
+func normalizeAlgorithm(algorithm string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(algorithm)) {
+	case "", AlgorithmHS256:
+		return AlgorithmHS256, nil
+	case AlgorithmRS256:
+		return AlgorithmRS256, nil
+	case "EDDSA", "ED25519":
+		return AlgorithmEdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported jwt algorithm: %s", algorithm)
+	}
+}
@@ -0,0 +1,299 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\jwt\manager.go
+ * @Description: JWT 签发、解析、刷新与密钥轮换
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 令牌校验失败原因，可用 errors.Is 判断
+var (
+	ErrTokenMalformed     = errors.New("token is malformed")
+	ErrUnknownKey         = errors.New("token signing key is unknown or retired")
+	ErrAlgorithmMismatch  = errors.New("token algorithm does not match signing key")
+	ErrSignatureInvalid   = errors.New("token signature is invalid")
+	ErrTokenExpired       = errors.New("token is expired")
+	ErrTokenNotValidYet   = errors.New("token is not valid yet")
+	ErrInvalidIssuer      = errors.New("token issuer is invalid")
+	ErrInvalidAudience    = errors.New("token audience is invalid")
+	ErrRefreshDisabled    = errors.New("token refresh is disabled")
+	ErrRefreshNotDue      = errors.New("token is not within refresh buffer time")
+	ErrRefreshLifeExpired = errors.New("token refresh life is exceeded")
+)
+
+// header JOSE 头
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// Manager 令牌管理器
+//
+// 签发使用当前密钥并在头部写入 kid；解析按 kid 查找密钥，轮换下来的旧密钥在宽限期内仍可验签
+type Manager struct {
+	mu     sync.RWMutex
+	cfg    *JWT
+	active *Key
+	keys   map[string]*Key
+	now    func() time.Time
+}
+
+// NewManager 根据配置创建令牌管理器，SigningKey 与 Algorithm 作为初始签名密钥
+func NewManager(cfg *JWT) (*Manager, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("jwt config is nil")
+	}
+	cfg = cfg.Clone().(*JWT)
+	if cfg.ExpiresTime <= 0 {
+		return nil, fmt.Errorf("expires time must be positive")
+	}
+	key, err := NewKey("", cfg.Algorithm, []byte(cfg.SigningKey))
+	if err != nil {
+		return nil, err
+	}
+	if !key.CanSign() {
+		return nil, fmt.Errorf("signing key for %s must contain a private key", key.Algorithm)
+	}
+	return &Manager{
+		cfg:    cfg,
+		active: key,
+		keys:   map[string]*Key{key.ID: key},
+		now:    time.Now,
+	}, nil
+}
+
+// WithClock 设置时间源，便于测试
+func (m *Manager) WithClock(now func() time.Time) *Manager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+	return m
+}
+
+// Rotate 切换签名密钥，原密钥在 grace 宽限期内仍可验签，grace <= 0 时立即失效
+//
+// 已超过宽限期的旧密钥会被清理
+func (m *Manager) Rotate(key *Key, grace time.Duration) error {
+	if key == nil || !key.CanSign() {
+		return fmt.Errorf("rotated key must be able to sign")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.keys[key.ID]; exists && key.ID != m.active.ID {
+		return fmt.Errorf("key id %s already exists", key.ID)
+	}
+
+	now := m.now()
+	if grace > 0 {
+		m.active.retireAt = now.Add(grace)
+	} else {
+		delete(m.keys, m.active.ID)
+	}
+	for id, k := range m.keys {
+		if !k.retireAt.IsZero() && !now.Before(k.retireAt) {
+			delete(m.keys, id)
+		}
+	}
+	key.retireAt = time.Time{}
+	m.active = key
+	m.keys[key.ID] = key
+	return nil
+}
+
+// AddVerificationKey 添加仅用于验签的密钥（如其他服务签发令牌的公钥）
+func (m *Manager) AddVerificationKey(key *Key) error {
+	if key == nil {
+		return fmt.Errorf("verification key is nil")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.keys[key.ID]; exists {
+		return fmt.Errorf("key id %s already exists", key.ID)
+	}
+	m.keys[key.ID] = key
+	return nil
+}
+
+// ActiveKeyID 返回当前签名密钥的 kid
+func (m *Manager) ActiveKeyID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.active.ID
+}
+
+// Issue 签发令牌
+//
+// 未设置的 iss/sub/aud 使用配置值，CustomClaims 作为默认自定义声明；
+// iat/nbf 为当前时间，exp 为当前时间加 ExpiresTime，jti 为空时随机生成
+func (m *Manager) Issue(claims *Claims) (string, error) {
+	m.mu.RLock()
+	key, now := m.active, m.now()
+	m.mu.RUnlock()
+
+	c := &Claims{}
+	if claims != nil {
+		c = claims.clone()
+	}
+	if c.Issuer == "" {
+		c.Issuer = m.cfg.Issuer
+	}
+	if c.Subject == "" {
+		c.Subject = m.cfg.Subject
+	}
+	if len(c.Audience) == 0 && m.cfg.Audience != "" {
+		c.Audience = Audience{m.cfg.Audience}
+	}
+	if len(m.cfg.CustomClaims) > 0 {
+		extra := maps.Clone(m.cfg.CustomClaims)
+		maps.Copy(extra, c.Extra)
+		c.Extra = extra
+	}
+	c.IssuedAt = now.Unix()
+	c.NotBefore = c.IssuedAt
+	c.ExpiresAt = now.Add(time.Duration(m.cfg.ExpiresTime) * time.Second).Unix()
+	if c.OriginalIssuedAt == 0 {
+		c.OriginalIssuedAt = c.IssuedAt
+	}
+	if c.ID == "" {
+		c.ID = randomID()
+	}
+	return sign(key, c)
+}
+
+// Parse 校验签名与 exp/nbf/iss/aud 并返回声明
+//
+// 令牌仅因过期失败时同时返回声明与 ErrTokenExpired
+func (m *Manager) Parse(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+
+	m.mu.RLock()
+	key, now := m.lookup(h.KeyID), m.now()
+	m.mu.RUnlock()
+	if key == nil || (!key.retireAt.IsZero() && !now.Before(key.retireAt)) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, h.KeyID)
+	}
+	if h.Algorithm != key.Algorithm {
+		return nil, fmt.Errorf("%w: %s", ErrAlgorithmMismatch, h.Algorithm)
+	}
+	if !key.verify([]byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrSignatureInvalid
+	}
+
+	claims := &Claims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	if m.cfg.Issuer != "" && claims.Issuer != m.cfg.Issuer {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidIssuer, claims.Issuer)
+	}
+	if m.cfg.Audience != "" && !slices.Contains(claims.Audience, m.cfg.Audience) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAudience, []string(claims.Audience))
+	}
+	unix := now.Unix()
+	if claims.NotBefore != 0 && unix < claims.NotBefore {
+		return nil, ErrTokenNotValidYet
+	}
+	if claims.ExpiresAt != 0 && unix >= claims.ExpiresAt {
+		return claims, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+// Refresh 在令牌剩余有效期不超过 BufferTime 时使用当前密钥重新签发
+//
+// 需开启 EnableRefresh；未进入缓冲期返回 ErrRefreshNotDue；
+// RefreshTokenLife 大于 0 时，自首次签发起超过该时长不再允许刷新
+func (m *Manager) Refresh(token string) (string, error) {
+	if !m.cfg.EnableRefresh {
+		return "", ErrRefreshDisabled
+	}
+	claims, err := m.Parse(token)
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.RLock()
+	unix := m.now().Unix()
+	m.mu.RUnlock()
+	if claims.ExpiresAt-unix > m.cfg.BufferTime {
+		return "", ErrRefreshNotDue
+	}
+	if life := m.cfg.RefreshTokenLife; life > 0 && unix-claims.OriginalIssuedAt >= life {
+		return "", ErrRefreshLifeExpired
+	}
+	claims.ID = ""
+	return m.Issue(claims)
+}
+
+// lookup 按 kid 查找密钥，kid 为空时使用当前密钥
+func (m *Manager) lookup(kid string) *Key {
+	if kid == "" {
+		return m.active
+	}
+	return m.keys[kid]
+}
+
+// sign 生成紧凑格式的 JWS
+func sign(key *Key, claims *Claims) (string, error) {
+	h, err := json.Marshal(header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode claims: %w", err)
+	}
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := key.sign([]byte(input))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// decodeSegment 解码 Base64URL 编码的 JSON 段
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// randomID 生成 16 字节随机数的十六进制字符串
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
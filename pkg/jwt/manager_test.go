@@ -0,0 +1,228 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\jwt\manager_test.go
+ * @Description: JWT 令牌管理器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock 可手动推进的时间源
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestManager(t *testing.T, cfg *JWT) (*Manager, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	return m.WithClock(clock.Now), clock
+}
+
+func pemPrivateKey(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestManager_IssueAndParse(t *testing.T) {
+	cfg := Default().WithCustomClaims(map[string]interface{}{"tenant": "t1", "role": "guest"})
+	m, _ := newTestManager(t, cfg)
+
+	token, err := m.Issue((&Claims{Subject: "user-1"}).Set("role", "admin"))
+	require.NoError(t, err)
+
+	claims, err := m.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "go-config", claims.Issuer)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, Audience{"go-config-audience"}, claims.Audience)
+	assert.Equal(t, int64(1_700_000_000), claims.IssuedAt)
+	assert.Equal(t, claims.IssuedAt+cfg.ExpiresTime, claims.ExpiresAt)
+	assert.NotEmpty(t, claims.ID)
+	assert.Equal(t, "admin", claims.Extra["role"])
+	assert.Equal(t, "t1", claims.Extra["tenant"])
+
+	var h header
+	require.NoError(t, decodeSegment(strings.Split(token, ".")[0], &h))
+	assert.Equal(t, header{Algorithm: AlgorithmHS256, Type: "JWT", KeyID: m.ActiveKeyID()}, h)
+}
+
+func TestManager_Algorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		algorithm string
+		key       string
+	}{
+		{"HS256", "secret"},
+		{"RS256", string(pemPrivateKey(t, rsaKey))},
+		{"EdDSA", string(pemPrivateKey(t, edKey))},
+		{"ed25519", string(pemPrivateKey(t, edKey))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			m, _ := newTestManager(t, Default().WithAlgorithm(tt.algorithm).WithSigningKey(tt.key))
+			token, err := m.Issue(nil)
+			require.NoError(t, err)
+			_, err = m.Parse(token)
+			assert.NoError(t, err)
+		})
+	}
+
+	_, err = NewManager(Default().WithAlgorithm("RS256").WithSigningKey(string(pemPrivateKey(t, edKey))))
+	assert.Error(t, err)
+	_, err = NewManager(Default().WithAlgorithm("ES256"))
+	assert.Error(t, err)
+}
+
+func TestManager_ParseRejects(t *testing.T) {
+	m, clock := newTestManager(t, Default())
+	token, err := m.Issue(nil)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	_, err = m.Parse("a.b")
+	assert.ErrorIs(t, err, ErrTokenMalformed)
+
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"root"}`)) + "." + parts[2]
+	_, err = m.Parse(tampered)
+	assert.ErrorIs(t, err, ErrSignatureInvalid)
+
+	none, _ := json.Marshal(header{Algorithm: "none", KeyID: m.ActiveKeyID()})
+	_, err = m.Parse(base64.RawURLEncoding.EncodeToString(none) + "." + parts[1] + ".")
+	assert.ErrorIs(t, err, ErrAlgorithmMismatch)
+
+	other, _ := newTestManager(t, Default().WithSigningKey("other-secret"))
+	foreign, err := other.Issue(nil)
+	require.NoError(t, err)
+	_, err = m.Parse(foreign)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	otherIssuer, err := m.Issue(&Claims{Issuer: "other"})
+	require.NoError(t, err)
+	_, err = m.Parse(otherIssuer)
+	assert.ErrorIs(t, err, ErrInvalidIssuer)
+
+	clock.Advance(7 * 24 * time.Hour)
+	claims, err := m.Parse(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+	assert.NotNil(t, claims)
+}
+
+func TestManager_Refresh(t *testing.T) {
+	cfg := Default().WithExpiresTime(3600).WithBufferTime(600).WithRefreshTokenLife(5000)
+	m, clock := newTestManager(t, cfg)
+	token, err := m.Issue(&Claims{Subject: "user-1"})
+	require.NoError(t, err)
+	original, _ := m.Parse(token)
+
+	_, err = m.Refresh(token)
+	assert.ErrorIs(t, err, ErrRefreshNotDue)
+
+	clock.Advance(3000 * time.Second)
+	refreshed, err := m.Refresh(token)
+	require.NoError(t, err)
+	claims, err := m.Parse(refreshed)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+	assert.Equal(t, original.OriginalIssuedAt, claims.OriginalIssuedAt)
+	assert.Equal(t, clock.Now().Unix()+3600, claims.ExpiresAt)
+	assert.NotEqual(t, original.ID, claims.ID)
+
+	clock.Advance(3100 * time.Second)
+	_, err = m.Refresh(refreshed)
+	assert.ErrorIs(t, err, ErrRefreshLifeExpired)
+
+	disabled, _ := newTestManager(t, Default().WithEnableRefresh(false))
+	_, err = disabled.Refresh(token)
+	assert.ErrorIs(t, err, ErrRefreshDisabled)
+}
+
+func TestManager_RotateGracePeriod(t *testing.T) {
+	m, clock := newTestManager(t, Default())
+	oldToken, err := m.Issue(nil)
+	require.NoError(t, err)
+	oldID := m.ActiveKeyID()
+
+	next, err := NewKey("2026-10", AlgorithmHS256, []byte("next-secret"))
+	require.NoError(t, err)
+	require.NoError(t, m.Rotate(next, time.Hour))
+	assert.Equal(t, "2026-10", m.ActiveKeyID())
+
+	newToken, err := m.Issue(nil)
+	require.NoError(t, err)
+	_, err = m.Parse(newToken)
+	assert.NoError(t, err)
+	_, err = m.Parse(oldToken)
+	assert.NoError(t, err, "旧密钥在宽限期内仍可验签")
+
+	clock.Advance(time.Hour)
+	_, err = m.Parse(oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	latest, err := NewKey("", AlgorithmHS256, []byte("latest-secret"))
+	require.NoError(t, err)
+	require.NoError(t, m.Rotate(latest, 0))
+	_, err = m.Parse(newToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.NotContains(t, m.keys, oldID)
+}
+
+func TestManager_VerificationOnlyKey(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	issuer, _ := newTestManager(t, Default().WithAlgorithm(AlgorithmEdDSA).WithSigningKey(string(pemPrivateKey(t, edKey))))
+	token, err := issuer.Issue(nil)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(edKey.Public())
+	require.NoError(t, err)
+	public, err := NewKey(issuer.ActiveKeyID(), AlgorithmEdDSA, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.False(t, public.CanSign())
+
+	m, _ := newTestManager(t, Default())
+	require.NoError(t, m.AddVerificationKey(public))
+	_, err = m.Parse(token)
+	assert.NoError(t, err)
+	assert.Error(t, m.Rotate(public, time.Hour))
+}
+
+func TestClaims_JSON(t *testing.T) {
+	data, err := json.Marshal((&Claims{Subject: "u", Audience: Audience{"a"}}).Set("sub", "ignored").Set("role", "admin"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"u","aud":"a","role":"admin"}`, string(data))
+
+	var c Claims
+	require.NoError(t, json.Unmarshal([]byte(`{"aud":["a","b"],"exp":10,"role":"admin"}`), &c))
+	assert.Equal(t, Audience{"a", "b"}, c.Audience)
+	assert.Equal(t, int64(10), c.ExpiresAt)
+	assert.Equal(t, map[string]any{"role": "admin"}, c.Extra)
+}
@@ -0,0 +1,188 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\logging\build.go
+ * @Description: 根据日志配置构建 go-logger 实例，支持运行时调整级别与格式
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kamalyes/go-logger"
+)
+
+// Handle 由 Build 创建的日志句柄，持有输出器并支持热更新级别与格式
+//
+// 级别变化直接作用于当前 Logger；格式等展示项变化时会基于同一组输出器创建新的 Logger，
+// 因此调用方应通过 Logger() 获取实例，而不是长期缓存
+type Handle struct {
+	mu      sync.Mutex
+	config  *Logging
+	writers []logger.IWriter
+	current atomic.Pointer[logger.Logger]
+}
+
+// Build 根据日志配置构建 Logger
+//
+// 始终输出到控制台（Output 为 stderr 时输出到标准错误，否则为标准输出）；
+// Output 为 file 或 rotate 时同时写入 FilePath，rotate 按 MaxSize/MaxBackups 轮转。
+// 所有输出都会按 SensitiveKeys 脱敏
+func Build(cfg *Logging) (*Handle, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("logging config is nil")
+	}
+	cfg = cfg.Clone().(*Logging)
+	level, err := parseOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	console := os.Stdout
+	if cfg.Output == logger.OutputStderr {
+		console = os.Stderr
+	}
+	writers := []logger.IWriter{logger.NewConsoleWriter(logger.WithConsoleOutput(console))}
+	if cfg.Output == logger.OutputFile || cfg.Output == logger.OutputRotate {
+		fileWriter, err := logger.CreateWriter(cfg.toWriterConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create log writer: %w", err)
+		}
+		writers = append(writers, fileWriter)
+	}
+
+	masker := NewMasker(cfg.SensitiveKeys, cfg.SensitiveMask)
+	for i, w := range writers {
+		writers[i] = newMaskWriter(w, masker)
+	}
+
+	h := &Handle{config: cfg, writers: writers}
+	h.current.Store(h.newLogger(cfg, level))
+	return h, nil
+}
+
+// Logger 返回当前生效的 Logger
+func (h *Handle) Logger() *logger.Logger {
+	return h.current.Load()
+}
+
+// Config 返回当前生效配置的副本
+func (h *Handle) Config() *Logging {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.config.Clone().(*Logging)
+}
+
+// Reconfigure 热更新 Level、Format 及前缀、颜色、调用者、时间格式，输出器保持不变
+//
+// 仅级别变化时沿用当前 Logger；其余展示项变化时替换为新的 Logger。
+// 输出目标、文件路径、轮转与脱敏设置需重新 Build 才能生效；配置无效时保留原设置并返回错误
+func (h *Handle) Reconfigure(cfg *Logging) error {
+	if cfg == nil {
+		return fmt.Errorf("logging config is nil")
+	}
+	level, err := parseOptions(cfg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	next := h.config.Clone().(*Logging)
+	next.Level = cfg.Level
+	next.Format = cfg.Format
+	next.Prefix = cfg.Prefix
+	next.Colorful = cfg.Colorful
+	next.ShowCaller = cfg.ShowCaller
+	next.TimeFormat = cfg.TimeFormat
+
+	prev := h.config
+	if next.Format == prev.Format && next.Prefix == prev.Prefix && next.Colorful == prev.Colorful && next.TimeFormat == prev.TimeFormat {
+		current := h.current.Load()
+		current.SetLevel(level)
+		current.SetShowCaller(next.ShowCaller)
+	} else {
+		h.current.Store(h.newLogger(next, level))
+	}
+	h.config = next
+	return nil
+}
+
+// Flush 刷新所有输出器的缓冲
+func (h *Handle) Flush() error {
+	var errs []error
+	for _, w := range h.writers {
+		if err := w.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 刷新并关闭文件输出器，控制台输出器不关闭
+func (h *Handle) Close() error {
+	errs := []error{h.Flush()}
+	for _, w := range h.writers[1:] {
+		if err := w.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newLogger 基于共享输出器创建 Logger
+func (h *Handle) newLogger(cfg *Logging, level logger.LogLevel) *logger.Logger {
+	return logger.NewLogger().
+		WithOutput(fanout(h.writers)).
+		WithLevel(level).
+		WithPrefix(cfg.Prefix).
+		WithShowCaller(cfg.ShowCaller).
+		WithColorful(cfg.Colorful).
+		WithTimeFormat(cfg.TimeFormat).
+		WithFormat(cfg.Format)
+}
+
+// fanout 依次写入所有输出器，单个输出器失败不影响其余输出器
+//
+// 不使用 logger.NewMultiWriter：它会跳过尚未打开文件（IsHealthy 为 false）的文件输出器
+type fanout []logger.IWriter
+
+// Write 实现 io.Writer，返回最后一个错误
+func (f fanout) Write(p []byte) (int, error) {
+	if len(f) == 1 {
+		return f[0].Write(p)
+	}
+	var lastErr error
+	for _, w := range f {
+		if _, err := w.Write(p); err != nil {
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return 0, lastErr
+	}
+	return len(p), nil
+}
+
+// parseOptions 校验日志级别与格式
+func parseOptions(cfg *Logging) (logger.LogLevel, error) {
+	level, err := logger.ParseLevel(cfg.Level)
+	if err != nil {
+		return level, err
+	}
+	switch cfg.Format {
+	case "", logger.FormatJSON, logger.FormatText, logger.FormatXML, logger.FormatCSV:
+	default:
+		return level, fmt.Errorf("invalid log format: %s", cfg.Format)
+	}
+	return level, nil
+}
@@ -0,0 +1,173 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\logging\build_test.go
+ * @Description: 日志构建、轮转、脱敏与热更新测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kamalyes/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildWithConsole 构建 Handle，控制台输出重定向到临时文件
+func buildWithConsole(t *testing.T, cfg *Logging) (*Handle, string) {
+	t.Helper()
+	console := filepath.Join(t.TempDir(), "console.log")
+	f, err := os.Create(console)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+
+	stdout := os.Stdout
+	os.Stdout = f
+	h, err := Build(cfg)
+	os.Stdout = stdout
+	require.NoError(t, err)
+	t.Cleanup(func() { h.Close() })
+	return h, console
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func newFileConfig(dir string) *Logging {
+	return Default().
+		WithLevel("info").
+		WithOutput(logger.OutputRotate).
+		WithFilePath(filepath.Join(dir, "app.log")).
+		WithCompress(false)
+}
+
+func TestBuild_ConsoleAndFileWithMasking(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newFileConfig(dir)
+	cfg.Colorful = false
+	h, console := buildWithConsole(t, cfg)
+
+	log := h.Logger()
+	log.InfoKV("login", "user", "bob", "password", "p@ss word", "X-Access-Token", 12345)
+	log.InfoWithFields("json fields", map[string]any{"Authorization": `Bearer "abc"`})
+	log.Info("retry with api_key=k-123&page=2")
+	require.NoError(t, h.Flush())
+
+	for _, path := range []string{console, filepath.Join(dir, "app.log")} {
+		lines := readLines(t, path)
+		require.Len(t, lines, 3, path)
+		for _, line := range lines {
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), "脱敏后仍为合法 JSON: %s", line)
+		}
+		content := strings.Join(lines, "\n")
+		for _, secret := range []string{"p@ss", "12345", "abc", "k-123"} {
+			assert.NotContains(t, content, secret, path)
+		}
+		assert.Contains(t, content, `"password":"***REDACTED***"`)
+		assert.Contains(t, content, `"X-Access-Token":"***REDACTED***"`)
+		assert.Contains(t, content, `api_key=***REDACTED***&page=2`)
+		assert.Contains(t, content, `"user":"bob"`)
+	}
+}
+
+func TestBuild_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newFileConfig(dir).WithMaxSize(1).WithMaxBackups(2).WithBufferSize(1024)
+	h, _ := buildWithConsole(t, cfg)
+
+	payload := strings.Repeat("x", 1024)
+	for i := 0; i < 3*1024; i++ {
+		h.Logger().Info("%d %s", i, payload)
+	}
+	require.NoError(t, h.Close())
+
+	for _, name := range []string{"app.log", "app.log.1", "app.log.2"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err, name)
+		assert.LessOrEqual(t, info.Size(), int64(1024*1024), name)
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "app.log.3"), "超出 MaxBackups 的备份被丢弃")
+}
+
+func TestHandle_Reconfigure(t *testing.T) {
+	dir := t.TempDir()
+	cfg := newFileConfig(dir)
+	h, _ := buildWithConsole(t, cfg)
+	path := filepath.Join(dir, "app.log")
+
+	first := h.Logger()
+	first.Debug("dropped")
+	first.Info("kept")
+
+	// 仅调整级别时沿用同一 Logger
+	require.NoError(t, h.Reconfigure(cfg.Clone().(*Logging).WithLevel("debug")))
+	assert.Same(t, first, h.Logger())
+	first.Debug("debug enabled")
+
+	// 调整格式时替换 Logger，输出器保持不变
+	text := cfg.Clone().(*Logging).WithLevel("warn").WithFormat(logger.FormatText)
+	text.Colorful = false
+	require.NoError(t, h.Reconfigure(text))
+	assert.NotSame(t, first, h.Logger())
+	h.Logger().Info("hidden")
+	h.Logger().Warn("plain text token: abc")
+
+	// 无效配置不生效
+	assert.Error(t, h.Reconfigure(cfg.Clone().(*Logging).WithLevel("verbose")))
+	assert.Error(t, h.Reconfigure(cfg.Clone().(*Logging).WithFormat("yaml")))
+	assert.Equal(t, "warn", h.Config().Level)
+	assert.Equal(t, logger.FormatText, h.Config().Format)
+	assert.Equal(t, filepath.Join(dir, "app.log"), h.Config().FilePath)
+
+	require.NoError(t, h.Flush())
+	lines := readLines(t, path)
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"message":"kept"`)
+	assert.Contains(t, lines[1], `"message":"debug enabled"`)
+	assert.False(t, json.Valid([]byte(lines[2])))
+	assert.Contains(t, lines[2], "plain text token: ***REDACTED***")
+}
+
+func TestBuild_Errors(t *testing.T) {
+	_, err := Build(nil)
+	assert.Error(t, err)
+	_, err = Build(Default().WithLevel("loud"))
+	assert.Error(t, err)
+	_, err = Build(Default().WithOutput(logger.OutputRotate).WithFilePath(""))
+	assert.Error(t, err)
+}
+
+func TestMasker(t *testing.T) {
+	assert.Nil(t, NewMasker(nil, "x"))
+	assert.Equal(t, "password: x", string((*Masker)(nil).Mask([]byte("password: x"))))
+
+	m := NewMasker([]string{"secret", " "}, "")
+	cases := map[string]string{
+		`{"client_secret":"a\"b","n":1}`:       `{"client_secret":"***","n":1}`,
+		`{"Secret": 42}`:                       `{"Secret": "***"}`,
+		`msg {secret: top secret, user: bob}`:  `msg {secret: ***, user: bob}`,
+		`[/src/secret.go:12:main.run] started`: `[/src/secret.go:12:main.run] started`,
+		`SECRET = abc def`:                     `SECRET = ***`,
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, string(m.Mask([]byte(in))), in)
+	}
+
+	dollar := NewMasker([]string{"token"}, "$1")
+	assert.Equal(t, `{"token":"$1"} token=$1`, string(dollar.Mask([]byte(`{"token":"v"} token=v`))))
+}
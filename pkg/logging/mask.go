@@ -0,0 +1,241 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\logging\mask.go
+ * @Description: 敏感字段脱敏输出器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/kamalyes/go-logger"
+)
+
+// defaultSensitiveMask 未配置 SensitiveMask 时使用的掩码
+const defaultSensitiveMask = "***"
+
+// Masker 按敏感字段关键词替换日志行中的字段值
+//
+// 字段名包含任一关键词（忽略大小写）即视为敏感字段，支持以下写法：
+//   - JSON：`"password":"xxx"`、`"token":123`
+//   - 文本：`password: xxx`（go-logger 的 KV/Fields 文本格式）、`password=xxx`
+//
+// 文本写法的冒号后必须有空白，以免误伤 `token.go:12` 形式的调用者信息
+type Masker struct {
+	keys     [][]byte
+	jsonMask []byte
+	textMask []byte
+}
+
+// maskSpan 待替换的值区间
+type maskSpan struct {
+	start, end int
+	json       bool
+}
+
+// NewMasker 根据关键词与掩码创建脱敏器，关键词为空时返回 nil
+func NewMasker(keys []string, mask string) *Masker {
+	m := &Masker{}
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			m.keys = append(m.keys, asciiLower([]byte(key)))
+		}
+	}
+	if len(m.keys) == 0 {
+		return nil
+	}
+	if mask == "" {
+		mask = defaultSensitiveMask
+	}
+	m.jsonMask, _ = json.Marshal(mask)
+	m.textMask = []byte(mask)
+	return m
+}
+
+// Mask 返回脱敏后的内容，不修改入参；未命中关键词时原样返回
+func (m *Masker) Mask(p []byte) []byte {
+	if m == nil {
+		return p
+	}
+	lower := asciiLower(p)
+	var spans []maskSpan
+	seen := map[int]bool{}
+	for _, key := range m.keys {
+		for offset := 0; ; {
+			i := bytes.Index(lower[offset:], key)
+			if i < 0 {
+				break
+			}
+			i += offset
+			offset = i + len(key)
+
+			// 扩展为完整字段名，同一字段只处理一次
+			ks, ke := i, offset
+			for ks > 0 && isKeyByte(p[ks-1]) {
+				ks--
+			}
+			for ke < len(p) && isKeyByte(p[ke]) {
+				ke++
+			}
+			if seen[ks] {
+				continue
+			}
+			if span, ok := valueSpan(p, ks, ke); ok {
+				seen[ks] = true
+				spans = append(spans, span)
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return p
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	out := make([]byte, 0, len(p)+len(spans)*len(m.jsonMask))
+	last := 0
+	for _, span := range spans {
+		if span.start < last {
+			continue
+		}
+		out = append(out, p[last:span.start]...)
+		if span.json {
+			out = append(out, m.jsonMask...)
+		} else {
+			out = append(out, m.textMask...)
+		}
+		last = span.end
+	}
+	return append(out, p[last:]...)
+}
+
+// valueSpan 定位字段名 p[ks:ke] 对应的值区间
+func valueSpan(p []byte, ks, ke int) (maskSpan, bool) {
+	// JSON："key" 之后跟冒号
+	if ks > 0 && p[ks-1] == '"' && ke < len(p) && p[ke] == '"' {
+		i := skipSpace(p, ke+1)
+		if i >= len(p) || p[i] != ':' {
+			return maskSpan{}, false
+		}
+		start := skipSpace(p, i+1)
+		if start >= len(p) {
+			return maskSpan{}, false
+		}
+		end := start
+		if p[start] == '"' {
+			for end = start + 1; end < len(p) && p[end] != '"'; end++ {
+				if p[end] == '\\' {
+					end++
+				}
+			}
+			end = min(end+1, len(p))
+		} else {
+			for end < len(p) && !isSpace(p[end]) && strings.IndexByte(",}]", p[end]) < 0 {
+				end++
+			}
+		}
+		return maskSpan{start: start, end: end, json: true}, end > start
+	}
+
+	// 文本：key: value 或 key=value
+	i := ke
+	switch {
+	case i+1 < len(p) && p[i] == ':' && isSpace(p[i+1]):
+		i = skipSpace(p, i+1)
+	default:
+		i = skipSpace(p, i)
+		if i >= len(p) || p[i] != '=' {
+			return maskSpan{}, false
+		}
+		i = skipSpace(p, i+1)
+	}
+	end := scanToken(p, i, ",}&\"")
+	if end == i {
+		return maskSpan{}, false
+	}
+	// 值中可包含单个空格分隔的多个词，遇到下一个 key: / key= 为止
+	for end < len(p) && p[end] == ' ' {
+		next := scanToken(p, end+1, ",}&\":=")
+		if next == end+1 || (next < len(p) && (p[next] == ':' || p[next] == '=')) {
+			break
+		}
+		end = next
+	}
+	return maskSpan{start: i, end: end}, true
+}
+
+// scanToken 返回从 i 开始不含空白与 stop 字符的最长区间终点
+func scanToken(p []byte, i int, stop string) int {
+	for i < len(p) && !isSpace(p[i]) && strings.IndexByte(stop, p[i]) < 0 {
+		i++
+	}
+	return i
+}
+
+// skipSpace 跳过空白
+func skipSpace(p []byte, i int) int {
+	for i < len(p) && isSpace(p[i]) {
+		i++
+	}
+	return i
+}
+
+// isSpace 判断是否为空白字符
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// isKeyByte 字段名允许的字符
+func isKeyByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// asciiLower 按 ASCII 转小写，保持字节偏移不变
+func asciiLower(p []byte) []byte {
+	out := make([]byte, len(p))
+	for i, c := range p {
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// maskWriter 写入前脱敏的输出器
+type maskWriter struct {
+	logger.IWriter
+	masker *Masker
+}
+
+// newMaskWriter 包装输出器，masker 为 nil 时原样返回
+func newMaskWriter(w logger.IWriter, masker *Masker) logger.IWriter {
+	if masker == nil {
+		return w
+	}
+	return &maskWriter{IWriter: w, masker: masker}
+}
+
+// Write 脱敏后写入，返回值按原始长度计算以满足 io.Writer 约定
+func (w *maskWriter) Write(p []byte) (int, error) {
+	if _, err := w.IWriter.Write(w.masker.Mask(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteLevel 脱敏后按级别写入
+func (w *maskWriter) WriteLevel(level logger.LogLevel, p []byte) (int, error) {
+	if _, err := w.IWriter.WriteLevel(level, w.masker.Mask(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
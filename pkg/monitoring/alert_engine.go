@@ -0,0 +1,525 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\monitoring\alert_engine.go
+ * @Description: 告警规则评估引擎：状态跟踪、去重分组、注释模板与通知投递
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/alerting"
+)
+
+// AlertState 告警状态
+type AlertState string
+
+const (
+	AlertPending  AlertState = "pending"  // 条件满足但未达到持续时间
+	AlertFiring   AlertState = "firing"   // 已触发
+	AlertResolved AlertState = "resolved" // 已恢复
+)
+
+// Sample 指标查询返回的单条样本
+type Sample struct {
+	Labels map[string]string `json:"labels"`
+	Value  float64           `json:"value"`
+}
+
+// MetricSource 指标数据源，按规则的 Query 返回当前样本
+type MetricSource interface {
+	Query(ctx context.Context, query string) ([]Sample, error)
+}
+
+// MetricSourceFunc 函数形式的指标数据源
+type MetricSourceFunc func(ctx context.Context, query string) ([]Sample, error)
+
+// Query 实现 MetricSource
+func (f MetricSourceFunc) Query(ctx context.Context, query string) ([]Sample, error) {
+	return f(ctx, query)
+}
+
+// Alert 单条告警实例，由规则名称与标签唯一确定
+type Alert struct {
+	Rule        string            `json:"rule"`
+	Fingerprint string            `json:"fingerprint"`
+	State       AlertState        `json:"state"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Value       float64           `json:"value"`
+	ActiveAt    time.Time         `json:"activeAt"`
+	FiredAt     time.Time         `json:"firedAt,omitempty"`
+	ResolvedAt  time.Time         `json:"resolvedAt,omitempty"`
+	notified    bool              // 当前状态的通知是否已投递（或被节流）
+	retry       []string          // 当前状态的通知在这些渠道投递失败，待补发
+}
+
+// AlertGroup 一次通知中按分组标签聚合的告警
+type AlertGroup struct {
+	Key    string            `json:"groupKey"`
+	Status AlertState        `json:"status"`
+	Labels map[string]string `json:"groupLabels"`
+	Alerts []Alert           `json:"alerts"`
+	retry  []string          // 非空时只向这些渠道补发
+}
+
+// 支持的比较条件
+var alertConditions = map[string]func(value, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"==": func(v, t float64) bool { return v == t },
+	"!=": func(v, t float64) bool { return v != t },
+}
+
+// Validate 校验告警规则：名称、查询、条件、持续时间、严重级别与注释模板
+func (r *AlertRule) Validate() error {
+	_, err := compileAlertRule(*r)
+	return err
+}
+
+// compiledRule 预解析后的告警规则
+type compiledRule struct {
+	AlertRule
+	compare     func(value, threshold float64) bool
+	duration    time.Duration
+	annotations map[string]*template.Template
+}
+
+func compileAlertRule(r AlertRule) (*compiledRule, error) {
+	if r.Name == "" {
+		return nil, fmt.Errorf("alert rule name is required")
+	}
+	if r.Query == "" {
+		return nil, fmt.Errorf("alert rule %q: query is required", r.Name)
+	}
+	compare, ok := alertConditions[strings.TrimSpace(r.Condition)]
+	if !ok {
+		return nil, fmt.Errorf("alert rule %q: unsupported condition %q", r.Name, r.Condition)
+	}
+	var duration time.Duration
+	if r.Duration != "" {
+		d, err := time.ParseDuration(r.Duration)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("alert rule %q: invalid duration %q", r.Name, r.Duration)
+		}
+		duration = d
+	}
+	switch r.Severity {
+	case "", "critical", "warning", "info":
+	default:
+		return nil, fmt.Errorf("alert rule %q: unsupported severity %q", r.Name, r.Severity)
+	}
+
+	annotations := make(map[string]*template.Template, len(r.Annotations))
+	for key, text := range r.Annotations {
+		tmpl, err := template.New(key).Option("missingkey=zero").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %q: invalid annotation %q: %w", r.Name, key, err)
+		}
+		annotations[key] = tmpl
+	}
+	return &compiledRule{AlertRule: r, compare: compare, duration: duration, annotations: annotations}, nil
+}
+
+// Notifiers 根据 Webhooks、Email、Slack 配置创建通知渠道
+func (a *Alerting) Notifiers() ([]alerting.Notifier, error) {
+	var notifiers []alerting.Notifier
+	for _, w := range a.Webhooks {
+		timeout := time.Duration(0)
+		if w.Timeout != "" {
+			d, err := time.ParseDuration(w.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("webhook %q: invalid timeout %q", w.Name, w.Timeout)
+			}
+			timeout = d
+		}
+		notifiers = append(notifiers, alerting.NewWebhookNotifier(w.Name, w.URL, w.Headers, timeout))
+	}
+	if a.Email != nil && a.Email.Enabled {
+		e := a.Email
+		notifiers = append(notifiers, alerting.NewSMTPNotifier("email", e.SMTPHost, e.SMTPPort, e.Username, e.Password, e.From, e.To, e.TLS))
+	}
+	if a.Slack != nil && a.Slack.Enabled {
+		s := alerting.NewSlackBotNotifier("slack", a.Slack.Token, a.Slack.Channel)
+		s.Username, s.IconEmoji = a.Slack.Username, a.Slack.IconEmoji
+		notifiers = append(notifiers, s)
+	}
+	return notifiers, nil
+}
+
+// AlertEngine 告警规则评估引擎
+type AlertEngine struct {
+	mu         sync.Mutex // 保护 active 与 resolved，查询与投递期间不持有
+	evalMu     sync.Mutex // 串行化 Evaluate，避免并发评估重复投递
+	enabled    bool
+	rules      []*compiledRule
+	source     MetricSource
+	dispatcher *alerting.Dispatcher
+	groupBy    []string
+	now        func() time.Time
+	active     map[string]*Alert
+	resolved   map[string]*Alert // 已恢复但恢复通知尚未投递的告警
+}
+
+// NewAlertEngine 校验规则并根据告警配置创建通知渠道
+func NewAlertEngine(cfg *Alerting, source MetricSource) (*AlertEngine, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("alerting config is nil")
+	}
+	if source == nil {
+		return nil, fmt.Errorf("metric source is nil")
+	}
+
+	var errs []error
+	rules := make([]*compiledRule, 0, len(cfg.Rules))
+	names := make(map[string]struct{}, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		rule, err := compileAlertRule(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alerting.rules[%d]: %w", i, err))
+			continue
+		}
+		if _, exists := names[r.Name]; exists {
+			errs = append(errs, fmt.Errorf("alerting.rules[%d]: duplicate rule name %q", i, r.Name))
+			continue
+		}
+		names[r.Name] = struct{}{}
+		rules = append(rules, rule)
+	}
+	notifiers, err := cfg.Notifiers()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &AlertEngine{
+		enabled:    cfg.Enabled,
+		rules:      rules,
+		source:     source,
+		dispatcher: alerting.NewDispatcher(notifiers...).WithRetry(3, time.Second),
+		groupBy:    []string{"alertname"},
+		now:        time.Now,
+		active:     make(map[string]*Alert),
+		resolved:   make(map[string]*Alert),
+	}, nil
+}
+
+// WithNotifiers 追加通知渠道，如 alerting.Alerting.Notifiers() 创建的渠道
+func (e *AlertEngine) WithNotifiers(notifiers ...alerting.Notifier) *AlertEngine {
+	e.dispatcher.WithNotifiers(notifiers...)
+	return e
+}
+
+// WithGroupBy 设置分组标签，默认按 alertname 分组
+func (e *AlertEngine) WithGroupBy(labels ...string) *AlertEngine {
+	e.groupBy = labels
+	return e
+}
+
+// WithRetry 设置投递总尝试次数与初始退避时间
+func (e *AlertEngine) WithRetry(attempts int, backoff time.Duration) *AlertEngine {
+	e.dispatcher.WithRetry(attempts, backoff)
+	return e
+}
+
+// WithClock 设置时钟，便于测试
+func (e *AlertEngine) WithClock(now func() time.Time) *AlertEngine {
+	e.now = now
+	e.dispatcher.WithClock(now)
+	return e
+}
+
+// WithThrottle 设置同一分组的通知冷却时间与每小时最大通知次数，0 表示不限制
+func (e *AlertEngine) WithThrottle(cooldown time.Duration, maxPerHour int) *AlertEngine {
+	e.dispatcher.WithCooldown(cooldown).WithMaxPerHour(maxPerHour)
+	return e
+}
+
+// Dispatcher 返回引擎使用的通知分发器，可与其他模块共享渠道
+func (e *AlertEngine) Dispatcher() *alerting.Dispatcher {
+	return e.dispatcher
+}
+
+// Alerts 返回当前处于 pending 或 firing 状态的告警
+func (e *AlertEngine) Alerts() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	alerts := make([]Alert, 0, len(e.active))
+	for _, a := range e.active {
+		alerts = append(alerts, *a)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Fingerprint < alerts[j].Fingerprint })
+	return alerts
+}
+
+// Run 按固定间隔评估规则，直到 ctx 结束
+func (e *AlertEngine) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.Evaluate(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate 评估全部规则，投递新触发与已恢复的告警，返回本次投递的分组（不含被节流的分组）
+//
+// 同一告警只在状态变化时通知一次，投递失败的通知在后续评估中重试直至成功；
+// 查询在锁外执行，慢查询不阻塞 Alerts；查询或投递失败不影响其他规则，错误合并返回
+func (e *AlertEngine) Evaluate(ctx context.Context) ([]AlertGroup, error) {
+	if !e.enabled {
+		return nil, nil
+	}
+	e.evalMu.Lock()
+	defer e.evalMu.Unlock()
+
+	e.mu.Lock()
+	rules := append([]*compiledRule(nil), e.rules...)
+	now := e.now()
+	e.mu.Unlock()
+
+	var errs []error
+	results := make(map[*compiledRule][]Sample, len(rules))
+	for _, rule := range rules {
+		samples, err := e.source.Query(ctx, rule.Query)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert rule %q: query failed: %w", rule.Name, err))
+			continue // 查询失败时保持原状态
+		}
+		results[rule] = samples
+	}
+
+	e.mu.Lock()
+	for _, rule := range rules {
+		if samples, ok := results[rule]; ok {
+			e.evaluateRule(rule, samples, now)
+		}
+	}
+	pending := e.pendingAlerts()
+	e.mu.Unlock()
+
+	var delivered []AlertGroup
+	for _, g := range e.group(pending) {
+		var err error
+		if len(g.retry) > 0 {
+			// 上次部分渠道失败：只补发失败的渠道，不受冷却限制
+			err = e.dispatcher.Retry(ctx, groupMessage(g), g.retry)
+		} else {
+			// 分组键加上状态，使恢复通知不受触发通知的冷却影响
+			err = e.dispatcher.Send(ctx, string(g.Status)+"/"+g.Key, groupMessage(g))
+		}
+		throttled := errors.Is(err, alerting.ErrCooldown) || errors.Is(err, alerting.ErrHourlyLimit)
+		var de *alerting.DeliveryError
+		switch {
+		case err == nil || throttled:
+			e.markNotified(g, nil)
+		case errors.As(err, &de) && (de.Partial() || len(g.retry) > 0):
+			// 已有渠道送达：视为已通知，下次只补发失败的渠道
+			e.markNotified(g, de.Failed)
+		}
+		if throttled {
+			continue // 被节流的分组不视为错误，也不再重试
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		delivered = append(delivered, g)
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// evaluateRule 更新单条规则的告警状态
+func (e *AlertEngine) evaluateRule(rule *compiledRule, samples []Sample, now time.Time) {
+	seen := make(map[string]struct{}, len(samples))
+
+	for _, sample := range samples {
+		if !rule.compare(sample.Value, rule.Threshold) {
+			continue
+		}
+		labels := alertLabels(rule, sample.Labels)
+		fp := fingerprint(labels)
+		if _, dup := seen[fp]; dup {
+			continue
+		}
+		seen[fp] = struct{}{}
+
+		alert, exists := e.active[fp]
+		if !exists {
+			alert = &Alert{Rule: rule.Name, Fingerprint: fp, State: AlertPending, Labels: labels, ActiveAt: now}
+			e.active[fp] = alert
+			delete(e.resolved, fp) // 再次触发时丢弃尚未投递的恢复通知
+		}
+		alert.Value = sample.Value
+		alert.Annotations = renderAnnotations(rule, labels, sample.Value)
+		if alert.State == AlertPending && now.Sub(alert.ActiveAt) >= rule.duration {
+			alert.State = AlertFiring
+			alert.FiredAt = now
+		}
+	}
+
+	for fp, alert := range e.active {
+		if alert.Rule != rule.Name {
+			continue
+		}
+		if _, ok := seen[fp]; ok {
+			continue
+		}
+		delete(e.active, fp)
+		// 触发通知未送达的告警恢复时不再发送恢复通知
+		if alert.State == AlertFiring && alert.notified {
+			alert.State = AlertResolved
+			alert.ResolvedAt = now
+			alert.notified = false
+			alert.retry = nil
+			e.resolved[fp] = alert
+		}
+	}
+}
+
+// pendingAlerts 返回待通知的告警：未送达（或待补发）的触发通知与恢复通知，按指纹排序
+func (e *AlertEngine) pendingAlerts() []Alert {
+	var alerts []Alert
+	for _, a := range e.active {
+		if a.State == AlertFiring && (!a.notified || len(a.retry) > 0) {
+			alerts = append(alerts, *a)
+		}
+	}
+	for _, a := range e.resolved {
+		alerts = append(alerts, *a)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Fingerprint < alerts[j].Fingerprint })
+	return alerts
+}
+
+// markNotified 记录分组中告警的通知已处理，failed 为待补发的渠道，为空时不再重试
+func (e *AlertEngine) markNotified(g AlertGroup, failed []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, a := range g.Alerts {
+		switch g.Status {
+		case AlertFiring:
+			if alert, ok := e.active[a.Fingerprint]; ok && alert.State == AlertFiring {
+				alert.notified = true
+				alert.retry = failed
+			}
+		case AlertResolved:
+			if alert, ok := e.resolved[a.Fingerprint]; ok && len(failed) > 0 {
+				alert.retry = failed
+				continue
+			}
+			delete(e.resolved, a.Fingerprint)
+		}
+	}
+}
+
+// group 按状态与分组标签聚合告警
+func (e *AlertEngine) group(alerts []Alert) []AlertGroup {
+	index := make(map[string]int)
+	var groups []AlertGroup
+	for _, a := range alerts {
+		groupLabels := make(map[string]string, len(e.groupBy))
+		for _, name := range e.groupBy {
+			groupLabels[name] = a.Labels[name]
+		}
+		key := string(a.State) + "/" + fingerprint(groupLabels)
+		// 待补发的告警按失败渠道单独分组
+		groupID := key + "/" + strings.Join(a.retry, ",")
+		i, ok := index[groupID]
+		if !ok {
+			i = len(groups)
+			index[groupID] = i
+			groups = append(groups, AlertGroup{Key: key, Status: a.State, Labels: groupLabels, retry: a.retry})
+		}
+		groups[i].Alerts = append(groups[i].Alerts, a)
+	}
+	for i := range groups {
+		sort.Slice(groups[i].Alerts, func(a, b int) bool {
+			return groups[i].Alerts[a].Fingerprint < groups[i].Alerts[b].Fingerprint
+		})
+	}
+	return groups
+}
+
+// groupMessage 将告警分组渲染为通知消息，Webhook 负载为整个分组
+func groupMessage(g AlertGroup) *alerting.Message {
+	var text strings.Builder
+	for _, a := range g.Alerts {
+		summary := a.Annotations["summary"]
+		if summary == "" {
+			summary = fmt.Sprintf("%s value=%g", a.Rule, a.Value)
+		}
+		fmt.Fprintf(&text, "- [%s] %s %s\n", a.State, summary, fingerprint(a.Labels))
+	}
+	return &alerting.Message{
+		Subject: fmt.Sprintf("[%s:%d] %s", strings.ToUpper(string(g.Status)), len(g.Alerts), fingerprint(g.Labels)),
+		Text:    text.String(),
+		Payload: g,
+	}
+}
+
+// alertLabels 合并样本标签与规则标签，并补充 alertname、severity
+func alertLabels(rule *compiledRule, sampleLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(sampleLabels)+len(rule.Labels)+2)
+	for k, v := range sampleLabels {
+		labels[k] = v
+	}
+	for k, v := range rule.Labels {
+		labels[k] = v
+	}
+	labels["alertname"] = rule.Name
+	if rule.Severity != "" {
+		labels["severity"] = rule.Severity
+	}
+	return labels
+}
+
+// renderAnnotations 渲染注释模板，可引用 .Labels、.Value、.Threshold、.Rule
+func renderAnnotations(rule *compiledRule, labels map[string]string, value float64) map[string]string {
+	data := map[string]any{"Labels": labels, "Value": value, "Threshold": rule.Threshold, "Rule": rule.Name}
+	rendered := make(map[string]string, len(rule.annotations))
+	for key, tmpl := range rule.annotations {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			rendered[key] = rule.Annotations[key] // 渲染失败时保留原文
+			continue
+		}
+		rendered[key] = buf.String()
+	}
+	return rendered
+}
+
+// fingerprint 按键排序生成标签指纹，如 {alertname="HighLatency",job="api"}
+func fingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", k, labels[k])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
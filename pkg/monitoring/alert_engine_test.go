@@ -0,0 +1,390 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\monitoring\alert_engine_test.go
+ * @Description: 告警规则评估引擎测试，使用假数据源与 httptest 接收端
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/alerting"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource 按查询返回预设样本的假数据源
+type fakeSource struct {
+	mu      sync.Mutex
+	samples map[string][]Sample
+	err     error
+}
+
+func (f *fakeSource) set(query string, samples ...Sample) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.samples[query] = samples
+}
+
+func (f *fakeSource) Query(_ context.Context, query string) ([]Sample, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.samples[query], nil
+}
+
+// webhookReceiver 记录收到的告警分组
+type webhookReceiver struct {
+	mu       sync.Mutex
+	groups   []AlertGroup
+	failures int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var g AlertGroup
+	json.NewDecoder(req.Body).Decode(&g)
+	r.groups = append(r.groups, g)
+}
+
+func (r *webhookReceiver) received() []AlertGroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]AlertGroup(nil), r.groups...)
+}
+
+func newTestEngine(t *testing.T, receiver *webhookReceiver) (*AlertEngine, *fakeSource, *time.Time) {
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	cfg := Default().EnableAlerting().Alerting
+	cfg.Rules = []AlertRule{{
+		Name:        "HighLatency",
+		Query:       "p99_latency",
+		Condition:   ">",
+		Threshold:   0.5,
+		Duration:    "1m",
+		Severity:    "warning",
+		Labels:      map[string]string{"team": "api"},
+		Annotations: map[string]string{"summary": "{{ .Labels.instance }} p99 {{ .Value }}s > {{ .Threshold }}s"},
+	}, {
+		Name:      "InstanceDown",
+		Query:     "up",
+		Condition: "==",
+		Threshold: 0,
+		Severity:  "critical",
+	}}
+	cfg.Webhooks = []Webhook{{Name: "ops", URL: server.URL, Timeout: "1s"}}
+
+	source := &fakeSource{samples: map[string][]Sample{}}
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	engine, err := NewAlertEngine(cfg, source)
+	require.NoError(t, err)
+	engine.WithClock(func() time.Time { return now }).WithRetry(3, time.Millisecond)
+	return engine, source, &now
+}
+
+func TestAlertEngine_Lifecycle(t *testing.T) {
+	receiver := &webhookReceiver{}
+	engine, source, now := newTestEngine(t, receiver)
+	ctx := context.Background()
+
+	source.set("p99_latency",
+		Sample{Labels: map[string]string{"instance": "a"}, Value: 0.8},
+		Sample{Labels: map[string]string{"instance": "b"}, Value: 0.2},
+		Sample{Labels: map[string]string{"instance": "c"}, Value: 0.9},
+	)
+
+	// 首次满足条件：pending，不通知
+	groups, err := engine.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+	alerts := engine.Alerts()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, AlertPending, alerts[0].State)
+
+	// 达到持续时间：firing，同一规则的两条告警合并为一组
+	*now = now.Add(time.Minute)
+	groups, err = engine.Evaluate(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, AlertFiring, groups[0].Status)
+	assert.Equal(t, map[string]string{"alertname": "HighLatency"}, groups[0].Labels)
+	require.Len(t, groups[0].Alerts, 2)
+	first := groups[0].Alerts[0]
+	assert.Equal(t, "a p99 0.8s > 0.5s", first.Annotations["summary"])
+	assert.Equal(t, map[string]string{"alertname": "HighLatency", "instance": "a", "team": "api", "severity": "warning"}, first.Labels)
+
+	// 持续触发不重复通知
+	*now = now.Add(time.Minute)
+	groups, err = engine.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	// 实例 a 恢复
+	source.set("p99_latency", Sample{Labels: map[string]string{"instance": "c"}, Value: 0.9})
+	*now = now.Add(time.Minute)
+	groups, err = engine.Evaluate(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, AlertResolved, groups[0].Status)
+	assert.Equal(t, "a", groups[0].Alerts[0].Labels["instance"])
+	assert.Len(t, engine.Alerts(), 1)
+
+	received := receiver.received()
+	require.Len(t, received, 2)
+	assert.Equal(t, AlertFiring, received[0].Status)
+	assert.Len(t, received[0].Alerts, 2)
+	assert.Equal(t, AlertResolved, received[1].Status)
+}
+
+func TestAlertEngine_ZeroDurationAndGrouping(t *testing.T) {
+	receiver := &webhookReceiver{failures: 2}
+	engine, source, _ := newTestEngine(t, receiver)
+	engine.WithGroupBy("severity")
+
+	source.set("up",
+		Sample{Labels: map[string]string{"instance": "a"}, Value: 0},
+		Sample{Labels: map[string]string{"instance": "a"}, Value: 0}, // 重复样本去重
+		Sample{Labels: map[string]string{"instance": "b"}, Value: 0},
+		Sample{Labels: map[string]string{"instance": "c"}, Value: 1},
+	)
+	groups, err := engine.Evaluate(context.Background())
+	require.NoError(t, err, "前两次投递失败后重试成功")
+	require.Len(t, groups, 1)
+	assert.Equal(t, map[string]string{"severity": "critical"}, groups[0].Labels)
+	assert.Len(t, groups[0].Alerts, 2)
+	assert.Len(t, receiver.received(), 1)
+}
+
+func TestAlertEngine_Errors(t *testing.T) {
+	receiver := &webhookReceiver{failures: 10}
+	engine, source, _ := newTestEngine(t, receiver)
+
+	source.set("up", Sample{Value: 0})
+	groups, err := engine.Evaluate(context.Background())
+	assert.Len(t, groups, 1)
+	assert.ErrorContains(t, err, `notifier "ops" failed after 3 attempt(s)`)
+
+	source.err = errors.New("backend down")
+	_, err = engine.Evaluate(context.Background())
+	assert.ErrorContains(t, err, `alert rule "HighLatency": query failed: backend down`)
+	assert.Len(t, engine.Alerts(), 1, "查询失败时保持原状态")
+
+	disabled := Default().Alerting
+	engine, err = NewAlertEngine(disabled, source)
+	require.NoError(t, err)
+	groups, err = engine.Evaluate(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, groups)
+}
+
+func TestAlertEngine_RetryUndelivered(t *testing.T) {
+	receiver := &webhookReceiver{failures: 3}
+	engine, source, now := newTestEngine(t, receiver)
+	ctx := context.Background()
+
+	source.set("up", Sample{Labels: map[string]string{"instance": "a"}, Value: 0})
+	_, err := engine.Evaluate(ctx)
+	assert.Error(t, err, "三次尝试均失败")
+	assert.Empty(t, receiver.received())
+
+	// 下次评估重试未送达的触发通知，送达后不再重复
+	*now = now.Add(time.Minute)
+	groups, err := engine.Evaluate(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, AlertFiring, groups[0].Status)
+	groups, err = engine.Evaluate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	// 恢复通知同样重试
+	receiver.mu.Lock()
+	receiver.failures = 3
+	receiver.mu.Unlock()
+	source.set("up")
+	_, err = engine.Evaluate(ctx)
+	assert.Error(t, err)
+	groups, err = engine.Evaluate(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, AlertResolved, groups[0].Status)
+
+	received := receiver.received()
+	require.Len(t, received, 2)
+	assert.Equal(t, AlertFiring, received[0].Status)
+	assert.Equal(t, AlertResolved, received[1].Status)
+}
+
+func TestAlertEngine_RetryFailedNotifiers(t *testing.T) {
+	for _, cooldown := range []time.Duration{0, time.Hour} {
+		ops := &webhookReceiver{}
+		engine, source, now := newTestEngine(t, ops)
+		oncall := &webhookReceiver{failures: 3}
+		server := httptest.NewServer(oncall)
+		t.Cleanup(server.Close)
+		engine.WithNotifiers(alerting.NewWebhookNotifier("oncall", server.URL, nil, time.Second))
+		engine.Dispatcher().WithCooldown(cooldown)
+		ctx := context.Background()
+
+		source.set("up", Sample{Labels: map[string]string{"instance": "a"}, Value: 0})
+		_, err := engine.Evaluate(ctx)
+		assert.Error(t, err, "oncall 三次尝试均失败")
+		require.Len(t, ops.received(), 1)
+		assert.Empty(t, oncall.received())
+
+		// 只向失败的渠道补发，已送达的渠道不重复收到；冷却期内同样补发
+		*now = now.Add(time.Minute)
+		groups, err := engine.Evaluate(ctx)
+		require.NoError(t, err, "cooldown=%s", cooldown)
+		require.Len(t, groups, 1)
+		assert.Len(t, ops.received(), 1, "cooldown=%s", cooldown)
+		require.Len(t, oncall.received(), 1, "cooldown=%s", cooldown)
+		assert.Equal(t, AlertFiring, oncall.received()[0].Status)
+
+		groups, err = engine.Evaluate(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+
+		// 恢复通知发往全部渠道
+		source.set("up")
+		_, err = engine.Evaluate(ctx)
+		require.NoError(t, err)
+		assert.Len(t, ops.received(), 2)
+		assert.Len(t, oncall.received(), 2)
+	}
+}
+
+func TestAlertEngine_QueryWithoutLock(t *testing.T) {
+	cfg := Default().EnableAlerting().Alerting
+	cfg.Rules = []AlertRule{{Name: "Slow", Query: "slow", Condition: ">", Threshold: 0}}
+	started, release := make(chan struct{}), make(chan struct{})
+	engine, err := NewAlertEngine(cfg, MetricSourceFunc(func(context.Context, string) ([]Sample, error) {
+		close(started)
+		<-release
+		return []Sample{{Value: 1}}, nil
+	}))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		engine.Evaluate(context.Background())
+	}()
+	<-started
+
+	listed := make(chan []Alert)
+	go func() { listed <- engine.Alerts() }()
+	select {
+	case alerts := <-listed:
+		assert.Empty(t, alerts)
+	case <-time.After(time.Second):
+		t.Fatal("查询期间 Alerts 被阻塞")
+	}
+	close(release)
+	<-done
+	assert.Len(t, engine.Alerts(), 1)
+}
+
+func TestAlertRule_Validate(t *testing.T) {
+	valid := AlertRule{Name: "r", Query: "q", Condition: ">=", Duration: "5m", Severity: "info"}
+	assert.NoError(t, valid.Validate())
+
+	cases := map[string]AlertRule{
+		"name is required":          {Query: "q", Condition: ">"},
+		"query is required":         {Name: "r", Condition: ">"},
+		`unsupported condition "~"`: {Name: "r", Query: "q", Condition: "~"},
+		`invalid duration "soon"`:   {Name: "r", Query: "q", Condition: ">", Duration: "soon"},
+		`unsupported severity "p1"`: {Name: "r", Query: "q", Condition: ">", Severity: "p1"},
+		`invalid annotation "summary"`: {Name: "r", Query: "q", Condition: ">",
+			Annotations: map[string]string{"summary": "{{ .Value"}},
+	}
+	for expected, rule := range cases {
+		assert.ErrorContains(t, rule.Validate(), expected)
+	}
+
+	m := Default()
+	m.AddAlertRule("dup", "q", ">", 1, "", "").AddAlertRule("dup", "q", ">", 1, "", "")
+	assert.NoError(t, m.Validate())
+	_, err := NewAlertEngine(m.Alerting, &fakeSource{})
+	assert.ErrorContains(t, err, `alerting.rules[1]: duplicate rule name "dup"`)
+
+	m.AddAlertRule("bad", "q", "=>", 1, "", "")
+	assert.ErrorContains(t, m.Validate(), "alerting.rules[2]")
+}
+
+func TestAlertEngine_ChannelNotifiers(t *testing.T) {
+	var mu sync.Mutex
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		texts = append(texts, body["text"])
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	channels, err := alerting.Default().AddSlackChannel("slack", server.URL, "#ops").Notifiers()
+	require.NoError(t, err)
+
+	engine, source, _ := newTestEngine(t, &webhookReceiver{})
+	engine.WithNotifiers(channels...)
+	source.set("up", Sample{Labels: map[string]string{"instance": "a"}, Value: 0})
+	_, err = engine.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, texts, 1)
+	assert.Contains(t, texts[0], `*[FIRING:1] {alertname="InstanceDown"}*`)
+	assert.Contains(t, texts[0], `instance="a"`)
+}
+
+func TestAlertEngine_Throttle(t *testing.T) {
+	receiver := &webhookReceiver{}
+	engine, source, now := newTestEngine(t, receiver)
+	engine.WithThrottle(10*time.Minute, 0)
+	ctx := context.Background()
+
+	source.set("up", Sample{Labels: map[string]string{"instance": "a"}, Value: 0})
+	groups, err := engine.Evaluate(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+
+	// 告警抖动：恢复通知不受触发通知冷却影响，再次触发被冷却抑制
+	source.set("up")
+	*now = now.Add(time.Minute)
+	groups, err = engine.Evaluate(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, AlertResolved, groups[0].Status)
+
+	source.set("up", Sample{Labels: map[string]string{"instance": "a"}, Value: 0})
+	*now = now.Add(time.Minute)
+	groups, err = engine.Evaluate(ctx)
+	assert.NoError(t, err, "被节流不视为错误")
+	assert.Empty(t, groups)
+	assert.Len(t, engine.Alerts(), 1, "告警状态照常更新")
+	assert.Len(t, receiver.received(), 2)
+}
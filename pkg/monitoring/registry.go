@@ -0,0 +1,319 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\monitoring\registry.go
+ * @Description: 根据指标配置校验并创建 Prometheus 采集器，提供抓取端点与 PushGateway 推送
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// 自定义指标类型
+const (
+	MetricTypeCounter   = "counter"
+	MetricTypeGauge     = "gauge"
+	MetricTypeHistogram = "histogram"
+	MetricTypeSummary   = "summary"
+)
+
+// 内置 HTTP 指标名称
+const (
+	HTTPRequestsTotal          = "http_requests_total"
+	HTTPRequestDurationSeconds = "http_request_duration_seconds"
+	HTTPRequestSizeBytes       = "http_request_size_bytes"
+	HTTPResponseSizeBytes      = "http_response_size_bytes"
+)
+
+var (
+	metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNamePattern  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	httpLabels        = []string{"method", "path", "status"}
+)
+
+// ParseObjectives 解析分位数目标，格式为 "0.5:0.05,0.9:0.01,0.99:0.001"
+func ParseObjectives(objectives string) (map[float64]float64, error) {
+	result := make(map[float64]float64)
+	if strings.TrimSpace(objectives) == "" {
+		return result, nil
+	}
+	for _, pair := range strings.Split(objectives, ",") {
+		quantileStr, errorStr, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid objective %q, expected quantile:error", pair)
+		}
+		quantile, err := strconv.ParseFloat(strings.TrimSpace(quantileStr), 64)
+		if err != nil || quantile <= 0 || quantile >= 1 {
+			return nil, fmt.Errorf("invalid quantile %q in objective %q, must be in (0, 1)", quantileStr, pair)
+		}
+		allowed, err := strconv.ParseFloat(strings.TrimSpace(errorStr), 64)
+		if err != nil || allowed < 0 || allowed >= 1 {
+			return nil, fmt.Errorf("invalid error %q in objective %q, must be in [0, 1)", errorStr, pair)
+		}
+		if _, exists := result[quantile]; exists {
+			return nil, fmt.Errorf("duplicate quantile %v in objectives", quantile)
+		}
+		result[quantile] = allowed
+	}
+	return result, nil
+}
+
+// ValidateBuckets 校验直方图桶严格递增
+func ValidateBuckets(buckets []float64) error {
+	for i := 1; i < len(buckets); i++ {
+		if buckets[i] <= buckets[i-1] {
+			return fmt.Errorf("buckets must be strictly increasing, got %v after %v", buckets[i], buckets[i-1])
+		}
+	}
+	return nil
+}
+
+// Validate 校验自定义指标定义：命名规则、标签、桶与分位数目标
+func (c *CustomMetric) Validate() error {
+	if !metricNamePattern.MatchString(c.Name) {
+		return fmt.Errorf("invalid metric name %q", c.Name)
+	}
+
+	seen := make(map[string]struct{}, len(c.Labels))
+	for _, label := range c.Labels {
+		if !labelNamePattern.MatchString(label) || strings.HasPrefix(label, "__") {
+			return fmt.Errorf("metric %q has invalid label name %q", c.Name, label)
+		}
+		if _, exists := seen[label]; exists {
+			return fmt.Errorf("metric %q has duplicate label %q", c.Name, label)
+		}
+		seen[label] = struct{}{}
+	}
+
+	metricType := strings.ToLower(c.Type)
+	if metricType != MetricTypeHistogram && len(c.Buckets) > 0 {
+		return fmt.Errorf("metric %q: buckets are only allowed for histogram", c.Name)
+	}
+	if metricType != MetricTypeSummary && c.Objectives != "" {
+		return fmt.Errorf("metric %q: objectives are only allowed for summary", c.Name)
+	}
+
+	switch metricType {
+	case MetricTypeCounter, MetricTypeGauge:
+	case MetricTypeHistogram:
+		if _, exists := seen["le"]; exists {
+			return fmt.Errorf("metric %q: label \"le\" is reserved for histogram", c.Name)
+		}
+		if err := ValidateBuckets(c.Buckets); err != nil {
+			return fmt.Errorf("metric %q: %w", c.Name, err)
+		}
+	case MetricTypeSummary:
+		if _, exists := seen["quantile"]; exists {
+			return fmt.Errorf("metric %q: label \"quantile\" is reserved for summary", c.Name)
+		}
+		if _, err := ParseObjectives(c.Objectives); err != nil {
+			return fmt.Errorf("metric %q: %w", c.Name, err)
+		}
+	default:
+		return fmt.Errorf("metric %q has unsupported type %q", c.Name, c.Type)
+	}
+	return nil
+}
+
+// ValidateDefinitions 校验默认桶与全部自定义指标，一次性返回所有错误
+func (m *Metrics) ValidateDefinitions() error {
+	var errs []error
+	if err := ValidateBuckets(m.Buckets); err != nil {
+		errs = append(errs, fmt.Errorf("metrics.buckets: %w", err))
+	}
+	names := make(map[string]struct{}, len(m.CustomMetrics))
+	for i := range m.CustomMetrics {
+		metric := &m.CustomMetrics[i]
+		if err := metric.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.custom-metrics[%d]: %w", i, err))
+			continue
+		}
+		if _, exists := names[metric.Name]; exists {
+			errs = append(errs, fmt.Errorf("metrics.custom-metrics[%d]: duplicate metric name %q", i, metric.Name))
+		}
+		names[metric.Name] = struct{}{}
+	}
+	return errors.Join(errs...)
+}
+
+// MetricsRegistry 由指标配置创建的采集器集合
+type MetricsRegistry struct {
+	config     *Metrics
+	registry   *prom.Registry
+	counters   map[string]*prom.CounterVec
+	gauges     map[string]*prom.GaugeVec
+	histograms map[string]*prom.HistogramVec
+	summaries  map[string]*prom.SummaryVec
+}
+
+// NewMetricsRegistry 校验指标配置并创建内置 HTTP 指标与自定义指标
+func NewMetricsRegistry(m *Metrics) (*MetricsRegistry, error) {
+	if m == nil {
+		return nil, fmt.Errorf("metrics config is nil")
+	}
+	if err := m.ValidateDefinitions(); err != nil {
+		return nil, err
+	}
+
+	r := &MetricsRegistry{
+		config:     m,
+		registry:   prom.NewRegistry(),
+		counters:   make(map[string]*prom.CounterVec),
+		gauges:     make(map[string]*prom.GaugeVec),
+		histograms: make(map[string]*prom.HistogramVec),
+		summaries:  make(map[string]*prom.SummaryVec),
+	}
+
+	// 内置 HTTP 指标
+	builtin := []CustomMetric{}
+	if m.RequestCount {
+		builtin = append(builtin, CustomMetric{Name: HTTPRequestsTotal, Type: MetricTypeCounter, Help: "Total number of HTTP requests", Labels: httpLabels})
+	}
+	if m.Duration {
+		builtin = append(builtin, CustomMetric{Name: HTTPRequestDurationSeconds, Type: MetricTypeHistogram, Help: "HTTP request latency in seconds", Labels: httpLabels, Buckets: m.Buckets})
+	}
+	if m.RequestSize {
+		builtin = append(builtin, CustomMetric{Name: HTTPRequestSizeBytes, Type: MetricTypeHistogram, Help: "HTTP request size in bytes", Labels: httpLabels, Buckets: prom.ExponentialBuckets(100, 10, 6)})
+	}
+	if m.ResponseSize {
+		builtin = append(builtin, CustomMetric{Name: HTTPResponseSizeBytes, Type: MetricTypeHistogram, Help: "HTTP response size in bytes", Labels: httpLabels, Buckets: prom.ExponentialBuckets(100, 10, 6)})
+	}
+
+	for _, metric := range append(builtin, m.CustomMetrics...) {
+		if err := r.register(metric); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// register 创建并注册单个采集器
+func (r *MetricsRegistry) register(c CustomMetric) error {
+	help := c.Help
+	if help == "" {
+		help = c.Name // Prometheus 要求 help 非空
+	}
+
+	var collector prom.Collector
+	switch strings.ToLower(c.Type) {
+	case MetricTypeCounter:
+		vec := prom.NewCounterVec(prom.CounterOpts{Name: c.Name, Help: help}, c.Labels)
+		r.counters[c.Name], collector = vec, vec
+	case MetricTypeGauge:
+		vec := prom.NewGaugeVec(prom.GaugeOpts{Name: c.Name, Help: help}, c.Labels)
+		r.gauges[c.Name], collector = vec, vec
+	case MetricTypeHistogram:
+		vec := prom.NewHistogramVec(prom.HistogramOpts{Name: c.Name, Help: help, Buckets: c.Buckets}, c.Labels)
+		r.histograms[c.Name], collector = vec, vec
+	case MetricTypeSummary:
+		objectives, _ := ParseObjectives(c.Objectives) // 已在 Validate 中校验
+		vec := prom.NewSummaryVec(prom.SummaryOpts{Name: c.Name, Help: help, Objectives: objectives}, c.Labels)
+		r.summaries[c.Name], collector = vec, vec
+	}
+
+	if err := r.registry.Register(collector); err != nil {
+		return fmt.Errorf("failed to register metric %q: %w", c.Name, err)
+	}
+	return nil
+}
+
+// Registry 返回底层 Prometheus 注册表，可继续注册其他采集器
+func (r *MetricsRegistry) Registry() *prom.Registry {
+	return r.registry
+}
+
+// Counter 按名称获取计数器
+func (r *MetricsRegistry) Counter(name string) (*prom.CounterVec, bool) {
+	vec, ok := r.counters[name]
+	return vec, ok
+}
+
+// Gauge 按名称获取仪表盘
+func (r *MetricsRegistry) Gauge(name string) (*prom.GaugeVec, bool) {
+	vec, ok := r.gauges[name]
+	return vec, ok
+}
+
+// Histogram 按名称获取直方图
+func (r *MetricsRegistry) Histogram(name string) (*prom.HistogramVec, bool) {
+	vec, ok := r.histograms[name]
+	return vec, ok
+}
+
+// Summary 按名称获取摘要
+func (r *MetricsRegistry) Summary(name string) (*prom.SummaryVec, bool) {
+	vec, ok := r.summaries[name]
+	return vec, ok
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求到已启用的内置指标
+func (r *MetricsRegistry) ObserveHTTPRequest(method, path string, status int, duration time.Duration, requestSize, responseSize int64) {
+	labels := prom.Labels{"method": method, "path": path, "status": strconv.Itoa(status)}
+	if vec, ok := r.counters[HTTPRequestsTotal]; ok {
+		vec.With(labels).Inc()
+	}
+	if vec, ok := r.histograms[HTTPRequestDurationSeconds]; ok {
+		vec.With(labels).Observe(duration.Seconds())
+	}
+	if vec, ok := r.histograms[HTTPRequestSizeBytes]; ok && requestSize >= 0 {
+		vec.With(labels).Observe(float64(requestSize))
+	}
+	if vec, ok := r.histograms[HTTPResponseSizeBytes]; ok && responseSize >= 0 {
+		vec.With(labels).Observe(float64(responseSize))
+	}
+}
+
+// Handler 返回抓取端点，EnableOpenMetrics 开启时按 Accept 头协商 OpenMetrics 格式
+func (r *MetricsRegistry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: r.config.EnableOpenMetrics,
+		Registry:          r.registry,
+	})
+}
+
+// Push 将全部指标推送到 PushGateway（PUT，替换该 Job 下的全部指标）
+func (r *MetricsRegistry) Push(ctx context.Context, pg *prometheus.PushGateway) error {
+	pusher, err := r.pusher(pg)
+	if err != nil {
+		return err
+	}
+	return pusher.PushContext(ctx)
+}
+
+// Add 将全部指标追加到 PushGateway（POST，仅替换同名指标）
+func (r *MetricsRegistry) Add(ctx context.Context, pg *prometheus.PushGateway) error {
+	pusher, err := r.pusher(pg)
+	if err != nil {
+		return err
+	}
+	return pusher.AddContext(ctx)
+}
+
+// pusher 根据 PushGateway 配置创建推送器
+func (r *MetricsRegistry) pusher(pg *prometheus.PushGateway) (*push.Pusher, error) {
+	if pg == nil || !pg.Enabled {
+		return nil, fmt.Errorf("push gateway is not enabled")
+	}
+	if pg.Endpoint == "" || pg.JobName == "" {
+		return nil, fmt.Errorf("push gateway endpoint and job name are required")
+	}
+	return push.New(pg.Endpoint, pg.JobName).Gatherer(r.registry), nil
+}
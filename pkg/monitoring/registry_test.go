@@ -0,0 +1,157 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\monitoring\registry_test.go
+ * @Description: 指标注册表、抓取端点与 PushGateway 推送测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package monitoring
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetrics() *Metrics {
+	m := Default().Metrics
+	m.CustomMetrics = []CustomMetric{
+		{Name: "orders_created_total", Type: "counter", Help: "Orders created", Labels: []string{"channel"}},
+		{Name: "queue_depth", Type: "gauge", Labels: []string{"queue"}},
+		{Name: "payment_seconds", Type: "histogram", Buckets: []float64{0.1, 0.5, 1}},
+		{Name: "render_seconds", Type: "summary", Objectives: "0.5:0.05, 0.9:0.01"},
+	}
+	return m
+}
+
+func TestParseObjectives(t *testing.T) {
+	objectives, err := ParseObjectives("0.5:0.05,0.9:0.01,0.99:0.001")
+	require.NoError(t, err)
+	assert.Equal(t, map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}, objectives)
+
+	objectives, err = ParseObjectives("")
+	require.NoError(t, err)
+	assert.Empty(t, objectives)
+
+	for _, invalid := range []string{"0.5", "1.5:0.01", "0.5:abc", "0.5:0.1,0.5:0.2", "0:0.1"} {
+		_, err := ParseObjectives(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMetrics_ValidateDefinitions(t *testing.T) {
+	assert.NoError(t, newTestMetrics().ValidateDefinitions())
+	assert.NoError(t, Default().Validate())
+
+	m := newTestMetrics()
+	m.Buckets = []float64{1, 0.5}
+	m.CustomMetrics = append(m.CustomMetrics,
+		CustomMetric{Name: "1bad", Type: "counter"},
+		CustomMetric{Name: "latency", Type: "histogram", Buckets: []float64{1, 1}},
+		CustomMetric{Name: "latency_q", Type: "summary", Objectives: "0.5-0.05"},
+		CustomMetric{Name: "with_labels", Type: "gauge", Labels: []string{"__name", "ok"}},
+		CustomMetric{Name: "hist_le", Type: "histogram", Labels: []string{"le"}},
+		CustomMetric{Name: "gauge_buckets", Type: "gauge", Buckets: []float64{1}},
+		CustomMetric{Name: "meter", Type: "meter"},
+		CustomMetric{Name: "queue_depth", Type: "gauge"},
+	)
+
+	err := m.ValidateDefinitions()
+	require.Error(t, err)
+	for _, expected := range []string{
+		"metrics.buckets: buckets must be strictly increasing",
+		`metrics.custom-metrics[4]: invalid metric name "1bad"`,
+		`metrics.custom-metrics[5]: metric "latency": buckets must be strictly increasing`,
+		`metrics.custom-metrics[6]: metric "latency_q": invalid objective`,
+		`invalid label name "__name"`,
+		`label "le" is reserved`,
+		"buckets are only allowed for histogram",
+		`unsupported type "meter"`,
+		`metrics.custom-metrics[11]: duplicate metric name "queue_depth"`,
+	} {
+		assert.Contains(t, err.Error(), expected)
+	}
+
+	_, err = NewMetricsRegistry(m)
+	assert.Error(t, err)
+}
+
+func TestMetricsRegistry_Handler(t *testing.T) {
+	m := newTestMetrics()
+	m.ResponseSize = false
+	registry, err := NewMetricsRegistry(m)
+	require.NoError(t, err)
+
+	counter, ok := registry.Counter("orders_created_total")
+	require.True(t, ok)
+	counter.WithLabelValues("app").Add(3)
+	gauge, _ := registry.Gauge("queue_depth")
+	gauge.WithLabelValues("email").Set(7)
+	histogram, _ := registry.Histogram("payment_seconds")
+	histogram.WithLabelValues().Observe(0.3)
+	summary, _ := registry.Summary("render_seconds")
+	summary.WithLabelValues().Observe(0.2)
+	registry.ObserveHTTPRequest("GET", "/users", 200, 250*time.Millisecond, 128, 512)
+	_, ok = registry.Histogram(HTTPResponseSizeBytes)
+	assert.False(t, ok, "未启用的内置指标不创建")
+
+	scrape := func(accept string) (string, string) {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(rec, req)
+		return rec.Body.String(), rec.Header().Get("Content-Type")
+	}
+
+	body, contentType := scrape("application/openmetrics-text; version=1.0.0")
+	assert.Contains(t, contentType, "text/plain", "未启用 OpenMetrics 时始终返回文本格式")
+	assert.Contains(t, body, `orders_created_total{channel="app"} 3`)
+	assert.Contains(t, body, `queue_depth{queue="email"} 7`)
+	assert.Contains(t, body, `payment_seconds_bucket{le="0.5"} 1`)
+	assert.Contains(t, body, `render_seconds{quantile="0.9"} 0.2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",path="/users",status="200"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",path="/users",status="200",le="0.3"} 1`)
+
+	m.EnableOpenMetrics = true
+	body, contentType = scrape("application/openmetrics-text; version=1.0.0")
+	assert.Contains(t, contentType, "application/openmetrics-text")
+	assert.Contains(t, body, "# EOF")
+}
+
+func TestMetricsRegistry_Push(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	registry, err := NewMetricsRegistry(newTestMetrics())
+	require.NoError(t, err)
+	counter, _ := registry.Counter("orders_created_total")
+	counter.WithLabelValues("app").Inc()
+
+	pg := &prometheus.PushGateway{Enabled: true, Endpoint: server.URL, JobName: "batch-import"}
+	require.NoError(t, registry.Push(context.Background(), pg))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/batch-import", path)
+	assert.Contains(t, body, "orders_created_total")
+
+	require.NoError(t, registry.Add(context.Background(), pg))
+	assert.Equal(t, http.MethodPost, method)
+
+	pg.Enabled = false
+	assert.ErrorContains(t, registry.Push(context.Background(), pg), "not enabled")
+}
@@ -0,0 +1,299 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\pprof\handler.go
+ * @Description: 按配置挂载 net/http/pprof，支持认证、IP 白名单与采样率热更新
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package pprof
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"net/netip"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultPathPrefix 未配置 PathPrefix 时的挂载前缀
+const defaultPathPrefix = "/debug/pprof"
+
+// state 已解析的配置快照
+type state struct {
+	cfg      *PProf
+	profiles map[string]http.Handler
+	allowed  []netip.Prefix
+}
+
+// Handle pprof 处理器，由 Register 挂载到 ServeMux
+//
+// 挂载前缀在注册时确定，其余配置可通过 Reconfigure 热更新
+type Handle struct {
+	mu      sync.Mutex
+	prefix  string
+	state   atomic.Pointer[state]
+	applied bool // 是否已修改运行时采样率
+	// 首次修改前的运行时采样率，禁用时恢复
+	origMemRate       int
+	origMutexFraction int
+}
+
+// Register 将启用的性能分析挂载到 mux 的 PathPrefix 下
+//
+// 未启用时同样挂载，所有请求返回 404，便于后续热更新启用
+func Register(mux *http.ServeMux, cfg *PProf) (*Handle, error) {
+	if mux == nil {
+		return nil, fmt.Errorf("serve mux is nil")
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("pprof config is nil")
+	}
+	h := &Handle{prefix: normalizePrefix(cfg.PathPrefix)}
+	if err := h.Reconfigure(cfg); err != nil {
+		return nil, err
+	}
+	mux.Handle(h.prefix+"/", h)
+	return h, nil
+}
+
+// Prefix 返回挂载前缀
+func (h *Handle) Prefix() string {
+	return h.prefix
+}
+
+// Reconfigure 热更新配置并调整运行时采样率，禁用时恢复注册前的采样率
+//
+// PathPrefix 不能在运行时修改
+func (h *Handle) Reconfigure(cfg *PProf) error {
+	if cfg == nil {
+		return fmt.Errorf("pprof config is nil")
+	}
+	if prefix := normalizePrefix(cfg.PathPrefix); prefix != h.prefix {
+		return fmt.Errorf("pprof path prefix cannot change at runtime: %s -> %s", h.prefix, prefix)
+	}
+	next, err := newState(cfg)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if next.cfg.Enabled {
+		h.applySampling(next.cfg)
+	} else {
+		h.resetSampling()
+	}
+	h.state.Store(next)
+	return nil
+}
+
+// applySampling 按启用的性能分析设置采样率，未启用的类型关闭采样
+//
+// CPU 采样率由 net/http/pprof 固定为 100Hz，CPURate 不生效
+func (h *Handle) applySampling(cfg *PProf) {
+	if !h.applied {
+		h.origMemRate = runtime.MemProfileRate
+		h.origMutexFraction = runtime.SetMutexProfileFraction(-1)
+		h.applied = true
+	}
+	profiles, sampling := cfg.EnableProfiles, cfg.Sampling
+	if profiles == nil {
+		profiles = &ProfilesConfig{}
+	}
+	if sampling == nil {
+		sampling = &SamplingConfig{}
+	}
+
+	blockRate, mutexFraction, memRate := 0, 0, h.origMemRate
+	if profiles.Block {
+		blockRate = sampling.BlockRate
+	}
+	if profiles.Mutex {
+		mutexFraction = sampling.MutexFraction
+	}
+	if (profiles.Memory || profiles.Heap || profiles.Allocs) && sampling.MemoryRate > 0 {
+		memRate = sampling.MemoryRate
+	}
+	runtime.SetBlockProfileRate(blockRate)
+	runtime.SetMutexProfileFraction(mutexFraction)
+	runtime.MemProfileRate = memRate
+}
+
+// resetSampling 恢复注册前的采样率
+func (h *Handle) resetSampling() {
+	if !h.applied {
+		return
+	}
+	runtime.SetBlockProfileRate(0)
+	runtime.SetMutexProfileFraction(h.origMutexFraction)
+	runtime.MemProfileRate = h.origMemRate
+	h.applied = false
+}
+
+// ServeHTTP 校验 IP 白名单与认证后分发到对应的性能分析
+func (h *Handle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s := h.state.Load()
+	if !s.cfg.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.clientAllowed(r) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if !s.authorized(r) {
+		if auth := s.cfg.Authentication; auth.Username != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="pprof"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, h.prefix), "/")
+	handler, ok := s.profiles[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+// newState 解析配置：计算可访问的性能分析并校验认证配置
+func newState(cfg *PProf) (*state, error) {
+	cfg = cfg.Clone().(*PProf)
+	s := &state{cfg: cfg, profiles: make(map[string]http.Handler)}
+
+	if auth := cfg.Authentication; auth != nil && auth.Enabled {
+		for _, entry := range auth.AllowedIPs {
+			prefix, err := parseAllowed(entry)
+			if err != nil {
+				return nil, err
+			}
+			s.allowed = append(s.allowed, prefix)
+		}
+		if auth.RequireAuth && auth.AuthToken == "" && auth.Username == "" {
+			return nil, fmt.Errorf("pprof require-auth needs auth-token or username")
+		}
+	}
+
+	profiles := cfg.EnableProfiles
+	if profiles == nil {
+		profiles = &ProfilesConfig{}
+	}
+	add := func(enabled bool, name string, handler http.Handler) {
+		if enabled {
+			s.profiles[name] = handler
+		}
+	}
+	web := cfg.WebInterface != nil && cfg.WebInterface.Enabled
+	add(web, "", http.HandlerFunc(index))
+	add(web, "cmdline", http.HandlerFunc(pprof.Cmdline))
+	add(web, "symbol", http.HandlerFunc(pprof.Symbol))
+	add(profiles.CPU, "profile", http.HandlerFunc(pprof.Profile))
+	add(profiles.Trace, "trace", http.HandlerFunc(pprof.Trace))
+	add(profiles.Heap || profiles.Memory, "heap", pprof.Handler("heap"))
+	add(profiles.Allocs || profiles.Memory, "allocs", pprof.Handler("allocs"))
+	add(profiles.Goroutine, "goroutine", pprof.Handler("goroutine"))
+	add(profiles.Block, "block", pprof.Handler("block"))
+	add(profiles.Mutex, "mutex", pprof.Handler("mutex"))
+	add(profiles.ThreadCreate, "threadcreate", pprof.Handler("threadcreate"))
+	return s, nil
+}
+
+// clientAllowed 检查客户端 IP 是否在白名单中，白名单为空时放行
+//
+// 仅使用连接的远端地址，不信任 X-Forwarded-For 等可伪造的请求头
+func (s *state) clientAllowed(r *http.Request) bool {
+	if len(s.allowed) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range s.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorized 校验认证：Bearer 令牌、token 查询参数或 Basic 认证任一通过即可
+func (s *state) authorized(r *http.Request) bool {
+	auth := s.cfg.Authentication
+	if auth == nil || !auth.Enabled || (!auth.RequireAuth && auth.AuthToken == "" && auth.Username == "") {
+		return true
+	}
+	if auth.AuthToken != "" {
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secureEqual(token, auth.AuthToken) {
+			return true
+		}
+		if token := r.URL.Query().Get("token"); token != "" && secureEqual(token, auth.AuthToken) {
+			return true
+		}
+	}
+	if auth.Username != "" {
+		if username, password, ok := r.BasicAuth(); ok &&
+			secureEqual(username, auth.Username) && secureEqual(password, auth.Password) {
+			return true
+		}
+	}
+	return false
+}
+
+// index 渲染 pprof 索引页；pprof.Index 按 /debug/pprof/ 解析路径，自定义前缀时需改写
+func index(w http.ResponseWriter, r *http.Request) {
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = defaultPathPrefix + "/"
+	pprof.Index(w, r2)
+}
+
+// parseAllowed 解析 IP 或 CIDR
+func parseAllowed(entry string) (netip.Prefix, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid pprof allowed ip %q: %w", entry, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(entry)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid pprof allowed ip %q: %w", entry, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// normalizePrefix 规范化挂载前缀，去掉结尾斜杠
+func normalizePrefix(prefix string) string {
+	prefix = strings.TrimRight(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return defaultPathPrefix
+	}
+	if prefix[0] != '/' {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
+// secureEqual 常量时间比较
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,124 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\pprof\handler_test.go
+ * @Description: pprof 处理器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package pprof
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(mux http.Handler, path, remoteAddr string, setup func(*http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if setup != nil {
+		setup(req)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRegister_Profiles(t *testing.T) {
+	cfg := Default().Enable().WithPathPrefix("/internal/pprof/")
+	mux := http.NewServeMux()
+	h, err := Register(mux, cfg)
+	require.NoError(t, err)
+	defer h.Reconfigure(Default().WithPathPrefix("/internal/pprof"))
+	assert.Equal(t, "/internal/pprof", h.Prefix())
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/internal/pprof/", http.StatusOK},
+		{"/internal/pprof/goroutine?debug=1", http.StatusOK},
+		{"/internal/pprof/heap?debug=1", http.StatusOK},
+		{"/internal/pprof/cmdline", http.StatusOK},
+		{"/internal/pprof/block", http.StatusNotFound},
+		{"/internal/pprof/mutex", http.StatusNotFound},
+		{"/internal/pprof/trace", http.StatusNotFound},
+		{"/internal/pprof/unknown", http.StatusNotFound},
+		{"/debug/pprof/", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, serve(mux, tt.path, "127.0.0.1:1234", nil).Code, tt.path)
+	}
+	assert.Contains(t, serve(mux, "/internal/pprof/", "127.0.0.1:1234", nil).Body.String(), "goroutine")
+
+	cfg.WithWebInterface(false, "", "")
+	require.NoError(t, h.Reconfigure(cfg))
+	assert.Equal(t, http.StatusNotFound, serve(mux, "/internal/pprof/", "127.0.0.1:1234", nil).Code)
+	assert.Error(t, h.Reconfigure(Default().Enable().WithPathPrefix("/other")))
+}
+
+func TestRegister_AuthAndAllowList(t *testing.T) {
+	cfg := Default().Enable().WithAuthToken("secret").WithAllowedIPs([]string{"10.0.0.0/8", "::1"})
+	mux := http.NewServeMux()
+	h, err := Register(mux, cfg)
+	require.NoError(t, err)
+	defer h.Reconfigure(Default())
+
+	bearer := func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }
+	assert.Equal(t, http.StatusOK, serve(mux, "/debug/pprof/goroutine", "10.1.2.3:5000", bearer).Code)
+	assert.Equal(t, http.StatusOK, serve(mux, "/debug/pprof/goroutine", "[::1]:5000", bearer).Code)
+	assert.Equal(t, http.StatusOK, serve(mux, "/debug/pprof/goroutine?token=secret", "10.1.2.3:5000", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(mux, "/debug/pprof/goroutine", "10.1.2.3:5000", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(mux, "/debug/pprof/goroutine?token=wrong", "10.1.2.3:5000", nil).Code)
+
+	spoofed := func(r *http.Request) {
+		bearer(r)
+		r.Header.Set("X-Forwarded-For", "10.0.0.1")
+	}
+	assert.Equal(t, http.StatusForbidden, serve(mux, "/debug/pprof/goroutine", "192.168.1.1:5000", spoofed).Code)
+
+	basic := Default().Enable().WithBasicAuth("admin", "pass")
+	require.NoError(t, h.Reconfigure(basic))
+	rec := serve(mux, "/debug/pprof/goroutine", "192.168.1.1:5000", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Basic realm="pprof"`, rec.Header().Get("WWW-Authenticate"))
+	ok := func(r *http.Request) { r.SetBasicAuth("admin", "pass") }
+	assert.Equal(t, http.StatusOK, serve(mux, "/debug/pprof/goroutine", "192.168.1.1:5000", ok).Code)
+
+	_, err = Register(http.NewServeMux(), Default().Enable().WithAllowedIPs([]string{"not-an-ip"}).WithAuthToken("x"))
+	assert.Error(t, err)
+}
+
+func TestRegister_SamplingReset(t *testing.T) {
+	origMem := runtime.MemProfileRate
+	origMutex := runtime.SetMutexProfileFraction(-1)
+
+	cfg := Default().Enable().EnableMutexProfile().EnableBlockProfile().WithSampling(100, 4096, 1, 5)
+	h, err := Register(http.NewServeMux(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 4096, runtime.MemProfileRate)
+	assert.Equal(t, 5, runtime.SetMutexProfileFraction(-1))
+
+	require.NoError(t, h.Reconfigure(cfg.Disable()))
+	assert.Equal(t, origMem, runtime.MemProfileRate)
+	assert.Equal(t, origMutex, runtime.SetMutexProfileFraction(-1))
+
+	mux := http.NewServeMux()
+	disabled, err := Register(mux, Default())
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, serve(mux, "/debug/pprof/", "127.0.0.1:1", nil).Code)
+	assert.Equal(t, origMem, runtime.MemProfileRate, "未启用时不修改采样率")
+
+	require.NoError(t, disabled.Reconfigure(Default().Enable()))
+	assert.Equal(t, http.StatusOK, serve(mux, "/debug/pprof/", "127.0.0.1:1", nil).Code)
+	require.NoError(t, disabled.Reconfigure(Default()))
+	assert.Equal(t, origMem, runtime.MemProfileRate)
+}
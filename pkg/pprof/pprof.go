@@ -33,6 +33,8 @@ type PProf struct {
 type AuthConfig struct {
 	Enabled     bool     `mapstructure:"enabled" yaml:"enabled" json:"enabled"`               // 是否启用认证
 	AuthToken   string   `mapstructure:"auth-token" yaml:"auth-token" json:"authToken"`       // 认证令牌
+	Username    string   `mapstructure:"username" yaml:"username" json:"username"`            // Basic 认证用户名
+	Password    string   `mapstructure:"password" yaml:"password" json:"password"`            // Basic 认证密码
 	AllowedIPs  []string `mapstructure:"allowed-ips" yaml:"allowed-ips" json:"allowedIps"`    // 允许的IP列表
 	RequireAuth bool     `mapstructure:"require-auth" yaml:"require-auth" json:"requireAuth"` // 是否需要认证
 	Timeout     int      `mapstructure:"timeout" yaml:"timeout" json:"timeout"`               // 认证超时时间(秒)
@@ -257,6 +259,20 @@ func (c *PProf) WithAuthToken(token string) *PProf {
 	return c
 }
 
+// WithBasicAuth 设置 Basic 认证
+func (c *PProf) WithBasicAuth(username, password string) *PProf {
+	if c.Authentication == nil {
+		c.Authentication = &AuthConfig{}
+	}
+	c.Authentication.Username = username
+	c.Authentication.Password = password
+	if username != "" {
+		c.Authentication.RequireAuth = true
+		c.Authentication.Enabled = true
+	}
+	return c
+}
+
 // WithAllowedIPs 设置允许的IP列表
 func (c *PProf) WithAllowedIPs(ips []string) *PProf {
 	if c.Authentication == nil {
@@ -0,0 +1,160 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\ratelimit\alert.go
+ * @Description: 风控预警与封禁通知，基于 alerting 统一分发器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/alerting"
+	"github.com/kamalyes/go-config/pkg/smtp"
+)
+
+// AlertData 风控邮件模板数据，对应 TemplateAlert / TemplateBlock 中的字段
+type AlertData struct {
+	AppName          string // 为空时取配置
+	Environment      string // 为空时取配置
+	UserID           string
+	UserType         string
+	MinuteCount      int // 当前每分钟请求数
+	HourCount        int // 当前每小时请求数
+	AlertThreshold   int // 预警阈值（条/分钟）
+	BlockThreshold   int // 封禁阈值（条/分钟）
+	AlertPercentage  int // 达到封禁阈值的百分比，为 0 时自动计算
+	ExceedPercentage int // 超出封禁阈值的百分比，为 0 时自动计算
+	Timestamp        string
+	SupportEmail     string // 为空时取配置
+	SecurityEmail    string // 为空时取配置
+}
+
+// Validate 校验邮件预警配置，模板需可解析
+func (c *EmailAlertConfig) Validate() error {
+	var errs []error
+	if c.Enabled && len(c.To) == 0 {
+		errs = append(errs, errors.New("email alert requires at least one recipient"))
+	}
+	if c.CooldownMinutes < 0 {
+		errs = append(errs, fmt.Errorf("invalid cooldown-minutes %d", c.CooldownMinutes))
+	}
+	if c.MaxAlertsPerHour < 0 {
+		errs = append(errs, fmt.Errorf("invalid max-alerts-per-hour %d", c.MaxAlertsPerHour))
+	}
+	if _, err := alerting.NewMessageTemplate("alert", c.SubjectAlert, c.TemplateAlert); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := alerting.NewMessageTemplate("block", c.SubjectBlock, c.TemplateBlock); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// NewSMTPNotifier 使用 SMTP 模块的服务器配置创建邮件渠道，收件人与抄送取预警配置，
+// 预警配置未设置收件人时使用 SMTP 模块的收件人
+func (c *EmailAlertConfig) NewSMTPNotifier(s *smtp.Smtp) *alerting.SMTPNotifier {
+	to := c.To
+	if len(to) == 0 {
+		to = s.ToAddresses
+	}
+	n := alerting.NewSMTPNotifier(s.ModuleName, s.SMTPHost, s.SMTPPort, s.Username, s.Password, s.FromAddress, to, s.EnableTLS)
+	n.Cc = c.CC
+	return n
+}
+
+// EmailAlerter 风控通知器，渲染预警/封禁模板并按用户限制发送频率
+type EmailAlerter struct {
+	config     *EmailAlertConfig
+	alert      *alerting.MessageTemplate
+	block      *alerting.MessageTemplate
+	dispatcher *alerting.Dispatcher
+	now        func() time.Time
+}
+
+// NewEmailAlerter 解析模板并创建分发器，冷却与每小时限额取自 CooldownMinutes 与 MaxAlertsPerHour
+//
+// notifiers 通常为 NewSMTPNotifier 创建的邮件渠道，也可以是 alerting 的任意渠道
+func NewEmailAlerter(cfg *EmailAlertConfig, notifiers ...alerting.Notifier) (*EmailAlerter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	alert, _ := alerting.NewMessageTemplate("alert", cfg.SubjectAlert, cfg.TemplateAlert)
+	block, _ := alerting.NewMessageTemplate("block", cfg.SubjectBlock, cfg.TemplateBlock)
+	dispatcher := alerting.NewDispatcher(notifiers...).
+		WithCooldown(time.Duration(cfg.CooldownMinutes) * time.Minute).
+		WithMaxPerHour(cfg.MaxAlertsPerHour)
+	return &EmailAlerter{config: cfg, alert: alert, block: block, dispatcher: dispatcher, now: time.Now}, nil
+}
+
+// WithClock 设置时钟，便于测试
+func (a *EmailAlerter) WithClock(now func() time.Time) *EmailAlerter {
+	a.now = now
+	a.dispatcher.WithClock(now)
+	return a
+}
+
+// Dispatcher 返回底层分发器，可追加渠道或调整重试
+func (a *EmailAlerter) Dispatcher() *alerting.Dispatcher {
+	return a.dispatcher
+}
+
+// SendAlert 发送预警通知，同一用户受冷却与每小时限额约束
+//
+// 配置未启用时不发送；被节流时返回包装 alerting.ErrCooldown / alerting.ErrHourlyLimit 的错误
+func (a *EmailAlerter) SendAlert(ctx context.Context, data AlertData) error {
+	return a.send(ctx, "alert", a.alert, data)
+}
+
+// SendBlock 发送封禁通知，与预警通知分别计算冷却与限额
+func (a *EmailAlerter) SendBlock(ctx context.Context, data AlertData) error {
+	return a.send(ctx, "block", a.block, data)
+}
+
+// send 补全模板数据、渲染并分发
+func (a *EmailAlerter) send(ctx context.Context, kind string, tmpl *alerting.MessageTemplate, data AlertData) error {
+	if !a.config.Enabled {
+		return nil
+	}
+	a.complete(&data)
+	msg, err := tmpl.Render(data)
+	if err != nil {
+		return fmt.Errorf("ratelimit %s notification: %w", kind, err)
+	}
+	return a.dispatcher.Send(ctx, kind+":"+data.UserType+":"+data.UserID, msg)
+}
+
+// complete 以配置与计数补全模板数据中的空字段
+func (a *EmailAlerter) complete(data *AlertData) {
+	if data.AppName == "" {
+		data.AppName = a.config.AppName
+	}
+	if data.Environment == "" {
+		data.Environment = a.config.Environment
+	}
+	if data.SupportEmail == "" {
+		data.SupportEmail = a.config.SupportEmail
+	}
+	if data.SecurityEmail == "" {
+		data.SecurityEmail = a.config.SecurityEmail
+	}
+	if data.Timestamp == "" {
+		data.Timestamp = a.now().Format(time.DateTime)
+	}
+	if data.BlockThreshold > 0 {
+		if data.AlertPercentage == 0 {
+			data.AlertPercentage = min(data.MinuteCount*100/data.BlockThreshold, 100)
+		}
+		if data.ExceedPercentage == 0 && data.MinuteCount > data.BlockThreshold {
+			data.ExceedPercentage = (data.MinuteCount - data.BlockThreshold) * 100 / data.BlockThreshold
+		}
+	}
+}
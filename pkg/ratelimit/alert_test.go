@@ -0,0 +1,172 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\ratelimit\alert_test.go
+ * @Description: 风控预警通知测试，使用本地 SMTP 替身
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/alerting"
+	"github.com/kamalyes/go-config/pkg/smtp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailSink 最小化的本地 SMTP 服务，记录收件人与邮件内容
+type mailSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	rcpts    [][]string
+	data     []string
+}
+
+func newMailSink(t *testing.T) *mailSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &mailSink{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *mailSink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP")
+
+	var rcpts []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			rcpts = append(rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, rcpts)
+			s.data = append(s.data, data.String())
+			s.mu.Unlock()
+			rcpts = nil
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *mailSink) mails() ([][]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.rcpts...), append([]string(nil), s.data...)
+}
+
+func TestEmailAlerter(t *testing.T) {
+	sink := newMailSink(t)
+	server := smtp.Default()
+	server.SMTPPort = sink.listener.Addr().(*net.TCPAddr).Port
+	server.Username = ""
+
+	cfg := DefaultEmailAlertConfig()
+	cfg.Enabled = true
+	cfg.To = []string{"ops@example.com"}
+	cfg.CC = []string{"lead@example.com"}
+	cfg.AppName = "IM"
+	cfg.MaxAlertsPerHour = 2
+
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	alerter, err := NewEmailAlerter(cfg, cfg.NewSMTPNotifier(server))
+	require.NoError(t, err)
+	alerter.WithClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	data := AlertData{UserID: "u-1", UserType: "vip", MinuteCount: 80, HourCount: 900, AlertThreshold: 60, BlockThreshold: 100}
+	require.NoError(t, alerter.SendAlert(ctx, data))
+	rcpts, bodies := sink.mails()
+	require.Len(t, bodies, 1)
+	assert.Equal(t, []string{"ops@example.com", "lead@example.com"}, rcpts[0])
+	assert.Contains(t, bodies[0], "Subject: "+mime.BEncoding.Encode("UTF-8", "⚠️ 风控预警 - IM"))
+	assert.Contains(t, bodies[0], "<td>u-1</td>")
+	assert.Contains(t, bodies[0], "width: 80%")
+	assert.Contains(t, bodies[0], "2026-10-19 08:00:00")
+
+	// 同一用户冷却中，封禁通知单独计算
+	assert.ErrorIs(t, alerter.SendAlert(ctx, data), alerting.ErrCooldown)
+	data.MinuteCount = 150
+	require.NoError(t, alerter.SendBlock(ctx, data))
+	_, bodies = sink.mails()
+	require.Len(t, bodies, 2)
+	assert.Contains(t, bodies[1], "超限 50%")
+
+	// 冷却结束后受每小时限额约束
+	now = now.Add(5 * time.Minute)
+	require.NoError(t, alerter.SendAlert(ctx, data))
+	now = now.Add(5 * time.Minute)
+	assert.ErrorIs(t, alerter.SendAlert(ctx, data), alerting.ErrHourlyLimit)
+
+	cfg.Enabled = false
+	assert.NoError(t, alerter.SendAlert(ctx, AlertData{UserID: "u-2"}))
+	_, bodies = sink.mails()
+	assert.Len(t, bodies, 3)
+}
+
+func TestEmailAlertConfig_Validate(t *testing.T) {
+	assert.NoError(t, DefaultEmailAlertConfig().Validate())
+
+	cfg := DefaultEmailAlertConfig()
+	cfg.Enabled = true
+	cfg.To = nil
+	cfg.CooldownMinutes = -1
+	cfg.TemplateBlock = "{{.UserID"
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires at least one recipient")
+	assert.Contains(t, err.Error(), "invalid cooldown-minutes -1")
+	assert.Contains(t, err.Error(), `invalid body template "block"`)
+
+	r := Default()
+	r.EmailAlertConfig = cfg
+	assert.ErrorContains(t, r.Validate(), "email-alert-config:")
+	_, err = NewEmailAlerter(cfg)
+	assert.Error(t, err)
+}
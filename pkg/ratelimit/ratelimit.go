@@ -12,6 +12,7 @@
 package ratelimit
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kamalyes/go-config/internal"
@@ -161,7 +162,15 @@ func (r *RateLimit) Set(data interface{}) {
 
 // Validate 验证配置
 func (r *RateLimit) Validate() error {
-	return internal.ValidateStruct(r)
+	if err := internal.ValidateStruct(r); err != nil {
+		return err
+	}
+	if r.EmailAlertConfig != nil {
+		if err := r.EmailAlertConfig.Validate(); err != nil {
+			return fmt.Errorf("email-alert-config: %w", err)
+		}
+	}
+	return nil
 }
 
 // WithGlobalLimit 设置全局限流
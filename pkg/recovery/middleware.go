@@ -0,0 +1,247 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\recovery\middleware.go
+ * @Description: panic 恢复 HTTP 中间件与 gRPC 拦截器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Logger 恢复日志输出，go-logger 的 *logger.Logger 满足该接口
+type Logger interface {
+	Debug(format string, args ...interface{})
+	Info(format string, args ...interface{})
+	Warn(format string, args ...interface{})
+	Error(format string, args ...interface{})
+}
+
+// PanicInfo panic 详情
+type PanicInfo struct {
+	Value  interface{} // panic 值
+	Stack  []byte      // 按 StackSize 截断后的堆栈，未启用堆栈时为空
+	Method string      // HTTP 方法，gRPC 调用为空
+	Path   string      // HTTP 路径或 gRPC 完整方法名
+	Time   time.Time
+}
+
+// Notifier panic 通知钩子，EnableNotify 时在独立协程中调用
+type Notifier func(ctx context.Context, info *PanicInfo)
+
+// Middleware panic 恢复中间件
+type Middleware struct {
+	config   *Recovery
+	level    string
+	logger   Logger
+	notifier Notifier
+}
+
+// NewMiddleware 校验日志级别并创建中间件，默认使用 go-logger 全局日志
+func NewMiddleware(cfg *Recovery) (*Middleware, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("recovery config is nil")
+	}
+	level := strings.ToLower(strings.TrimSpace(cfg.LogLevel))
+	switch level {
+	case "":
+		level = "error"
+	case "debug", "info", "warn", "error":
+	case "warning":
+		level = "warn"
+	default:
+		return nil, fmt.Errorf("unsupported recovery log level: %s", cfg.LogLevel)
+	}
+	config := cfg.Clone().(*Recovery)
+	config.RecoveryHandler = cfg.RecoveryHandler // 深拷贝不复制函数字段
+	return &Middleware{config: config, level: level, logger: globalLogger{}}, nil
+}
+
+// WithLogger 设置日志输出，按 LogLevel 选择级别
+func (m *Middleware) WithLogger(logger Logger) *Middleware {
+	m.logger = logger
+	return m
+}
+
+// WithNotifier 设置通知钩子
+func (m *Middleware) WithNotifier(notifier Notifier) *Middleware {
+	m.notifier = notifier
+	return m
+}
+
+// Handler 包装处理器：panic 时记录日志并返回 500
+//
+// 设置了 RecoveryHandler 时由其生成响应；EnableDebug 时响应中附带 panic 值与堆栈；
+// 响应已开始写出时仅记录日志。http.ErrAbortHandler 按标准库约定继续向上抛出
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	if !m.config.Enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			info := m.handle(r.Context(), p, r.Method, r.URL.Path)
+			if rw.written {
+				return
+			}
+			if m.config.RecoveryHandler != nil {
+				m.config.RecoveryHandler(w, r, p)
+				return
+			}
+			http.Error(w, m.message(info), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// UnaryServerInterceptor 一元调用拦截器：panic 时返回 Internal 错误
+func (m *Middleware) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		if !m.config.Enabled {
+			return handler(ctx, req)
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				err = status.Error(codes.Internal, m.message(m.handle(ctx, p, "", info.FullMethod)))
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 流式调用拦截器：panic 时返回 Internal 错误
+func (m *Middleware) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		if !m.config.Enabled {
+			return handler(srv, ss)
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				err = status.Error(codes.Internal, m.message(m.handle(ss.Context(), p, "", info.FullMethod)))
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// handle 采集堆栈、记录日志并触发通知
+func (m *Middleware) handle(ctx context.Context, p interface{}, method, path string) *PanicInfo {
+	info := &PanicInfo{Value: p, Method: method, Path: path, Time: time.Now()}
+	if m.config.EnableStack || m.config.PrintStack {
+		info.Stack = debug.Stack()
+		if size := m.config.StackSize; size > 0 && len(info.Stack) > size {
+			info.Stack = info.Stack[:size]
+		}
+	}
+
+	target := strings.TrimSpace(method + " " + path)
+	if len(info.Stack) > 0 {
+		m.log("panic recovered: %s: %v\n%s", target, p, info.Stack)
+	} else {
+		m.log("panic recovered: %s: %v", target, p)
+	}
+	if m.config.EnableNotify && m.notifier != nil {
+		go m.notifier(context.WithoutCancel(ctx), info)
+	}
+	return info
+}
+
+// log 按配置的级别输出日志
+func (m *Middleware) log(format string, args ...interface{}) {
+	switch m.level {
+	case "debug":
+		m.logger.Debug(format, args...)
+	case "info":
+		m.logger.Info(format, args...)
+	case "warn":
+		m.logger.Warn(format, args...)
+	default:
+		m.logger.Error(format, args...)
+	}
+}
+
+// message 返回响应消息，EnableDebug 时附带 panic 值与堆栈
+func (m *Middleware) message(info *PanicInfo) string {
+	msg := m.config.ErrorMessage
+	if msg == "" {
+		msg = http.StatusText(http.StatusInternalServerError)
+	}
+	if !m.config.EnableDebug {
+		return msg
+	}
+	msg = fmt.Sprintf("%s: %v", msg, info.Value)
+	if len(info.Stack) > 0 {
+		msg += "\n" + string(info.Stack)
+	}
+	return msg
+}
+
+// responseWriter 记录响应是否已开始写出
+type responseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// globalLogger 默认日志输出，每次调用时获取 go-logger 全局实例，跟随全局日志的替换与配置
+type globalLogger struct{}
+
+func (globalLogger) Debug(format string, args ...interface{}) {
+	logger.GetGlobalLogger().Debug(format, args...)
+}
+
+func (globalLogger) Info(format string, args ...interface{}) {
+	logger.GetGlobalLogger().Info(format, args...)
+}
+
+func (globalLogger) Warn(format string, args ...interface{}) {
+	logger.GetGlobalLogger().Warn(format, args...)
+}
+
+func (globalLogger) Error(format string, args ...interface{}) {
+	logger.GetGlobalLogger().Error(format, args...)
+}
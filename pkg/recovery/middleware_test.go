@@ -0,0 +1,204 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\recovery\middleware_test.go
+ * @Description: panic 恢复中间件与拦截器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package recovery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	golog "github.com/kamalyes/go-logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// recordLogger 记录日志级别与内容
+type recordLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordLogger) record(level, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level+" "+fmt.Sprintf(format, args...))
+}
+
+func (l *recordLogger) Debug(format string, args ...interface{}) { l.record("DEBUG", format, args...) }
+func (l *recordLogger) Info(format string, args ...interface{})  { l.record("INFO", format, args...) }
+func (l *recordLogger) Warn(format string, args ...interface{})  { l.record("WARN", format, args...) }
+func (l *recordLogger) Error(format string, args ...interface{}) { l.record("ERROR", format, args...) }
+
+// panicHealth 所有调用均 panic
+type panicHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (panicHealth) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	panic("check exploded")
+}
+
+func (panicHealth) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	panic("watch exploded")
+}
+
+func panicking(value interface{}) http.Handler {
+	return http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(value) })
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	logger := &recordLogger{}
+	notified := make(chan *PanicInfo, 1)
+	m, err := NewMiddleware(Default())
+	require.NoError(t, err)
+	m.WithLogger(logger).WithNotifier(func(_ context.Context, info *PanicInfo) { notified <- info })
+
+	rec := httptest.NewRecorder()
+	m.Handler(panicking("boom")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "服务器内部错误\n", rec.Body.String())
+	require.Len(t, logger.lines, 1)
+	assert.Contains(t, logger.lines[0], "ERROR panic recovered: GET /orders: boom")
+	assert.Contains(t, logger.lines[0], "runtime/debug.Stack")
+	select {
+	case <-notified:
+		t.Fatal("未启用通知时不应调用通知钩子")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cfg := Default().WithNotify(true).WithEnableDebug(true).WithStackSize(64).WithLogLevel("warn")
+	m, err = NewMiddleware(cfg)
+	require.NoError(t, err)
+	logger = &recordLogger{}
+	m.WithLogger(logger).WithNotifier(func(_ context.Context, info *PanicInfo) { notified <- info })
+
+	rec = httptest.NewRecorder()
+	m.Handler(panicking("kaboom")).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pay", nil))
+	assert.Contains(t, rec.Body.String(), "服务器内部错误: kaboom\n")
+	assert.Contains(t, logger.lines[0], "WARN panic recovered: POST /pay: kaboom")
+	select {
+	case info := <-notified:
+		assert.Equal(t, "kaboom", info.Value)
+		assert.Equal(t, http.MethodPost, info.Method)
+		assert.Equal(t, "/pay", info.Path)
+		assert.Len(t, info.Stack, 64)
+	case <-time.After(time.Second):
+		t.Fatal("通知钩子未被调用")
+	}
+
+	_, err = NewMiddleware(Default().WithLogLevel("fatal"))
+	assert.Error(t, err)
+}
+
+func TestMiddleware_HandlerResponses(t *testing.T) {
+	cfg := Default().WithRecoveryHandler(func(w http.ResponseWriter, _ *http.Request, p interface{}) {
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprintf(w, "custom: %v", p)
+	})
+	m, err := NewMiddleware(cfg)
+	require.NoError(t, err)
+	m.WithLogger(&recordLogger{})
+
+	rec := httptest.NewRecorder()
+	m.Handler(panicking("oops")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "custom: oops", rec.Body.String())
+
+	noStack, err := NewMiddleware(Default().WithPrintStack(false).WithEnableDebug(true))
+	require.NoError(t, err)
+	logger := &recordLogger{}
+	noStack.WithLogger(logger)
+	rec = httptest.NewRecorder()
+	noStack.Handler(panicking("plain")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "服务器内部错误: plain\n", rec.Body.String())
+	assert.Equal(t, []string{"ERROR panic recovered: GET /: plain"}, logger.lines)
+
+	rec = httptest.NewRecorder()
+	m.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late")
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code, "响应已写出时不再覆盖")
+	assert.Empty(t, rec.Body.String())
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		m.Handler(panicking(http.ErrAbortHandler)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	disabled, err := NewMiddleware(Default().Disable())
+	require.NoError(t, err)
+	assert.Panics(t, func() {
+		disabled.Handler(panicking("x")).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestMiddleware_GRPC(t *testing.T) {
+	logger := &recordLogger{}
+	m, err := NewMiddleware(Default().WithErrorMessage("internal error"))
+	require.NoError(t, err)
+	m.WithLogger(logger)
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(m.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(m.StreamServerInterceptor()),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, panicHealth{})
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	require.Len(t, logger.lines, 2)
+	assert.Contains(t, logger.lines[0], "panic recovered: /grpc.health.v1.Health/Check: check exploded")
+	assert.Contains(t, logger.lines[1], "panic recovered: /grpc.health.v1.Health/Watch: watch exploded")
+}
+
+func TestMiddleware_DefaultGlobalLogger(t *testing.T) {
+	var buf bytes.Buffer
+	golog.GetGlobalLogger().WithOutput(&buf)
+	defer golog.GetGlobalLogger().WithOutput(os.Stdout)
+
+	m, err := NewMiddleware(Default().WithPrintStack(false))
+	require.NoError(t, err)
+	m.Handler(panicking("global")).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/g", nil))
+	assert.Contains(t, buf.String(), "panic recovered: GET /g: global", "默认写入 go-logger 全局日志")
+}
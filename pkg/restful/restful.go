@@ -12,7 +12,10 @@
 package restful
 
 import (
+	"strconv"
+
 	"github.com/kamalyes/go-config/internal"
+	"github.com/kamalyes/go-config/pkg/cors"
 	"github.com/kamalyes/go-toolbox/pkg/syncx"
 )
 
@@ -299,3 +302,23 @@ func (r *Restful) Disable() *Restful {
 	r.Enabled = false
 	return r
 }
+
+// ToCors 转换为 cors.Cors，可直接用于 cors.NewHandler
+func (c *CORS) ToCors() *cors.Cors {
+	if c == nil {
+		return nil
+	}
+	cfg := &cors.Cors{
+		ModuleName:       "cors",
+		Enabled:          c.Enabled,
+		AllowedOrigins:   append([]string(nil), c.AllowOrigins...),
+		AllowedMethods:   append([]string(nil), c.AllowMethods...),
+		AllowedHeaders:   append([]string(nil), c.AllowHeaders...),
+		ExposedHeaders:   append([]string(nil), c.ExposeHeaders...),
+		AllowCredentials: c.AllowCredentials,
+	}
+	if c.MaxAge > 0 {
+		cfg.MaxAge = strconv.Itoa(c.MaxAge)
+	}
+	return cfg
+}
@@ -222,3 +222,22 @@ func TestRestful_ChainedCalls(t *testing.T) {
 	assert.True(t, config.Static.Enabled)
 	assert.True(t, config.Enabled)
 }
+
+func TestCORS_ToCors(t *testing.T) {
+	var empty *CORS
+	assert.Nil(t, empty.ToCors())
+
+	config := Default().EnableCORS()
+	config.CORS.AllowOrigins = []string{"https://*.example.com"}
+	config.CORS.ExposeHeaders = []string{"X-Request-ID"}
+
+	c := config.CORS.ToCors()
+	assert.True(t, c.Enabled)
+	assert.Equal(t, []string{"https://*.example.com"}, c.AllowedOrigins)
+	assert.Equal(t, []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}, c.AllowedMethods)
+	assert.Equal(t, []string{"X-Request-ID"}, c.ExposedHeaders)
+	assert.Equal(t, "3600", c.MaxAge)
+
+	c.AllowedOrigins[0] = "changed"
+	assert.Equal(t, "https://*.example.com", config.CORS.AllowOrigins[0], "转换结果不共享切片")
+}
@@ -34,6 +34,7 @@ type Signature struct {
 	Type             SignatureType       `mapstructure:"type" yaml:"type" json:"type"`                                       // 签名类型 (hmac/rsa)
 	SecretKey        string              `mapstructure:"secret-key" yaml:"secret-key" json:"secretKey"`                      // HMAC 签名密钥
 	PublicKeyPEM     string              `mapstructure:"public-key-pem" yaml:"public-key-pem" json:"publicKeyPem"`           // RSA 公钥 PEM（用于验证签名）
+	PrivateKeyPEM    string              `mapstructure:"private-key-pem" yaml:"private-key-pem" json:"privateKeyPem"`        // RSA 私钥 PEM（客户端签名使用）
 	NonceKeyPrefix   string              `mapstructure:"nonce-key-prefix" yaml:"nonce-key-prefix" json:"nonceKeyPrefix"`     // Nonce Redis key 前缀
 	NonceTTL         time.Duration       `mapstructure:"nonce-ttl" yaml:"nonce-ttl" json:"nonceTtl"`                         // Nonce 过期时间
 	RequireTimestamp bool                `mapstructure:"require-timestamp" yaml:"require-timestamp" json:"requireTimestamp"` // 是否强制要求 Timestamp（向后兼容：false 允许旧客户端不传）
@@ -46,6 +47,7 @@ type Signature struct {
 	RequiredHeaders  []string            `mapstructure:"required-headers" yaml:"required-headers" json:"requiredHeaders"`    // 签名校验必须包含的 headers
 	TimestampFormat  string              `mapstructure:"timestamp-format" yaml:"timestamp-format" json:"timestampFormat"`    // 时间戳格式
 	SkipBody         bool                `mapstructure:"skip-body" yaml:"skip-body" json:"skipBody"`                         // 是否跳过请求体
+	MaxBodySize      int64               `mapstructure:"max-body-size" yaml:"max-body-size" json:"maxBodySize"`              // 参与签名的请求体最大字节数，<=0 表示不限制
 }
 
 // Default 创建默认签名验证配置
@@ -84,6 +86,7 @@ func Default() *Signature {
 		TimestampFormat: "unix",
 		SkipQuery:       false,
 		SkipBody:        false,
+		MaxBodySize:     10 << 20, // 10MB
 	}
 }
 
@@ -120,6 +123,24 @@ func (s *Signature) WithSecretKey(secretKey string) *Signature {
 	return s
 }
 
+// WithType 设置签名类型
+func (s *Signature) WithType(signatureType SignatureType) *Signature {
+	s.Type = signatureType
+	return s
+}
+
+// WithPublicKeyPEM 设置 RSA 公钥
+func (s *Signature) WithPublicKeyPEM(publicKeyPEM string) *Signature {
+	s.PublicKeyPEM = publicKeyPEM
+	return s
+}
+
+// WithPrivateKeyPEM 设置 RSA 私钥
+func (s *Signature) WithPrivateKeyPEM(privateKeyPEM string) *Signature {
+	s.PrivateKeyPEM = privateKeyPEM
+	return s
+}
+
 // WithAlgorithm 设置签名算法
 func (s *Signature) WithAlgorithm(algorithm sign.HashCryptoFunc) *Signature {
 	s.Algorithm = algorithm
@@ -168,6 +189,12 @@ func (s *Signature) WithSkipBody(skip bool) *Signature {
 	return s
 }
 
+// WithMaxBodySize 设置参与签名的请求体最大字节数
+func (s *Signature) WithMaxBodySize(size int64) *Signature {
+	s.MaxBodySize = size
+	return s
+}
+
 // Enable 启用签名验证中间件
 func (s *Signature) Enable() *Signature {
 	s.Enabled = true
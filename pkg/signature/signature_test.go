@@ -33,6 +33,7 @@ func TestSignature_Default(t *testing.T) {
 	assert.Equal(t, "unix", config.TimestampFormat)
 	assert.False(t, config.SkipQuery)
 	assert.False(t, config.SkipBody)
+	assert.Equal(t, int64(10<<20), config.MaxBodySize)
 }
 
 func TestSignature_WithSecretKey(t *testing.T) {
@@ -92,6 +93,13 @@ func TestSignature_WithSkipBody(t *testing.T) {
 	assert.Equal(t, config, result)
 }
 
+func TestSignature_WithMaxBodySize(t *testing.T) {
+	config := Default()
+	result := config.WithMaxBodySize(1024)
+	assert.Equal(t, int64(1024), result.MaxBodySize)
+	assert.Equal(t, config, result)
+}
+
 func TestSignature_Enable(t *testing.T) {
 	config := Default()
 	result := config.Enable()
@@ -0,0 +1,286 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\signature\signer.go
+ * @Description: 请求规范化字符串与客户端签名器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	_ "crypto/md5" // 注册 crypto.MD5
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1"   // 注册 crypto.SHA1
+	_ "crypto/sha256" // 注册 crypto.SHA224、crypto.SHA256
+	_ "crypto/sha512" // 注册 crypto.SHA384、crypto.SHA512
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/sign"
+)
+
+// 签名相关请求头
+const (
+	HeaderSignature = "X-Signature" // 签名（Base64）
+	HeaderTimestamp = "X-Timestamp" // 时间戳，格式由 TimestampFormat 决定
+	HeaderNonce     = "X-Nonce"     // 随机数，用于防重放
+)
+
+// 时间戳格式
+const (
+	TimestampUnix      = "unix"       // Unix 秒
+	TimestampUnixMilli = "unix-milli" // Unix 毫秒
+)
+
+// cryptoHashes 签名算法到 crypto.Hash 的映射
+var cryptoHashes = map[sign.HashCryptoFunc]crypto.Hash{
+	sign.AlgorithmMD5:    crypto.MD5,
+	sign.AlgorithmSHA1:   crypto.SHA1,
+	sign.AlgorithmSHA224: crypto.SHA224,
+	sign.AlgorithmSHA256: crypto.SHA256,
+	sign.AlgorithmSHA384: crypto.SHA384,
+	sign.AlgorithmSHA512: crypto.SHA512,
+}
+
+// scheme 签名器与验签器共用的规范化规则
+type scheme struct {
+	cfg           *Signature
+	hash          crypto.Hash
+	signedHeaders []string
+}
+
+// newScheme 校验签名类型与算法，并计算参与签名的请求头
+func newScheme(cfg *Signature) (*scheme, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("signature config is nil")
+	}
+	cfg = cfg.Clone().(*Signature)
+	switch cfg.Type {
+	case "", SignatureTypeHMAC, SignatureTypeRSA:
+	default:
+		return nil, fmt.Errorf("unsupported signature type: %s", cfg.Type)
+	}
+	algorithm := cfg.Algorithm
+	if algorithm == "" {
+		algorithm = sign.AlgorithmSHA256
+	}
+	h, ok := cryptoHashes[sign.HashCryptoFunc(strings.ToUpper(string(algorithm)))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm: %s", cfg.Algorithm)
+	}
+
+	s := &scheme{cfg: cfg, hash: h}
+	protocol := []string{HeaderSignature, HeaderTimestamp, HeaderNonce}
+	for _, name := range cfg.RequiredHeaders {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if name == "" || slices.Contains(protocol, name) || slices.Contains(s.signedHeaders, name) ||
+			slices.ContainsFunc(cfg.SkipHeaders, func(skip string) bool { return strings.EqualFold(skip, name) }) {
+			continue
+		}
+		s.signedHeaders = append(s.signedHeaders, name)
+	}
+	slices.Sort(s.signedHeaders)
+	return s, nil
+}
+
+// isRSA 是否使用 RSA 签名
+func (s *scheme) isRSA() bool {
+	return s.cfg.Type == SignatureTypeRSA
+}
+
+// Canonical 构建规范化字符串，各部分以换行分隔：
+//
+//	METHOD
+//	PATH（转义后的路径）
+//	按键排序并编码的查询参数（SkipQuery 时为空）
+//	请求体摘要的十六进制（SkipBody 时为空）
+//	TIMESTAMP
+//	NONCE
+//	参与签名的请求头，每行 `小写名称:值`，按名称排序
+//
+// 参与签名的请求头为 RequiredHeaders 中除 SkipHeaders 与签名协议头之外的部分
+func (s *scheme) Canonical(r *http.Request, body []byte, timestamp, nonce string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToUpper(r.Method))
+	b.WriteByte('\n')
+	b.WriteString(r.URL.EscapedPath())
+	b.WriteByte('\n')
+	if !s.cfg.SkipQuery {
+		b.WriteString(r.URL.Query().Encode())
+	}
+	b.WriteByte('\n')
+	if !s.cfg.SkipBody {
+		digest := s.hash.New()
+		digest.Write(body)
+		b.WriteString(hex.EncodeToString(digest.Sum(nil)))
+	}
+	b.WriteByte('\n')
+	b.WriteString(timestamp)
+	b.WriteByte('\n')
+	b.WriteString(nonce)
+	for _, name := range s.signedHeaders {
+		b.WriteByte('\n')
+		b.WriteString(strings.ToLower(name))
+		b.WriteByte(':')
+		b.WriteString(strings.TrimSpace(r.Header.Get(name)))
+	}
+	return b.String()
+}
+
+// formatTimestamp 按 TimestampFormat 格式化时间，未识别的格式视为 Go 时间布局
+func (s *scheme) formatTimestamp(t time.Time) string {
+	switch s.cfg.TimestampFormat {
+	case "", TimestampUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimestampUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.Format(s.cfg.TimestampFormat)
+	}
+}
+
+// parseTimestamp 按 TimestampFormat 解析时间戳
+func (s *scheme) parseTimestamp(value string) (time.Time, error) {
+	switch s.cfg.TimestampFormat {
+	case "", TimestampUnix, TimestampUnixMilli:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if s.cfg.TimestampFormat == TimestampUnixMilli {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	default:
+		return time.Parse(s.cfg.TimestampFormat, value)
+	}
+}
+
+// readBody 读取请求体并放回，使后续处理器仍可读取
+//
+// 最多读取 MaxBodySize 字节，超出时返回 ErrBodyTooLarge，避免验签前缓冲任意大小的请求体
+func (s *scheme) readBody(r *http.Request) ([]byte, error) {
+	if s.cfg.SkipBody || r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	reader := io.Reader(r.Body)
+	if s.cfg.MaxBodySize > 0 {
+		reader = io.LimitReader(r.Body, s.cfg.MaxBodySize+1)
+	}
+	body, err := io.ReadAll(reader)
+	r.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if s.cfg.MaxBodySize > 0 && int64(len(body)) > s.cfg.MaxBodySize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, s.cfg.MaxBodySize)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	return body, nil
+}
+
+// Signer 客户端请求签名器
+type Signer struct {
+	*scheme
+	secret     []byte
+	privateKey *rsa.PrivateKey
+	now        func() time.Time
+	nonce      func() string
+}
+
+// NewSigner 创建签名器：HMAC 使用 SecretKey，RSA 使用 PrivateKeyPEM
+func NewSigner(cfg *Signature) (*Signer, error) {
+	s, err := newScheme(cfg)
+	if err != nil {
+		return nil, err
+	}
+	signer := &Signer{scheme: s, now: time.Now, nonce: randomNonce}
+	if s.isRSA() {
+		if signer.privateKey, err = sign.ParsePrivateKey([]byte(cfg.PrivateKeyPEM)); err != nil {
+			return nil, fmt.Errorf("invalid rsa private key: %w", err)
+		}
+	} else {
+		if cfg.SecretKey == "" {
+			return nil, fmt.Errorf("secret key is required for hmac signature")
+		}
+		signer.secret = []byte(cfg.SecretKey)
+	}
+	return signer, nil
+}
+
+// WithClock 设置时间源，便于测试
+func (s *Signer) WithClock(now func() time.Time) *Signer {
+	s.now = now
+	return s
+}
+
+// WithNonceGenerator 设置随机数生成函数
+func (s *Signer) WithNonceGenerator(nonce func() string) *Signer {
+	s.nonce = nonce
+	return s
+}
+
+// Sign 为请求写入 X-Timestamp、X-Nonce 与 X-Signature
+//
+// 请求体会被读取后放回；RequiredHeaders 中参与签名的请求头须在调用前设置
+func (s *Signer) Sign(r *http.Request) error {
+	for _, name := range s.signedHeaders {
+		if r.Header.Get(name) == "" {
+			return fmt.Errorf("missing required header %s", name)
+		}
+	}
+	body, err := s.readBody(r)
+	if err != nil {
+		return err
+	}
+
+	timestamp := s.formatTimestamp(s.now())
+	nonce := s.nonce()
+	signature, err := s.sign([]byte(s.Canonical(r, body, timestamp, nonce)))
+	if err != nil {
+		return err
+	}
+	r.Header.Set(HeaderTimestamp, timestamp)
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, signature)
+	return nil
+}
+
+// sign 计算规范化字符串的签名并以 Base64 编码
+func (s *Signer) sign(data []byte) (string, error) {
+	if s.privateKey == nil {
+		mac := hmac.New(s.hash.New, s.secret)
+		mac.Write(data)
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+	}
+	digest := s.hash.New()
+	digest.Write(data)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, s.hash, digest.Sum(nil))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign request: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// randomNonce 生成 16 字节随机数的十六进制字符串
+func randomNonce() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
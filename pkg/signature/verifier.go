@@ -0,0 +1,267 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\signature\verifier.go
+ * @Description: 服务端验签：时间窗口、Nonce 防重放与忽略路径
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package signature
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/sign"
+)
+
+// 验签失败原因，Verify 返回的错误可用 errors.Is 判断
+var (
+	ErrMissingSignature = errors.New("missing signature")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrMissingHeader    = errors.New("missing required header")
+	ErrMissingTimestamp = errors.New("missing timestamp")
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	ErrTimestampExpired = errors.New("timestamp outside allowed window")
+	ErrMissingNonce     = errors.New("missing nonce")
+	ErrReplayedNonce    = errors.New("nonce already used")
+	ErrBodyTooLarge     = errors.New("request body too large")
+)
+
+// defaultNonceTTL 未配置 NonceTTL 时 Nonce 的保留时间
+const defaultNonceTTL = 10 * time.Minute
+
+// NonceStore Nonce 存储，用于拒绝重放请求
+type NonceStore interface {
+	// CheckAndStore 记录 key 并保留 ttl；key 已存在且未过期时返回 false
+	CheckAndStore(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore 进程内带过期时间的 Nonce 存储，适用于单实例部署
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	items     map[string]time.Time
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryNonceStore 创建进程内 Nonce 存储
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{items: make(map[string]time.Time), now: time.Now}
+}
+
+// WithClock 设置时间源，便于测试
+func (s *MemoryNonceStore) WithClock(now func() time.Time) *MemoryNonceStore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+	return s
+}
+
+// CheckAndStore 实现 NonceStore，每经过一个 ttl 清理一次过期记录
+func (s *MemoryNonceStore) CheckAndStore(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= ttl {
+		for k, expire := range s.items {
+			if !now.Before(expire) {
+				delete(s.items, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	if expire, ok := s.items[key]; ok && now.Before(expire) {
+		return false, nil
+	}
+	s.items[key] = now.Add(ttl)
+	return true, nil
+}
+
+// Len 返回当前记录数（可能包含尚未清理的过期记录）
+func (s *MemoryNonceStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// Verifier 服务端请求验签器
+type Verifier struct {
+	*scheme
+	secret    []byte
+	publicKey *rsa.PublicKey
+	store     NonceStore
+	now       func() time.Time
+}
+
+// NewVerifier 创建验签器：HMAC 使用 SecretKey，RSA 使用 PublicKeyPEM
+//
+// nonceStore 为 nil 时使用 MemoryNonceStore
+func NewVerifier(cfg *Signature, nonceStore NonceStore) (*Verifier, error) {
+	s, err := newScheme(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if nonceStore == nil {
+		nonceStore = NewMemoryNonceStore()
+	}
+	v := &Verifier{scheme: s, store: nonceStore, now: time.Now}
+	if s.isRSA() {
+		if v.publicKey, err = sign.ParsePublicKey([]byte(cfg.PublicKeyPEM)); err != nil {
+			return nil, fmt.Errorf("invalid rsa public key: %w", err)
+		}
+	} else {
+		if cfg.SecretKey == "" {
+			return nil, fmt.Errorf("secret key is required for hmac signature")
+		}
+		v.secret = []byte(cfg.SecretKey)
+	}
+	return v, nil
+}
+
+// WithClock 设置时间源，便于测试
+func (v *Verifier) WithClock(now func() time.Time) *Verifier {
+	v.now = now
+	return v
+}
+
+// Ignored 检查路径是否在 IgnorePaths 中；以 `*` 结尾的规则按前缀匹配
+//
+// 匹配前按 path.Clean 规范化路径，避免 "/public/../admin" 这类路径借忽略规则绕过校验
+func (v *Verifier) Ignored(p string) bool {
+	p = cleanPath(p)
+	for _, pattern := range v.cfg.IgnorePaths {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(p, prefix) {
+				return true
+			}
+		} else if p == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanPath 规范化请求路径，保留末尾的斜杠
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// Verify 校验请求签名，忽略路径直接通过
+//
+// 依次检查必需请求头、时间窗口、签名，签名有效后才记录 Nonce，避免伪造请求消耗合法 Nonce。
+// RequireTimestamp/RequireNonce 为 false 时允许缺少对应请求头（按空字符串参与签名）
+func (v *Verifier) Verify(r *http.Request) error {
+	if v.Ignored(r.URL.Path) {
+		return nil
+	}
+	signature := r.Header.Get(HeaderSignature)
+	if signature == "" {
+		return ErrMissingSignature
+	}
+	for _, name := range v.cfg.RequiredHeaders {
+		if r.Header.Get(name) == "" {
+			return fmt.Errorf("%w: %s", ErrMissingHeader, name)
+		}
+	}
+
+	timestamp := r.Header.Get(HeaderTimestamp)
+	if timestamp == "" {
+		if v.cfg.RequireTimestamp {
+			return ErrMissingTimestamp
+		}
+	} else {
+		ts, err := v.parseTimestamp(timestamp)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidTimestamp, timestamp)
+		}
+		if window := v.cfg.TimeoutWindow; window > 0 {
+			if skew := v.now().Sub(ts).Abs(); skew > window {
+				return fmt.Errorf("%w: skew %s exceeds %s", ErrTimestampExpired, skew.Round(time.Second), window)
+			}
+		}
+	}
+	nonce := r.Header.Get(HeaderNonce)
+	if nonce == "" && v.cfg.RequireNonce {
+		return ErrMissingNonce
+	}
+
+	body, err := v.readBody(r)
+	if err != nil {
+		return err
+	}
+	if !v.verify([]byte(v.Canonical(r, body, timestamp, nonce)), signature) {
+		return ErrInvalidSignature
+	}
+
+	if nonce != "" {
+		ttl := v.cfg.NonceTTL
+		if ttl <= 0 {
+			ttl = defaultNonceTTL
+		}
+		// 时间戳在 ±TimeoutWindow 内均被接受，Nonce 至少保留整个区间，否则过期后可被重放
+		ttl = max(ttl, 2*v.cfg.TimeoutWindow)
+		fresh, err := v.store.CheckAndStore(r.Context(), v.cfg.NonceKeyPrefix+nonce, ttl)
+		if err != nil {
+			return fmt.Errorf("failed to check nonce: %w", err)
+		}
+		if !fresh {
+			return ErrReplayedNonce
+		}
+	}
+	return nil
+}
+
+// verify 校验 Base64 编码的签名
+func (v *Verifier) verify(data []byte, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	if v.publicKey == nil {
+		mac := hmac.New(v.hash.New, v.secret)
+		mac.Write(data)
+		return hmac.Equal(sig, mac.Sum(nil))
+	}
+	digest := v.hash.New()
+	digest.Write(data)
+	return rsa.VerifyPKCS1v15(v.publicKey, v.hash, digest.Sum(nil), sig) == nil
+}
+
+// Handler 验签中间件，未启用时直接放行，验签失败返回 401，请求体过大返回 413
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	if !v.cfg.Enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Verify(r); err != nil {
+			code := http.StatusUnauthorized
+			if errors.Is(err, ErrBodyTooLarge) {
+				code = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,329 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\signature\verifier_test.go
+ * @Description: 请求签名与验签测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package signature
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/sign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+func newRSAConfig(t *testing.T) *Signature {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privatePEM, err := sign.ExportRsaPrivateKeyToPEM(key)
+	require.NoError(t, err)
+	publicPEM, err := sign.ExportRsaPublicKeyToPEM(&key.PublicKey)
+	require.NoError(t, err)
+	return Default().Enable().WithType(SignatureTypeRSA).WithPrivateKeyPEM(privatePEM).WithPublicKeyPEM(publicPEM)
+}
+
+func newPair(t *testing.T, cfg *Signature) (*Signer, *Verifier, *MemoryNonceStore) {
+	t.Helper()
+	signer, err := NewSigner(cfg)
+	require.NoError(t, err)
+	store := NewMemoryNonceStore().WithClock(func() time.Time { return testNow })
+	verifier, err := NewVerifier(cfg, store)
+	require.NoError(t, err)
+	return signer.WithClock(func() time.Time { return testNow }), verifier.WithClock(func() time.Time { return testNow }), store
+}
+
+func newRequest(method, target, body string) *http.Request {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	r := httptest.NewRequest(method, target, reader)
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	configs := map[string]*Signature{
+		"hmac-sha256": Default().Enable().WithSecretKey("s3cr3t"),
+		"hmac-sha512": Default().Enable().WithSecretKey("s3cr3t").WithAlgorithm(sign.AlgorithmSHA512),
+		"rsa-sha256":  newRSAConfig(t),
+	}
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			signer, verifier, _ := newPair(t, cfg)
+
+			r := newRequest(http.MethodPost, "/api/orders?b=2&a=1&a=0", `{"amount":100}`)
+			require.NoError(t, signer.Sign(r))
+			assert.Equal(t, "1792411200", r.Header.Get(HeaderTimestamp))
+			assert.Len(t, r.Header.Get(HeaderNonce), 32)
+
+			// 请求体在签名后仍可读取
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"amount":100}`, string(body))
+
+			// 查询参数顺序不影响签名
+			server := newRequest(http.MethodPost, "/api/orders?a=1&a=0&b=2", `{"amount":100}`)
+			for _, h := range []string{HeaderSignature, HeaderTimestamp, HeaderNonce} {
+				server.Header.Set(h, r.Header.Get(h))
+			}
+			require.NoError(t, verifier.Verify(server))
+			body, err = io.ReadAll(server.Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"amount":100}`, string(body), "验签后请求体仍可读取")
+		})
+	}
+}
+
+func TestVerifier_Rejections(t *testing.T) {
+	cfg := Default().Enable().WithSecretKey("s3cr3t")
+	cfg.RequireNonce = true
+	cfg.RequireTimestamp = true
+
+	signed := func(t *testing.T, signer *Signer, method, target, body string) *http.Request {
+		r := newRequest(method, target, body)
+		require.NoError(t, signer.Sign(r))
+		return r
+	}
+	resend := func(r *http.Request, body string) *http.Request {
+		clone := newRequest(r.Method, r.URL.String(), body)
+		clone.Header = r.Header.Clone()
+		return clone
+	}
+
+	t.Run("重放", func(t *testing.T) {
+		signer, verifier, store := newPair(t, cfg)
+		r := signed(t, signer, http.MethodPost, "/pay", `{"n":1}`)
+		require.NoError(t, verifier.Verify(resend(r, `{"n":1}`)))
+		assert.ErrorIs(t, verifier.Verify(resend(r, `{"n":1}`)), ErrReplayedNonce)
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("篡改请求体", func(t *testing.T) {
+		signer, verifier, store := newPair(t, cfg)
+		r := signed(t, signer, http.MethodPost, "/pay", `{"n":1}`)
+		assert.ErrorIs(t, verifier.Verify(resend(r, `{"n":100}`)), ErrInvalidSignature)
+		assert.Zero(t, store.Len(), "签名无效时不消耗 nonce")
+		require.NoError(t, verifier.Verify(resend(r, `{"n":1}`)))
+	})
+
+	t.Run("篡改查询参数与路径", func(t *testing.T) {
+		signer, verifier, _ := newPair(t, cfg)
+		r := signed(t, signer, http.MethodGet, "/items?page=1", "")
+
+		tampered := resend(r, "")
+		tampered.URL.RawQuery = "page=2"
+		assert.ErrorIs(t, verifier.Verify(tampered), ErrInvalidSignature)
+
+		tampered = resend(r, "")
+		tampered.URL.Path = "/admin"
+		assert.ErrorIs(t, verifier.Verify(tampered), ErrInvalidSignature)
+
+		tampered = resend(r, "")
+		tampered.Header.Set("Content-Type", "text/plain")
+		assert.ErrorIs(t, verifier.Verify(tampered), ErrInvalidSignature, "Content-Type 参与签名")
+	})
+
+	t.Run("时钟偏差", func(t *testing.T) {
+		signer, verifier, _ := newPair(t, cfg)
+		for skew, ok := range map[time.Duration]bool{
+			-4 * time.Minute: true,
+			4 * time.Minute:  true,
+			-6 * time.Minute: false,
+			6 * time.Minute:  false,
+		} {
+			signer.WithClock(func() time.Time { return testNow.Add(skew) })
+			err := verifier.Verify(signed(t, signer, http.MethodGet, "/items", ""))
+			if ok {
+				assert.NoError(t, err, skew)
+			} else {
+				assert.ErrorIs(t, err, ErrTimestampExpired, skew)
+			}
+		}
+	})
+
+	t.Run("缺少请求头", func(t *testing.T) {
+		signer, verifier, _ := newPair(t, cfg)
+		r := signed(t, signer, http.MethodGet, "/items", "")
+		cases := map[string]error{
+			HeaderSignature: ErrMissingSignature,
+			HeaderTimestamp: ErrMissingHeader,
+			"Content-Type":  ErrMissingHeader,
+			HeaderNonce:     ErrMissingNonce,
+		}
+		for header, want := range cases {
+			clone := resend(r, "")
+			clone.Header.Del(header)
+			assert.ErrorIs(t, verifier.Verify(clone), want, header)
+		}
+
+		clone := resend(r, "")
+		clone.Header.Set(HeaderTimestamp, "yesterday")
+		assert.ErrorIs(t, verifier.Verify(clone), ErrInvalidTimestamp)
+
+		assert.ErrorContains(t, signer.Sign(httptest.NewRequest(http.MethodGet, "/items", nil)), "missing required header Content-Type")
+	})
+
+	t.Run("请求体过大", func(t *testing.T) {
+		limited := cfg.Clone().(*Signature).WithMaxBodySize(8)
+		signer, verifier, store := newPair(t, limited)
+		assert.ErrorIs(t, signer.Sign(newRequest(http.MethodPost, "/pay", strings.Repeat("x", 9))), ErrBodyTooLarge)
+
+		r := signed(t, signer, http.MethodPost, "/pay", strings.Repeat("x", 8))
+		require.NoError(t, verifier.Verify(resend(r, strings.Repeat("x", 8))), "恰好等于上限")
+		assert.ErrorIs(t, verifier.Verify(resend(r, strings.Repeat("x", 1<<20))), ErrBodyTooLarge)
+		assert.Equal(t, 1, store.Len(), "请求体过大时不消耗 nonce")
+	})
+
+	t.Run("Nonce 保留时间覆盖时间窗口", func(t *testing.T) {
+		short := cfg.Clone().(*Signature)
+		short.NonceTTL = time.Minute
+		signer, verifier, store := newPair(t, short)
+		signer.WithClock(func() time.Time { return testNow.Add(4 * time.Minute) })
+		r := signed(t, signer, http.MethodGet, "/items", "")
+		require.NoError(t, verifier.Verify(resend(r, "")))
+
+		// 时间戳在之后 9 分钟内仍处于窗口内，nonce 不能按配置的 1 分钟过期
+		later := func() time.Time { return testNow.Add(8 * time.Minute) }
+		verifier.WithClock(later)
+		store.WithClock(later)
+		assert.ErrorIs(t, verifier.Verify(resend(r, "")), ErrReplayedNonce)
+	})
+
+	t.Run("签名密钥不同", func(t *testing.T) {
+		signer, _, _ := newPair(t, cfg)
+		_, verifier, _ := newPair(t, cfg.Clone().(*Signature).WithSecretKey("other"))
+		assert.ErrorIs(t, verifier.Verify(signed(t, signer, http.MethodGet, "/items", "")), ErrInvalidSignature)
+	})
+
+	t.Run("RSA 公钥不匹配", func(t *testing.T) {
+		signer, _, _ := newPair(t, newRSAConfig(t))
+		_, verifier, _ := newPair(t, newRSAConfig(t))
+		assert.ErrorIs(t, verifier.Verify(signed(t, signer, http.MethodGet, "/items", "")), ErrInvalidSignature)
+	})
+}
+
+func TestVerifier_OptionalFieldsAndFormats(t *testing.T) {
+	// 默认配置兼容不带 nonce 的旧客户端
+	cfg := Default().Enable().WithSecretKey("s3cr3t").WithTimestampFormat(TimestampUnixMilli)
+	signer, verifier, store := newPair(t, cfg)
+	signer.WithNonceGenerator(func() string { return "" })
+
+	r := newRequest(http.MethodGet, "/items", "")
+	require.NoError(t, signer.Sign(r))
+	assert.Equal(t, "1792411200000", r.Header.Get(HeaderTimestamp))
+	require.NoError(t, verifier.Verify(r))
+	require.NoError(t, verifier.Verify(r), "未携带 nonce 时不做重放检查")
+	assert.Zero(t, store.Len())
+
+	cfg = Default().Enable().WithSecretKey("s3cr3t").WithTimestampFormat(time.RFC3339).WithSkipBody(true).WithSkipQuery(true)
+	signer, verifier, _ = newPair(t, cfg)
+	r = newRequest(http.MethodPost, "/items?x=1", "a")
+	require.NoError(t, signer.Sign(r))
+	assert.Equal(t, "2026-10-19T12:00:00Z", r.Header.Get(HeaderTimestamp))
+	tampered := newRequest(http.MethodPost, "/items?x=2", "b")
+	tampered.Header = r.Header.Clone()
+	require.NoError(t, verifier.Verify(tampered), "跳过查询参数与请求体")
+}
+
+func TestVerifier_Handler(t *testing.T) {
+	cfg := Default().Enable().WithSecretKey("s3cr3t").AddIgnorePath("/public/*")
+	signer, verifier, _ := newPair(t, cfg)
+	handler := verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	r := newRequest(http.MethodPost, "/api", "payload")
+	require.NoError(t, signer.Sign(r))
+	rec := serve(r)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "payload", rec.Body.String())
+
+	rec = serve(newRequest(http.MethodPost, "/api", "payload"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	r = newRequest(http.MethodPost, "/api", "payload")
+	require.NoError(t, signer.Sign(r))
+	r.Body = io.NopCloser(strings.NewReader(strings.Repeat("x", 11<<20)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(r).Code, "默认限制 10MB")
+	assert.Contains(t, rec.Body.String(), ErrMissingSignature.Error())
+
+	for _, path := range []string{"/health", "/public/logo.png"} {
+		assert.Equal(t, http.StatusOK, serve(newRequest(http.MethodGet, path, "")).Code, path)
+	}
+	assert.True(t, verifier.Ignored("/ping"))
+	assert.False(t, verifier.Ignored("/pings"))
+
+	// 规范化后不在忽略列表中的路径仍需签名
+	for _, path := range []string{"/public/../admin", "/public/./../api", "//public/../admin/"} {
+		assert.False(t, verifier.Ignored(path), path)
+		assert.Equal(t, http.StatusUnauthorized, serve(newRequest(http.MethodGet, path, "")).Code, path)
+	}
+	assert.True(t, verifier.Ignored("/public/./img/../logo.png"))
+
+	disabled, err := NewVerifier(cfg.Clone().(*Signature).Disable(), nil)
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	disabled.Handler(http.NotFoundHandler()).ServeHTTP(rec, newRequest(http.MethodGet, "/api", ""))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	now := testNow
+	store := NewMemoryNonceStore().WithClock(func() time.Time { return now })
+
+	ok, err := store.CheckAndStore(t.Context(), "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, _ = store.CheckAndStore(t.Context(), "a", time.Minute)
+	assert.False(t, ok)
+
+	now = now.Add(30 * time.Second)
+	store.CheckAndStore(t.Context(), "b", time.Minute)
+	now = now.Add(31 * time.Second)
+	ok, _ = store.CheckAndStore(t.Context(), "a", time.Minute)
+	assert.True(t, ok, "过期后可再次使用")
+
+	now = now.Add(2 * time.Minute)
+	store.CheckAndStore(t.Context(), "c", time.Minute)
+	assert.Equal(t, 1, store.Len(), "过期记录被清理")
+}
+
+func TestNewSignerVerifier_Errors(t *testing.T) {
+	_, err := NewSigner(nil)
+	assert.Error(t, err)
+	_, err = NewSigner(Default().WithSecretKey(""))
+	assert.ErrorContains(t, err, "secret key is required")
+	_, err = NewSigner(Default().WithType(SignatureTypeRSA))
+	assert.ErrorContains(t, err, "invalid rsa private key")
+	_, err = NewVerifier(Default().WithType(SignatureTypeRSA).WithPublicKeyPEM("bad"), nil)
+	assert.ErrorContains(t, err, "invalid rsa public key")
+	_, err = NewVerifier(Default().WithType("ecdsa"), nil)
+	assert.ErrorContains(t, err, "unsupported signature type")
+	_, err = NewVerifier(Default().WithAlgorithm("SM3"), nil)
+	assert.ErrorContains(t, err, "unsupported signature algorithm")
+}
@@ -0,0 +1,619 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\swagger\merge.go
+ * @Description: 聚合多个服务的 OpenAPI 2/3 规范
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-config/internal"
+	"github.com/kamalyes/go-logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Fetcher 拉取远程规范文档
+type Fetcher func(url string) ([]byte, error)
+
+// HTTPFetcher 基于 http.Client 的 Fetcher，client 为 nil 时使用 10 秒超时的客户端，非 2xx 响应视为错误
+func HTTPFetcher(client *http.Client) Fetcher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return func(url string) ([]byte, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+		}
+		return io.ReadAll(resp.Body)
+	}
+}
+
+// Spec OpenAPI 文档
+type Spec map[string]any
+
+// Result 聚合结果
+type Result struct {
+	Merged    Spec            // 全部启用服务合并后的文档
+	Documents map[string]Spec // 按 DocumentSpec.Name 生成的独立文档
+	Warnings  []string        // 不影响结果的提示，如 OpenAPI 3.0 与 3.1 混用
+}
+
+// 冲突类型
+const (
+	ConflictPath       = "path"       // 同一路径与方法出现在多个服务中
+	ConflictDefinition = "definition" // 同名定义内容不一致
+	ConflictVersion    = "version"    // OpenAPI 2 与 3 混用
+)
+
+// Conflict 聚合冲突，先出现的服务生效，后者被跳过
+type Conflict struct {
+	Kind     string   // 冲突类型
+	Name     string   // 路径为 `METHOD /path`，定义为 `section/name`，版本为服务的规范版本
+	Document string   // 所在文档名称，空表示 Merged
+	Services []string // 冲突双方服务名
+}
+
+// String 返回冲突描述
+func (c Conflict) String() string {
+	s := fmt.Sprintf("%s %s (%s)", c.Kind, c.Name, strings.Join(c.Services, ", "))
+	if c.Document != "" {
+		s = c.Document + ": " + s
+	}
+	return s
+}
+
+// MergeError 聚合过程中出现的冲突
+type MergeError struct {
+	Conflicts []Conflict
+}
+
+// Error 实现 error 接口
+func (e *MergeError) Error() string {
+	items := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		items[i] = c.String()
+	}
+	return fmt.Sprintf("swagger merge has %d conflict(s): %s", len(e.Conflicts), strings.Join(items, "; "))
+}
+
+// httpMethods 路径项中的操作字段
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// 各版本需要添加服务名前缀的组件分区，键为分区名，值为 $ref 前缀
+var (
+	namespacedV2 = map[string]string{
+		"definitions": "#/definitions/",
+		"parameters":  "#/parameters/",
+		"responses":   "#/responses/",
+	}
+	namespacedV3 = map[string]string{
+		"schemas":       "#/components/schemas/",
+		"responses":     "#/components/responses/",
+		"parameters":    "#/components/parameters/",
+		"examples":      "#/components/examples/",
+		"requestBodies": "#/components/requestBodies/",
+		"headers":       "#/components/headers/",
+		"links":         "#/components/links/",
+		"callbacks":     "#/components/callbacks/",
+	}
+)
+
+// globalFields 文档级默认值：各服务取值一致时保留在文档顶层，否则下沉到该服务未单独设置的操作上
+var globalFields = map[int][]string{
+	2: {"consumes", "produces", "security"},
+	3: {"security"},
+}
+
+// invalidNameChars 组件名称中不允许的字符
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// service 加载并完成重命名的服务规范
+type service struct {
+	name       string
+	version    int                       // 2 或 3
+	openapi    string                    // OpenAPI 3 的版本号
+	paths      map[string]map[string]any // 加上前缀后的路径 -> 路径项
+	rawPaths   map[string]string         // 加上前缀后的路径 -> 原始路径
+	components map[string]map[string]any // 分区 -> 名称 -> 定义，安全定义使用 securityDefinitions/securitySchemes
+	globals    map[string]any            // 文档级 consumes/produces/security
+	tags       []any
+}
+
+// Merge 加载 cfg.Services 中启用服务的规范并聚合
+//
+// 规范来自 SpecPath 本地文件或 URL（通过 fetch 拉取，nil 时使用 HTTPFetcher(nil)），支持 JSON 与 YAML。
+// 路径加上服务的 BasePath 前缀（OpenAPI 2 的 basePath 先并入路径）；组件名称改为 `服务名.名称` 并同步改写 $ref，
+// 匹配 SharedDefinitionPrefixes 的名称及安全定义保持原名，内容相同时去重。
+// Documents 按来源的 include/exclude 选择器筛选接口，选择器路径可以是服务原始路径或加前缀后的路径，
+// 支持 path.Match 通配与 `/**` 后缀前缀匹配。
+//
+// 出现冲突时仍返回结果，并返回 *MergeError 列出全部冲突
+func Merge(cfg *AggregateConfig, fetch Fetcher) (*Result, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("aggregate config is nil")
+	}
+	if fetch == nil {
+		fetch = HTTPFetcher(nil)
+	}
+
+	var order []*service
+	services := make(map[string]*service)
+	for _, spec := range cfg.Services {
+		if spec == nil || !spec.Enabled {
+			continue
+		}
+		if spec.Name == "" {
+			return nil, fmt.Errorf("aggregate service name is required")
+		}
+		if _, exists := services[spec.Name]; exists {
+			return nil, fmt.Errorf("duplicate aggregate service: %s", spec.Name)
+		}
+		svc, err := loadService(spec, cfg.SharedDefinitionPrefixes, fetch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load spec for service %s: %w", spec.Name, err)
+		}
+		services[spec.Name] = svc
+		order = append(order, svc)
+	}
+
+	var conflicts []Conflict
+	parts := make([]part, len(order))
+	for i, svc := range order {
+		parts[i] = part{svc: svc}
+	}
+	result := &Result{Documents: make(map[string]Spec)}
+	result.Merged = assemble("", map[string]any{"title": "Aggregated API", "version": "1.0.0"}, parts, &conflicts, &result.Warnings)
+
+	for _, doc := range cfg.Documents {
+		if doc == nil || !doc.Enabled {
+			continue
+		}
+		var docParts []part
+		for _, source := range doc.Sources {
+			if source == nil {
+				continue
+			}
+			svc, ok := services[source.Service]
+			if !ok {
+				return nil, fmt.Errorf("document %s references unknown or disabled service: %s", doc.Name, source.Service)
+			}
+			docParts = append(docParts, part{svc: svc, include: source.GetEffectiveInclude(), exclude: source.Exclude})
+		}
+		info := map[string]any{"title": doc.Title, "version": doc.Version}
+		if doc.Description != "" {
+			info["description"] = doc.Description
+		}
+		result.Documents[doc.Name] = assemble(doc.Name, info, docParts, &conflicts, &result.Warnings)
+	}
+	for _, warning := range result.Warnings {
+		logger.GetGlobalLogger().Warn("swagger merge: %s", warning)
+	}
+
+	if len(conflicts) > 0 {
+		return result, &MergeError{Conflicts: conflicts}
+	}
+	return result, nil
+}
+
+// loadService 读取规范并完成路径前缀与组件重命名
+func loadService(spec *ServiceSpec, shared []string, fetch Fetcher) (*service, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case spec.SpecPath != "":
+		data, err = os.ReadFile(spec.SpecPath)
+	case spec.URL != "":
+		data, err = fetch(spec.URL)
+	default:
+		return nil, fmt.Errorf("spec-path or url is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &service{
+		name:       spec.Name,
+		paths:      make(map[string]map[string]any),
+		rawPaths:   make(map[string]string),
+		components: make(map[string]map[string]any),
+		globals:    make(map[string]any),
+	}
+	var (
+		namespaced map[string]string
+		sections   map[string]any
+		security   string
+		specBase   string
+	)
+	switch {
+	case doc["swagger"] != nil:
+		svc.version, namespaced, sections, security = 2, namespacedV2, doc, "securityDefinitions"
+		specBase, _ = doc["basePath"].(string)
+	case doc["openapi"] != nil:
+		svc.version, namespaced, security = 3, namespacedV3, "securitySchemes"
+		svc.openapi = fmt.Sprint(doc["openapi"])
+		sections, _ = doc["components"].(map[string]any)
+	default:
+		return nil, fmt.Errorf("document is neither swagger 2.0 nor openapi 3")
+	}
+
+	// 计算重命名表后统一改写 $ref
+	prefix := invalidNameChars.ReplaceAllString(spec.Name, "_") + "."
+	renames := make(map[string]string)
+	for section, refPrefix := range namespaced {
+		items, _ := sections[section].(map[string]any)
+		if len(items) == 0 {
+			continue
+		}
+		renamed := make(map[string]any, len(items))
+		for name, value := range items {
+			newName := name
+			if !slices.ContainsFunc(shared, func(p string) bool { return p != "" && strings.HasPrefix(name, p) }) {
+				newName = prefix + name
+			}
+			renames[refPrefix+escapePointer(name)] = refPrefix + escapePointer(newName)
+			renamed[newName] = value
+		}
+		svc.components[section] = renamed
+	}
+	if items, ok := sections[security].(map[string]any); ok {
+		svc.components[security] = items
+	}
+	for _, items := range svc.components {
+		rewriteRefs(items, renames)
+	}
+
+	paths, _ := doc["paths"].(map[string]any)
+	rewriteRefs(paths, renames)
+	for p, item := range paths {
+		pathItem, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		full := joinPath(spec.BasePath, joinPath(specBase, p))
+		svc.paths[full] = pathItem
+		svc.rawPaths[full] = joinPath(specBase, p)
+	}
+	for _, field := range globalFields[svc.version] {
+		if value, ok := doc[field]; ok {
+			svc.globals[field] = value
+		}
+	}
+	svc.tags, _ = doc["tags"].([]any)
+	return svc, nil
+}
+
+// part 参与组装的服务及其接口选择器
+type part struct {
+	svc              *service
+	include, exclude []*DocumentPathSelector
+}
+
+// assemble 将多个服务组装为一份文档，版本以第一个服务为准；OpenAPI 3 次版本不一致时记录警告
+func assemble(document string, info map[string]any, parts []part, conflicts *[]Conflict, warnings *[]string) Spec {
+	out := Spec{"info": info}
+	if len(parts) == 0 {
+		out["openapi"] = "3.0.3"
+		out["paths"] = map[string]any{}
+		return out
+	}
+	first := parts[0].svc
+
+	paths := make(map[string]any)
+	pathOwners := make(map[string]string) // "METHOD path" -> 服务名
+	components := make(map[string]map[string]any)
+	componentOwners := make(map[string]string) // "section/name" -> 服务名
+	var tags []any
+	tagNames := make(map[string]bool)
+	globals := sharedGlobals(parts, first.version)
+
+	for _, pt := range parts {
+		svc := pt.svc
+		if svc.version != first.version {
+			*conflicts = append(*conflicts, Conflict{
+				Kind: ConflictVersion, Name: fmt.Sprintf("openapi %d", svc.version),
+				Document: document, Services: []string{first.name, svc.name},
+			})
+			continue
+		}
+		if svc.version == 3 && minorVersion(svc.openapi) != minorVersion(first.openapi) {
+			warning := fmt.Sprintf("service %s uses openapi %s but the document is generated as %s (%s)", svc.name, svc.openapi, first.openapi, first.name)
+			if document != "" {
+				warning = document + ": " + warning
+			}
+			*warnings = append(*warnings, warning)
+		}
+
+		for _, p := range sortedKeys(svc.paths) {
+			item := svc.paths[p]
+			selected := make(map[string]any)
+			for _, method := range httpMethods {
+				op, ok := item[method]
+				if !ok || !pt.selected(svc.rawPaths[p], p, method) {
+					continue
+				}
+				key := strings.ToUpper(method) + " " + p
+				if owner, exists := pathOwners[key]; exists {
+					*conflicts = append(*conflicts, Conflict{
+						Kind: ConflictPath, Name: key, Document: document, Services: []string{owner, svc.name},
+					})
+					continue
+				}
+				pathOwners[key] = svc.name
+				selected[method] = withGlobals(op, svc.globals, globals)
+			}
+			if len(selected) == 0 {
+				continue
+			}
+			target, _ := paths[p].(map[string]any)
+			if target == nil {
+				target = make(map[string]any)
+				for k, v := range item {
+					if !slices.Contains(httpMethods, k) {
+						target[k] = v
+					}
+				}
+				paths[p] = target
+			}
+			for method, op := range selected {
+				target[method] = op
+			}
+		}
+
+		for _, section := range sortedKeys(svc.components) {
+			target := components[section]
+			if target == nil {
+				target = make(map[string]any)
+				components[section] = target
+			}
+			for _, name := range sortedKeys(svc.components[section]) {
+				value := svc.components[section][name]
+				key := section + "/" + name
+				if existing, exists := target[name]; exists {
+					if !reflect.DeepEqual(existing, value) {
+						*conflicts = append(*conflicts, Conflict{
+							Kind: ConflictDefinition, Name: key, Document: document, Services: []string{componentOwners[key], svc.name},
+						})
+					}
+					continue
+				}
+				componentOwners[key] = svc.name
+				target[name] = value
+			}
+		}
+
+		for _, tag := range svc.tags {
+			name, _ := tag.(map[string]any)["name"].(string)
+			if name != "" && !tagNames[name] {
+				tagNames[name] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	out["paths"] = paths
+	if len(tags) > 0 {
+		out["tags"] = tags
+	}
+	for field, value := range globals {
+		out[field] = value
+	}
+	if first.version == 2 {
+		out["swagger"] = "2.0"
+		for section, items := range components {
+			out[section] = items
+		}
+		return out
+	}
+	out["openapi"] = first.openapi
+	if len(components) > 0 {
+		c := make(map[string]any, len(components))
+		for section, items := range components {
+			c[section] = items
+		}
+		out["components"] = c
+	}
+	return out
+}
+
+// selected 判断接口是否被选择器选中
+func (pt part) selected(rawPath, fullPath, method string) bool {
+	matches := func(s *DocumentPathSelector) bool {
+		if s == nil || !(internal.MatchPath(s.Path, rawPath) || internal.MatchPath(s.Path, fullPath)) {
+			return false
+		}
+		return len(s.Methods) == 0 || slices.ContainsFunc(s.Methods, func(m string) bool { return strings.EqualFold(m, method) })
+	}
+	if len(pt.include) > 0 && !slices.ContainsFunc(pt.include, matches) {
+		return false
+	}
+	return !slices.ContainsFunc(pt.exclude, matches)
+}
+
+// sharedGlobals 返回所有同版本服务取值完全一致的文档级字段
+func sharedGlobals(parts []part, version int) map[string]any {
+	shared := make(map[string]any)
+	for _, field := range globalFields[version] {
+		value, ok := parts[0].svc.globals[field]
+		if !ok {
+			continue
+		}
+		same := true
+		for _, pt := range parts[1:] {
+			if pt.svc.version != version {
+				continue
+			}
+			if other, ok := pt.svc.globals[field]; !ok || !reflect.DeepEqual(other, value) {
+				same = false
+				break
+			}
+		}
+		if same {
+			shared[field] = value
+		}
+	}
+	return shared
+}
+
+// withGlobals 将未保留在文档顶层的服务级默认值写入操作，操作自身的设置优先；需要修改时复制操作，避免影响其他文档
+func withGlobals(op any, globals, shared map[string]any) any {
+	operation, ok := op.(map[string]any)
+	if !ok {
+		return op
+	}
+	var copied map[string]any
+	for _, field := range sortedKeys(globals) {
+		if _, ok := shared[field]; ok {
+			continue
+		}
+		if _, ok := operation[field]; ok {
+			continue
+		}
+		if copied == nil {
+			copied = make(map[string]any, len(operation)+len(globals))
+			for k, v := range operation {
+				copied[k] = v
+			}
+		}
+		copied[field] = globals[field]
+	}
+	if copied == nil {
+		return op
+	}
+	return copied
+}
+
+// minorVersion 返回 "主版本.次版本"，如 3.1.0 -> 3.1
+func minorVersion(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// joinPath 拼接路径前缀，忽略空前缀与多余的斜杠
+func joinPath(base, p string) string {
+	base = strings.TrimRight(base, "/")
+	if base == "" {
+		return p
+	}
+	if base[0] != '/' {
+		base = "/" + base
+	}
+	return base + "/" + strings.TrimLeft(p, "/")
+}
+
+// rewriteRefs 按重命名表递归改写 $ref
+func rewriteRefs(node any, renames map[string]string) {
+	switch v := node.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				if renamed, ok := renames[ref]; ok {
+					v[k] = renamed
+				}
+				continue
+			}
+			rewriteRefs(child, renames)
+		}
+	case []any:
+		for _, child := range v {
+			rewriteRefs(child, renames)
+		}
+	}
+}
+
+// escapePointer 按 JSON Pointer 规则转义名称
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// decodeSpec 解析 JSON 或 YAML 规范，并统一为 JSON 数据类型，便于比较去重
+func decodeSpec(data []byte) (Spec, error) {
+	var raw any
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("invalid json spec: %w", err)
+		}
+	} else {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("invalid yaml spec: %w", err)
+		}
+		normalized, err := json.Marshal(stringKeys(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid yaml spec: %w", err)
+		}
+		raw = nil
+		if err := json.Unmarshal(normalized, &raw); err != nil {
+			return nil, err
+		}
+	}
+	doc, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("spec must be an object")
+	}
+	return doc, nil
+}
+
+// stringKeys 将 YAML 中的非字符串键（如响应码 200）转换为字符串
+func stringKeys(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		for k, child := range v {
+			v[k] = stringKeys(child)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, child := range v {
+			m[fmt.Sprint(k)] = stringKeys(child)
+		}
+		return m
+	case []any:
+		for i, child := range v {
+			v[i] = stringKeys(child)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// sortedKeys 返回排序后的键，保证冲突报告顺序稳定
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
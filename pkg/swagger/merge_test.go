@@ -0,0 +1,336 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\swagger\merge_test.go
+ * @Description: Swagger 聚合测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package swagger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userSpecYAML = `swagger: "2.0"
+info:
+  title: user
+  version: 1.0.0
+basePath: /api
+tags:
+  - name: user
+paths:
+  /users:
+    get:
+      responses:
+        200:
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/User'
+    post:
+      parameters:
+        - in: body
+          name: body
+          schema:
+            $ref: '#/definitions/User'
+      responses:
+        400:
+          schema:
+            $ref: '#/definitions/commonError'
+  /users/{id}:
+    parameters:
+      - $ref: '#/parameters/id'
+    get:
+      responses:
+        200:
+          schema:
+            $ref: '#/definitions/User'
+parameters:
+  id:
+    in: path
+    name: id
+    required: true
+    type: string
+definitions:
+  User:
+    type: object
+    properties:
+      id:
+        type: string
+  commonError:
+    type: object
+    properties:
+      code:
+        type: integer
+securityDefinitions:
+  ApiKey:
+    type: apiKey
+    in: header
+    name: X-Api-Key
+`
+
+const orderSpecJSON = `{
+  "swagger": "2.0",
+  "info": {"title": "order", "version": "1.0.0"},
+  "tags": [{"name": "order"}, {"name": "user"}],
+  "paths": {
+    "/orders": {"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/Order"}}}}},
+    "/orders/{id}": {"delete": {"responses": {"400": {"schema": {"$ref": "#/definitions/commonError"}}}}}
+  },
+  "definitions": {
+    "Order": {"type": "object", "properties": {"buyer": {"$ref": "#/definitions/User"}}},
+    "User": {"type": "object", "properties": {"name": {"type": "string"}}},
+    "commonError": {"type": "object", "properties": {"code": {"type": "integer"}}}
+  },
+  "securityDefinitions": {"ApiKey": {"type": "apiKey", "in": "header", "name": "X-Api-Key"}}
+}`
+
+func writeSpec(t *testing.T, name, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	return p
+}
+
+func serveSpec(t *testing.T, content string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/swagger.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL + "/swagger.json"
+}
+
+func ref(v any, keys ...string) any {
+	if spec, ok := v.(Spec); ok {
+		v = map[string]any(spec)
+	}
+	for _, k := range keys {
+		v = v.(map[string]any)[k]
+	}
+	return v
+}
+
+func TestMerge_SwaggerV2(t *testing.T) {
+	cfg := &AggregateConfig{
+		Services: []*ServiceSpec{
+			NewServiceSpec("user", "", writeSpec(t, "user.yaml", userSpecYAML)).WithBasePath("/user-svc"),
+			NewRemoteServiceSpec("order", "", serveSpec(t, orderSpecJSON)).WithBasePath("/order-svc/"),
+			NewServiceSpec("disabled", "", "missing.yaml").Disable(),
+		},
+		SharedDefinitionPrefixes: []string{"common"},
+	}
+
+	result, err := Merge(cfg, nil)
+	require.NoError(t, err)
+	merged := result.Merged
+	assert.Equal(t, "2.0", merged["swagger"])
+
+	paths := merged["paths"].(map[string]any)
+	assert.ElementsMatch(t, []string{"/user-svc/api/users", "/user-svc/api/users/{id}", "/order-svc/orders", "/order-svc/orders/{id}"}, sortedKeys(paths))
+	assert.Equal(t, "#/parameters/user.id", ref(paths, "/user-svc/api/users/{id}", "parameters").([]any)[0].(map[string]any)["$ref"])
+	assert.Equal(t, "#/definitions/user.User", ref(paths, "/user-svc/api/users/{id}", "get", "responses", "200", "schema", "$ref"))
+	assert.Equal(t, "#/definitions/commonError", ref(paths, "/order-svc/orders/{id}", "delete", "responses", "400", "schema", "$ref"))
+
+	definitions := merged["definitions"].(map[string]any)
+	assert.ElementsMatch(t, []string{"user.User", "order.User", "order.Order", "commonError"}, sortedKeys(definitions))
+	assert.Equal(t, "#/definitions/order.User", ref(definitions, "order.Order", "properties", "buyer", "$ref"))
+	assert.Contains(t, merged["securityDefinitions"], "ApiKey")
+	assert.Len(t, merged["tags"], 2)
+}
+
+func TestMerge_SwaggerV2GlobalFields(t *testing.T) {
+	userSpec := `swagger: "2.0"
+consumes: [application/json]
+produces: [application/json]
+security:
+  - ApiKey: []
+paths:
+  /users:
+    get: {responses: {200: {description: ok}}}
+    post: {produces: [text/plain], responses: {200: {description: ok}}}
+`
+	orderSpec := `swagger: "2.0"
+consumes: [application/json]
+produces: [application/xml]
+paths:
+  /orders:
+    get: {responses: {200: {description: ok}}}
+    delete: {security: [], responses: {200: {description: ok}}}
+`
+	cfg := &AggregateConfig{
+		Services: []*ServiceSpec{
+			NewServiceSpec("user", "", writeSpec(t, "user.yaml", userSpec)),
+			NewServiceSpec("order", "", writeSpec(t, "order.yaml", orderSpec)),
+		},
+		Documents: []*DocumentSpec{{Name: "user", Enabled: true, Sources: []*DocumentSource{{Service: "user"}}}},
+	}
+
+	result, err := Merge(cfg, nil)
+	require.NoError(t, err)
+	merged := result.Merged
+	// 各服务一致的字段保留在顶层，不一致的下沉到操作，操作自身的设置优先
+	assert.Equal(t, []any{"application/json"}, merged["consumes"])
+	assert.NotContains(t, merged, "produces")
+	assert.NotContains(t, merged, "security")
+	assert.Equal(t, []any{"application/json"}, ref(merged, "paths", "/users", "get", "produces"))
+	assert.Equal(t, []any{map[string]any{"ApiKey": []any{}}}, ref(merged, "paths", "/users", "get", "security"))
+	assert.Equal(t, []any{"text/plain"}, ref(merged, "paths", "/users", "post", "produces"))
+	assert.Equal(t, []any{"application/xml"}, ref(merged, "paths", "/orders", "get", "produces"))
+	assert.NotContains(t, ref(merged, "paths", "/orders", "get"), "security")
+	assert.Equal(t, []any{}, ref(merged, "paths", "/orders", "delete", "security"))
+	assert.NotContains(t, ref(merged, "paths", "/orders", "get"), "consumes")
+
+	// 单服务文档保留原有的顶层字段，操作不受合并文档影响
+	doc := result.Documents["user"]
+	assert.Equal(t, []any{"application/json"}, doc["produces"])
+	assert.Equal(t, []any{map[string]any{"ApiKey": []any{}}}, doc["security"])
+	assert.NotContains(t, ref(doc, "paths", "/users", "get"), "security")
+}
+
+func TestMerge_Documents(t *testing.T) {
+	userPath := writeSpec(t, "user.yaml", userSpecYAML)
+	orderURL := serveSpec(t, orderSpecJSON)
+	open := NewDocumentSpec("open").WithTitle("Open API").
+		AddSource(NewDocumentSource("user").AddIncludePath("/api/users/**").AddExcludePath("/api/users", "POST")).
+		AddSource(NewDocumentSource("order").AddIncludePath("/order-svc/orders/*", "delete"))
+	cfg := &AggregateConfig{
+		Services: []*ServiceSpec{
+			NewServiceSpec("user", "", userPath).WithBasePath("/user-svc"),
+			NewRemoteServiceSpec("order", "", orderURL).WithBasePath("/order-svc"),
+		},
+		Documents:                []*DocumentSpec{open, NewDocumentSpec("off").Disable()},
+		SharedDefinitionPrefixes: []string{"common"},
+	}
+
+	result, err := Merge(cfg, HTTPFetcher(http.DefaultClient))
+	require.NoError(t, err)
+	require.Contains(t, result.Documents, "open")
+	assert.NotContains(t, result.Documents, "off")
+
+	doc := result.Documents["open"]
+	assert.Equal(t, "Open API", ref(doc, "info", "title"))
+	paths := doc["paths"].(map[string]any)
+	assert.ElementsMatch(t, []string{"/user-svc/api/users", "/user-svc/api/users/{id}", "/order-svc/orders/{id}"}, sortedKeys(paths))
+	assert.Contains(t, paths["/user-svc/api/users"], "get")
+	assert.NotContains(t, paths["/user-svc/api/users"], "post")
+
+	cfg.Documents = []*DocumentSpec{NewDocumentSpec("bad").AddSource(NewDocumentSource("missing"))}
+	_, err = Merge(cfg, nil)
+	assert.Error(t, err)
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	conflicting := `{
+  "swagger": "2.0",
+  "basePath": "/api",
+  "paths": {"/users": {"get": {}, "put": {}}},
+  "definitions": {"commonError": {"type": "string"}}
+}`
+	cfg := &AggregateConfig{
+		Services: []*ServiceSpec{
+			NewServiceSpec("user", "", writeSpec(t, "user.yaml", userSpecYAML)),
+			NewRemoteServiceSpec("legacy", "", serveSpec(t, conflicting)),
+		},
+		SharedDefinitionPrefixes: []string{"common"},
+	}
+
+	result, err := Merge(cfg, nil)
+	var mergeErr *MergeError
+	require.True(t, errors.As(err, &mergeErr))
+	assert.Equal(t, []Conflict{
+		{Kind: ConflictPath, Name: "GET /api/users", Services: []string{"user", "legacy"}},
+		{Kind: ConflictDefinition, Name: "definitions/commonError", Services: []string{"user", "legacy"}},
+	}, mergeErr.Conflicts)
+
+	users := ref(result.Merged, "paths", "/api/users").(map[string]any)
+	assert.Contains(t, users, "put", "不冲突的方法仍然合并")
+	assert.Equal(t, "object", ref(result.Merged, "definitions", "commonError", "type"), "先出现的服务生效")
+}
+
+func TestMerge_OpenAPI3(t *testing.T) {
+	petSpec := `openapi: 3.0.3
+info: {title: pet, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+components:
+  schemas:
+    Pet: {type: object}
+    googleRpcStatus: {type: object}
+  securitySchemes:
+    bearer: {type: http, scheme: bearer}
+`
+	storeSpec := `{"openapi": "3.0.3", "paths": {"/stores": {"get": {"requestBody": {"$ref": "#/components/requestBodies/Store"}}}},
+"components": {"requestBodies": {"Store": {"content": {}}}, "schemas": {"googleRpcStatus": {"type": "object"}}}}`
+	cfg := &AggregateConfig{
+		Services: []*ServiceSpec{
+			NewServiceSpec("pet", "", writeSpec(t, "pet.yaml", petSpec)),
+			NewRemoteServiceSpec("store api", "", serveSpec(t, storeSpec)),
+		},
+		SharedDefinitionPrefixes: []string{"google"},
+	}
+
+	result, err := Merge(cfg, nil)
+	require.NoError(t, err)
+	merged := result.Merged
+	assert.Equal(t, "3.0.3", merged["openapi"])
+	assert.Equal(t, "#/components/schemas/pet.Pet", ref(merged, "paths", "/pets", "get", "responses", "200", "content", "application/json", "schema", "$ref"))
+	assert.Equal(t, "#/components/requestBodies/store_api.Store", ref(merged, "paths", "/stores", "get", "requestBody", "$ref"))
+	assert.ElementsMatch(t, []string{"pet.Pet", "googleRpcStatus"}, sortedKeys(ref(merged, "components", "schemas").(map[string]any)))
+	assert.Contains(t, ref(merged, "components", "securitySchemes"), "bearer")
+	assert.Empty(t, result.Warnings)
+
+	// 3.0 与 3.1 混用时仍合并，但给出警告
+	cfg.Services = append(cfg.Services, NewServiceSpec("tag", "", writeSpec(t, "tag.yaml", `openapi: 3.1.0
+paths:
+  /tags:
+    get: {responses: {"200": {description: ok}}}
+`)))
+	result, err = Merge(cfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.3", result.Merged["openapi"])
+	assert.Contains(t, result.Merged["paths"], "/tags")
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "service tag uses openapi 3.1.0")
+
+	cfg.Services = append(cfg.Services, NewServiceSpec("user", "", writeSpec(t, "user.yaml", userSpecYAML)))
+	_, err = Merge(cfg, nil)
+	var mergeErr *MergeError
+	require.True(t, errors.As(err, &mergeErr))
+	assert.Equal(t, ConflictVersion, mergeErr.Conflicts[0].Kind)
+}
+
+func TestMerge_LoadErrors(t *testing.T) {
+	_, err := Merge(nil, nil)
+	assert.Error(t, err)
+
+	missing := &AggregateConfig{Services: []*ServiceSpec{NewRemoteServiceSpec("x", "", serveSpec(t, "{}")+"/missing")}}
+	_, err = Merge(missing, nil)
+	assert.ErrorContains(t, err, "unexpected status 404")
+
+	invalid := &AggregateConfig{Services: []*ServiceSpec{NewServiceSpec("x", "", writeSpec(t, "x.yaml", "info: {}"))}}
+	_, err = Merge(invalid, nil)
+	assert.ErrorContains(t, err, "neither swagger 2.0 nor openapi 3")
+}
@@ -0,0 +1,125 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\timeout\middleware.go
+ * @Description: 超时 HTTP 中间件与 gRPC 拦截器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Middleware 按路由解析超时的中间件
+type Middleware struct {
+	config *Timeout
+}
+
+// NewMiddleware 校验路由配置并创建中间件
+func NewMiddleware(cfg *Timeout) (*Middleware, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("timeout config is nil")
+	}
+	cfg = cfg.Clone().(*Timeout)
+	if cfg.Duration < 0 {
+		return nil, fmt.Errorf("timeout duration must not be negative: %s", cfg.Duration)
+	}
+	for _, route := range cfg.Routes {
+		if route == nil {
+			continue
+		}
+		if route.Duration < 0 {
+			return nil, fmt.Errorf("timeout duration for route %s must not be negative: %s", route.Path, route.Duration)
+		}
+		if _, err := path.Match(route.Path, ""); err != nil {
+			return nil, fmt.Errorf("invalid timeout route pattern %q: %w", route.Path, err)
+		}
+	}
+	return &Middleware{config: cfg}, nil
+}
+
+// Handler 包装处理器：超时后返回 503 与 Message，截止时间通过 r.Context() 传递
+//
+// 基于 http.TimeoutHandler，响应在处理器返回前被缓冲，不适用于流式响应
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	if !m.config.Enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := m.config.Resolve(r.Method, r.URL.Path)
+		if d <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		http.TimeoutHandler(next, d, m.config.Message).ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor 一元调用拦截器：截止时间写入 ctx，超时时返回 DeadlineExceeded 与 Message
+func (m *Middleware) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !m.config.Enabled {
+			return handler(ctx, req)
+		}
+		ctx, cancel := m.withDeadline(ctx, info.FullMethod)
+		defer cancel()
+		resp, err := handler(ctx, req)
+		return resp, m.convert(ctx, err)
+	}
+}
+
+// StreamServerInterceptor 流式调用拦截器，行为同 UnaryServerInterceptor
+func (m *Middleware) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !m.config.Enabled {
+			return handler(srv, ss)
+		}
+		ctx, cancel := m.withDeadline(ss.Context(), info.FullMethod)
+		defer cancel()
+		return m.convert(ctx, handler(srv, &serverStream{ServerStream: ss, ctx: ctx}))
+	}
+}
+
+// withDeadline 为 gRPC 方法设置截止时间，客户端设置了更早的截止时间时以客户端为准
+func (m *Middleware) withDeadline(ctx context.Context, fullMethod string) (context.Context, context.CancelFunc) {
+	if d := m.config.Resolve("", fullMethod); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// convert 截止时间已过且处理器返回错误时，将错误转换为 DeadlineExceeded
+func (m *Middleware) convert(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	msg := m.config.Message
+	if msg == "" {
+		msg = context.DeadlineExceeded.Error()
+	}
+	return status.Error(codes.DeadlineExceeded, msg)
+}
+
+// serverStream 替换 Context 的 ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回带截止时间的 ctx
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,144 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\timeout\middleware_test.go
+ * @Description: 超时中间件与拦截器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package timeout
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// slowHealth 等待 ctx 结束后返回其错误
+type slowHealth struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (slowHealth) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Second):
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	}
+}
+
+func (slowHealth) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
+
+func dialBufconn(t *testing.T, m *Middleware) grpc_health_v1.HealthClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(m.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(m.StreamServerInterceptor()),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, slowHealth{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestTimeout_Resolve(t *testing.T) {
+	cfg := Default().WithDuration(5*time.Second).
+		AddRoute("/upload/**", time.Minute, http.MethodPost).
+		AddRoute("/api/*/report", 0).
+		AddRoute("/grpc.health.v1.Health/*", time.Second)
+
+	assert.Equal(t, time.Minute, cfg.Resolve(http.MethodPost, "/upload/file"))
+	assert.Equal(t, time.Minute, cfg.Resolve("post", "/upload"))
+	assert.Equal(t, 5*time.Second, cfg.Resolve(http.MethodGet, "/upload/file"))
+	assert.Equal(t, 5*time.Second, cfg.Resolve(http.MethodPost, "/uploads"))
+	assert.Equal(t, time.Duration(0), cfg.Resolve(http.MethodGet, "/api/v1/report"))
+	assert.Equal(t, time.Second, cfg.Resolve("", "/grpc.health.v1.Health/Check"))
+	assert.Equal(t, 5*time.Second, cfg.Resolve("", "/upload/file"), "限定方法的路由不匹配 gRPC")
+
+	_, err := NewMiddleware(Default().AddRoute("/[", time.Second))
+	assert.Error(t, err)
+	_, err = NewMiddleware(Default().WithDuration(-time.Second))
+	assert.Error(t, err)
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	cfg := Default().Enable().WithDuration(20*time.Millisecond).WithMessage("too slow").
+		AddRoute("/long", time.Second)
+	m, err := NewMiddleware(cfg)
+	require.NoError(t, err)
+
+	var deadline time.Duration
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d, ok := r.Context().Deadline(); ok {
+			deadline = time.Until(d)
+		}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(100 * time.Millisecond):
+			w.Write([]byte("done"))
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/short", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "too slow", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/long", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+	assert.Greater(t, deadline, 500*time.Millisecond, "截止时间按路由配置传递")
+
+	disabled, err := NewMiddleware(Default().WithDuration(time.Millisecond))
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	disabled.Handler(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/short", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "禁用时直接透传到内层处理器")
+}
+
+func TestMiddleware_GRPC(t *testing.T) {
+	cfg := Default().Enable().WithDuration(time.Minute).WithMessage("rpc timed out").
+		AddRoute("/grpc.health.v1.Health/*", 30*time.Millisecond)
+	m, err := NewMiddleware(cfg)
+	require.NoError(t, err)
+	client := dialBufconn(t, m)
+
+	start := time.Now()
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, "rpc timed out", status.Convert(err).Message())
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, "rpc timed out", status.Convert(err).Message())
+}
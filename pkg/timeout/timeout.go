@@ -12,6 +12,8 @@
 package timeout
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/kamalyes/go-config/internal"
@@ -20,10 +22,18 @@ import (
 
 // Timeout 超时中间件配置
 type Timeout struct {
-	ModuleName string        `mapstructure:"module-name" yaml:"module-name" json:"moduleName"` // 模块名称
-	Enabled    bool          `mapstructure:"enabled" yaml:"enabled" json:"enabled"`            // 是否启用超时
-	Duration   time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"`         // 超时时长
-	Message    string        `mapstructure:"message" yaml:"message" json:"message"`            // 超时消息
+	ModuleName string          `mapstructure:"module-name" yaml:"module-name" json:"moduleName"` // 模块名称
+	Enabled    bool            `mapstructure:"enabled" yaml:"enabled" json:"enabled"`            // 是否启用超时
+	Duration   time.Duration   `mapstructure:"duration" yaml:"duration" json:"duration"`         // 超时时长
+	Message    string          `mapstructure:"message" yaml:"message" json:"message"`            // 超时消息
+	Routes     []*RouteTimeout `mapstructure:"routes" yaml:"routes" json:"routes"`               // 按路由覆盖的超时，按顺序匹配
+}
+
+// RouteTimeout 路由级超时
+type RouteTimeout struct {
+	Path     string        `mapstructure:"path" yaml:"path" json:"path"`             // 路径模式，`/**` 结尾匹配前缀，支持 path.Match 通配；gRPC 为完整方法名，如 /pkg.Service/*
+	Methods  []string      `mapstructure:"methods" yaml:"methods" json:"methods"`    // HTTP 方法，留空表示全部；设置后不匹配 gRPC 调用
+	Duration time.Duration `mapstructure:"duration" yaml:"duration" json:"duration"` // 超时时长，0 表示不限制
 }
 
 // Default 创建默认超时配置
@@ -60,6 +70,30 @@ func (t *Timeout) WithMessage(message string) *Timeout {
 	return t
 }
 
+// AddRoute 添加路由级超时
+func (t *Timeout) AddRoute(path string, duration time.Duration, methods ...string) *Timeout {
+	t.Routes = append(t.Routes, &RouteTimeout{Path: path, Methods: methods, Duration: duration})
+	return t
+}
+
+// Resolve 返回请求对应的超时：命中的第一个路由配置，未命中时为 Duration
+//
+// method 为空表示 gRPC 调用，此时 p 为完整方法名，仅匹配未设置 Methods 的路由
+func (t *Timeout) Resolve(method, p string) time.Duration {
+	for _, route := range t.Routes {
+		if route == nil || !internal.MatchPath(route.Path, p) {
+			continue
+		}
+		if len(route.Methods) > 0 && !slices.ContainsFunc(route.Methods, func(m string) bool {
+			return method != "" && strings.EqualFold(m, method)
+		}) {
+			continue
+		}
+		return route.Duration
+	}
+	return t.Duration
+}
+
 // Get 返回配置接口
 func (t *Timeout) Get() interface{} {
 	return t
@@ -0,0 +1,220 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\tracing\provider.go
+ * @Description: 根据追踪配置初始化 OpenTelemetry TracerProvider
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kamalyes/go-config/pkg/jaeger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 导出器类型（比较时忽略大小写与 -、_）
+const (
+	ExporterOTLPGRPC = "otlp"      // OTLP gRPC 导出器
+	ExporterOTLPHTTP = "otlp-http" // OTLP HTTP 导出器
+	ExporterStdout   = "stdout"    // 标准输出导出器
+	ExporterNoop     = "noop"      // 不导出
+)
+
+// ShutdownFunc 刷新剩余 Span 并关闭导出器
+type ShutdownFunc func(ctx context.Context) error
+
+// Provider 由配置构建的 TracerProvider，采样器支持热更新
+type Provider struct {
+	tp      *sdktrace.TracerProvider
+	sampler *reloadableSampler
+}
+
+// Bootstrap 根据追踪配置与可选的 Jaeger 采样配置创建 TracerProvider，并注册为全局实例
+//
+// 未启用追踪时不创建导出器且不采样；返回的 ShutdownFunc 会刷新并关闭导出器
+func Bootstrap(ctx context.Context, t *Tracing, j *jaeger.Jaeger) (*Provider, ShutdownFunc, error) {
+	p, err := NewProvider(ctx, t, j)
+	if err != nil {
+		return nil, nil, err
+	}
+	otel.SetTracerProvider(p.tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return p, p.Shutdown, nil
+}
+
+// NewProvider 创建 TracerProvider，不修改全局实例
+func NewProvider(ctx context.Context, t *Tracing, j *jaeger.Jaeger) (*Provider, error) {
+	if t == nil {
+		return nil, fmt.Errorf("tracing config is nil")
+	}
+
+	sampler, err := BuildSampler(t, j)
+	if err != nil {
+		return nil, err
+	}
+	res, err := BuildResource(ctx, t, j)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Provider{sampler: newReloadableSampler(sampler)}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(p.sampler),
+	}
+	if t.Enabled {
+		exporter, err := NewExporter(ctx, t)
+		if err != nil {
+			return nil, err
+		}
+		if exporter != nil {
+			opts = append(opts, sdktrace.WithBatcher(exporter))
+		}
+	}
+	p.tp = sdktrace.NewTracerProvider(opts...)
+	return p, nil
+}
+
+// TracerProvider 返回底层 TracerProvider
+func (p *Provider) TracerProvider() *sdktrace.TracerProvider {
+	return p.tp
+}
+
+// Tracer 返回指定名称的 Tracer
+func (p *Provider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return p.tp.Tracer(name, opts...)
+}
+
+// Sampler 返回当前生效的采样器
+func (p *Provider) Sampler() sdktrace.Sampler {
+	return p.sampler.Load()
+}
+
+// Reconfigure 热更新采样器，导出器与资源属性保持不变，已创建的 Span 不受影响
+//
+// 配置无效时保留原采样器并返回错误
+func (p *Provider) Reconfigure(t *Tracing, j *jaeger.Jaeger) error {
+	sampler, err := BuildSampler(t, j)
+	if err != nil {
+		return err
+	}
+	p.sampler.Store(sampler)
+	return nil
+}
+
+// ForceFlush 立即导出已结束的 Span
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	return p.tp.ForceFlush(ctx)
+}
+
+// Shutdown 刷新剩余 Span 并关闭导出器
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.tp.Shutdown(ctx)
+}
+
+// BuildResource 构建资源属性：服务名称、版本、环境、自定义属性及 Jaeger 全局标签
+//
+// 同名键以追踪配置的 Attributes 为准，OTEL_RESOURCE_ATTRIBUTES 环境变量优先级最低
+func BuildResource(ctx context.Context, t *Tracing, j *jaeger.Jaeger) (*resource.Resource, error) {
+	var attrs []attribute.KeyValue
+	if j != nil {
+		for k, v := range j.Tags {
+			attrs = append(attrs, attribute.String(k, v))
+		}
+	}
+	if t.ServiceName != "" {
+		attrs = append(attrs, semconv.ServiceName(t.ServiceName))
+	} else if j != nil && j.ServiceName != "" {
+		attrs = append(attrs, semconv.ServiceName(j.ServiceName))
+	}
+	if t.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(t.ServiceVersion))
+	}
+	if t.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentName(t.Environment))
+	}
+	for k, v := range t.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracing resource: %w", err)
+	}
+	return res, nil
+}
+
+// NewExporter 根据导出器类型创建 Span 导出器，noop 类型返回 nil
+//
+// 导出器端点优先使用 ExporterEndpoint，为空时回退到 Endpoint；
+// 带 scheme 的端点按 URL 解析，否则视为 host:port
+func NewExporter(ctx context.Context, t *Tracing) (sdktrace.SpanExporter, error) {
+	endpoint := t.ExporterEndpoint
+	if endpoint == "" {
+		endpoint = t.Endpoint
+	}
+	hasScheme := strings.Contains(endpoint, "://")
+
+	switch normalizeName(t.ExporterType) {
+	case "otlp", "otlpgrpc", "grpc":
+		var opts []otlptracegrpc.Option
+		if endpoint != "" {
+			if hasScheme {
+				opts = append(opts, otlptracegrpc.WithEndpointURL(endpoint))
+			} else {
+				opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+			}
+		}
+		if t.ExporterTLSInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(t.ExporterHeaders) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(t.ExporterHeaders))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case "otlphttp", "http":
+		var opts []otlptracehttp.Option
+		if endpoint != "" {
+			if hasScheme {
+				opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+			} else {
+				opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+			}
+		}
+		if t.ExporterTLSInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(t.ExporterHeaders) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(t.ExporterHeaders))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case "stdout", "console":
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case "noop", "none", "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported exporter type %q", t.ExporterType)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\tracing\provider_test.go
+ * @Description: OpenTelemetry 初始化测试，使用进程内 OTLP 接收端
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package tracing
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver 进程内 OTLP 接收端，记录收到的 Span 与请求头
+type otlpReceiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+	mu      sync.Mutex
+	spans   []*tracepb.ResourceSpans
+	headers map[string]string
+}
+
+func (r *otlpReceiver) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	headers := make(map[string]string, len(md))
+	for k, v := range md {
+		headers[k] = v[0]
+	}
+	r.record(req, headers)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (r *otlpReceiver) record(req *coltracepb.ExportTraceServiceRequest, headers map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, req.ResourceSpans...)
+	r.headers = headers
+}
+
+// spanNames 返回收到的 Span 名称及资源属性
+func (r *otlpReceiver) spanNames() ([]string, map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var names []string
+	attrs := map[string]string{}
+	for _, rs := range r.spans {
+		for _, kv := range rs.GetResource().GetAttributes() {
+			attrs[kv.Key] = kv.Value.GetStringValue()
+		}
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				names = append(names, span.Name)
+			}
+		}
+	}
+	return names, attrs
+}
+
+func newEnabledTracing(exporterType, endpoint string) *Tracing {
+	cfg := Default().Enable()
+	cfg.ExporterType = exporterType
+	cfg.ExporterEndpoint = endpoint
+	cfg.SamplerType = SamplerAlways
+	cfg.Attributes = map[string]string{"team": "platform"}
+	cfg.ExporterHeaders = map[string]string{"x-api-key": "secret"}
+	return cfg
+}
+
+func emitSpans(p *Provider, names ...string) {
+	tracer := p.Tracer("test")
+	for _, name := range names {
+		_, span := tracer.Start(context.Background(), name)
+		span.End()
+	}
+}
+
+func TestBootstrap_OTLPGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver := &otlpReceiver{}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, receiver)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	cfg := newEnabledTracing(ExporterOTLPGRPC, listener.Addr().String())
+	j := jaeger.Default().AddTag("region", "cn-east").AddTag("team", "overridden")
+
+	p, shutdown, err := Bootstrap(context.Background(), cfg, j)
+	require.NoError(t, err)
+	assert.Same(t, p.TracerProvider(), otel.GetTracerProvider())
+
+	emitSpans(p, "GET /users", "GET /orders")
+	require.NoError(t, shutdown(context.Background()))
+
+	names, attrs := receiver.spanNames()
+	assert.ElementsMatch(t, []string{"GET /users", "GET /orders"}, names)
+	assert.Equal(t, "go-rpc-gateway", attrs["service.name"])
+	assert.Equal(t, "1.0.0", attrs["service.version"])
+	assert.Equal(t, "development", attrs["deployment.environment.name"])
+	assert.Equal(t, "cn-east", attrs["region"])
+	assert.Equal(t, "platform", attrs["team"], "追踪配置属性覆盖 Jaeger 标签")
+	assert.Equal(t, "secret", receiver.headers["x-api-key"])
+}
+
+func TestBootstrap_OTLPHTTP(t *testing.T) {
+	receiver := &otlpReceiver{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := &coltracepb.ExportTraceServiceRequest{}
+		if r.URL.Path != "/v1/traces" || proto.Unmarshal(body, req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		receiver.record(req, map[string]string{"x-api-key": r.Header.Get("X-Api-Key")})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(context.Background(), newEnabledTracing(ExporterOTLPHTTP, server.URL+"/v1/traces"), nil)
+	require.NoError(t, err)
+	emitSpans(p, "consume")
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	names, _ := receiver.spanNames()
+	assert.Equal(t, []string{"consume"}, names)
+	assert.Equal(t, "secret", receiver.headers["x-api-key"])
+}
+
+func TestNewProvider_DisabledAndInvalid(t *testing.T) {
+	p, err := NewProvider(context.Background(), Default(), nil)
+	require.NoError(t, err, "未启用时不创建导出器，默认的 zipkin 类型不报错")
+	_, span := p.Tracer("test").Start(context.Background(), "noop")
+	assert.False(t, span.SpanContext().IsSampled())
+	require.NoError(t, p.Shutdown(context.Background()))
+
+	_, err = NewProvider(context.Background(), Default().Enable(), nil)
+	assert.ErrorContains(t, err, `unsupported exporter type "zipkin"`)
+
+	cfg := newEnabledTracing(ExporterNoop, "")
+	cfg.SamplerType = "adaptive"
+	_, err = NewProvider(context.Background(), cfg, nil)
+	assert.ErrorContains(t, err, `unsupported sampler type "adaptive"`)
+}
+
+func TestProvider_ReconfigureKeepsSpans(t *testing.T) {
+	exporter := newMemoryExporter()
+	cfg := newEnabledTracing(ExporterNoop, "")
+	p, err := NewProvider(context.Background(), cfg, nil)
+	require.NoError(t, err)
+	p.tp.RegisterSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter))
+
+	// 采样器切换前开始的 Span 在切换后结束仍然导出
+	_, inflight := p.Tracer("test").Start(context.Background(), "inflight")
+
+	cfg.SamplerType = SamplerNever
+	require.NoError(t, p.Reconfigure(cfg, nil))
+	inflight.End()
+	emitSpans(p, "dropped")
+
+	cfg.SamplerType = "bogus"
+	assert.Error(t, p.Reconfigure(cfg, nil))
+	assert.Equal(t, "AlwaysOffSampler", p.Sampler().Description(), "无效配置保留原采样器")
+
+	j := jaeger.Default().AddOperationSampling("GET /health", 0, 0).AddOperationSampling("checkout", 0, 1)
+	cfg.SamplerType = SamplerAlways
+	require.NoError(t, p.Reconfigure(cfg, j))
+	emitSpans(p, "GET /health", "checkout", "other")
+
+	assert.Equal(t, []string{"inflight", "checkout", "other"}, exporter.names())
+}
+
+func TestBuildSampler(t *testing.T) {
+	params := sdktrace.SamplingParameters{TraceID: trace.TraceID{0xff}, Name: "op"}
+
+	cfg := Default().Enable()
+	cfg.SamplerType = SamplerParentBased
+	cfg.SamplerProbability = 0
+	cfg.SampleRate = 0.25
+	sampler, err := BuildSampler(cfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ParentBased{root:TraceIDRatioBased{0.25},remoteParentSampled:AlwaysOnSampler,remoteParentNotSampled:AlwaysOffSampler,localParentSampled:AlwaysOnSampler,localParentNotSampled:AlwaysOffSampler}", sampler.Description())
+
+	cfg.SamplerType = "rate-limiting"
+	cfg.SamplerRate = 2
+	sampler, err = BuildSampler(cfg, nil)
+	require.NoError(t, err)
+	limiter := sampler.(*rateLimitingSampler)
+	now := time.Unix(0, 0)
+	limiter.last, limiter.now = now, func() time.Time { return now }
+	decisions := []sdktrace.SamplingDecision{}
+	for i := 0; i < 3; i++ {
+		decisions = append(decisions, sampler.ShouldSample(params).Decision)
+	}
+	assert.Equal(t, []sdktrace.SamplingDecision{sdktrace.RecordAndSample, sdktrace.RecordAndSample, sdktrace.Drop}, decisions)
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision, "按速率补充令牌")
+
+	cfg.SamplerType = SamplerProbability
+	cfg.SamplerProbability = 1.5
+	_, err = BuildSampler(cfg, nil)
+	assert.ErrorContains(t, err, "out of range")
+
+	cfg.SamplerProbability = 0.5
+	_, err = BuildSampler(cfg, jaeger.Default().AddOperationSampling("a", 1, 0).AddOperationSampling("a", 1, 0))
+	assert.ErrorContains(t, err, `duplicate operation sampling rule "a"`)
+}
+
+// memoryExporter 内存导出器
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func newMemoryExporter() *memoryExporter {
+	return &memoryExporter{}
+}
+
+func (e *memoryExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error { return nil }
+
+func (e *memoryExporter) names() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	names := make([]string, len(e.spans))
+	for i, span := range e.spans {
+		names[i] = span.Name()
+	}
+	return names
+}
@@ -0,0 +1,239 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-19 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-19 00:00:00
+ * @FilePath: \go-config\pkg\tracing\sampler.go
+ * @Description: 根据追踪配置构建 OpenTelemetry 采样器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+package tracing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamalyes/go-config/pkg/jaeger"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// 采样器类型（比较时忽略大小写与 -、_）
+const (
+	SamplerAlways      = "always"      // 全部采样
+	SamplerNever       = "never"       // 全部丢弃
+	SamplerProbability = "probability" // 按 TraceID 比例采样
+	SamplerParentBased = "parentBased" // 跟随父 Span，根 Span 按比例采样
+	SamplerRate        = "rate"        // 按每秒最大追踪数限流采样
+)
+
+// BuildSampler 根据追踪配置与 Jaeger 按操作采样规则构建采样器
+//
+// 未启用追踪时返回 NeverSample；Jaeger 的按操作规则优先于全局采样器，
+// parentBased 类型下规则只作用于根 Span
+func BuildSampler(t *Tracing, j *jaeger.Jaeger) (sdktrace.Sampler, error) {
+	if t == nil || !t.Enabled {
+		return sdktrace.NeverSample(), nil
+	}
+
+	probability := t.SamplerProbability
+	if probability == 0 {
+		probability = t.SampleRate
+	}
+	if probability < 0 || probability > 1 {
+		return nil, fmt.Errorf("sampler probability %v out of range [0, 1]", probability)
+	}
+
+	var root sdktrace.Sampler
+	parentBased := false
+	switch normalizeName(t.SamplerType) {
+	case "always", "alwayson":
+		root = sdktrace.AlwaysSample()
+	case "never", "alwaysoff":
+		root = sdktrace.NeverSample()
+	case "", "probability", "ratio", "traceidratio":
+		root = sdktrace.TraceIDRatioBased(probability)
+	case "parentbased":
+		root = sdktrace.TraceIDRatioBased(probability)
+		parentBased = true
+	case "rate", "ratelimiting":
+		if t.SamplerRate <= 0 {
+			return nil, fmt.Errorf("sampler rate must be positive for rate sampler, got %v", t.SamplerRate)
+		}
+		root = newRateLimitingSampler(t.SamplerRate)
+	default:
+		return nil, fmt.Errorf("unsupported sampler type %q", t.SamplerType)
+	}
+
+	if j != nil && j.Sampling != nil && len(j.Sampling.OperationSampling) > 0 {
+		rules, err := buildOperationRules(j.Sampling.OperationSampling)
+		if err != nil {
+			return nil, err
+		}
+		root = &operationSampler{rules: rules, fallback: root}
+	}
+
+	if parentBased {
+		return sdktrace.ParentBased(root), nil
+	}
+	return root, nil
+}
+
+// buildOperationRules 构建按操作名称匹配的采样规则
+func buildOperationRules(operations []jaeger.OperationSampling) (map[string]sdktrace.Sampler, error) {
+	rules := make(map[string]sdktrace.Sampler, len(operations))
+	for _, op := range operations {
+		if op.Operation == "" {
+			return nil, fmt.Errorf("operation sampling rule requires an operation name")
+		}
+		if _, exists := rules[op.Operation]; exists {
+			return nil, fmt.Errorf("duplicate operation sampling rule %q", op.Operation)
+		}
+		if op.ProbabilisticSampling < 0 || op.ProbabilisticSampling > 1 {
+			return nil, fmt.Errorf("operation %q probabilistic sampling %v out of range [0, 1]", op.Operation, op.ProbabilisticSampling)
+		}
+
+		var samplers []sdktrace.Sampler
+		if op.ProbabilisticSampling > 0 {
+			samplers = append(samplers, sdktrace.TraceIDRatioBased(op.ProbabilisticSampling))
+		}
+		if op.MaxTracesPerSecond > 0 {
+			samplers = append(samplers, newRateLimitingSampler(float64(op.MaxTracesPerSecond)))
+		}
+		switch len(samplers) {
+		case 0:
+			rules[op.Operation] = sdktrace.NeverSample()
+		case 1:
+			rules[op.Operation] = samplers[0]
+		default:
+			rules[op.Operation] = &allSampler{samplers: samplers}
+		}
+	}
+	return rules, nil
+}
+
+// operationSampler 按 Span 名称匹配规则，未命中时使用兜底采样器
+type operationSampler struct {
+	rules    map[string]sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+func (s *operationSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if rule, ok := s.rules[p.Name]; ok {
+		return rule.ShouldSample(p)
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s *operationSampler) Description() string {
+	return fmt.Sprintf("OperationSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+// allSampler 所有采样器都同意时才采样，按顺序短路，避免未采样时消耗限流令牌
+type allSampler struct {
+	samplers []sdktrace.Sampler
+}
+
+func (s *allSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	var result sdktrace.SamplingResult
+	for _, sampler := range s.samplers {
+		result = sampler.ShouldSample(p)
+		if result.Decision == sdktrace.Drop {
+			return result
+		}
+	}
+	return result
+}
+
+func (s *allSampler) Description() string {
+	names := make([]string, len(s.samplers))
+	for i, sampler := range s.samplers {
+		names[i] = sampler.Description()
+	}
+	return "All{" + strings.Join(names, ",") + "}"
+}
+
+// rateLimitingSampler 令牌桶限流采样器，每秒最多采样 rate 条追踪
+type rateLimitingSampler struct {
+	mu      sync.Mutex
+	rate    float64
+	balance float64
+	max     float64
+	last    time.Time
+	now     func() time.Time
+}
+
+func newRateLimitingSampler(rate float64) *rateLimitingSampler {
+	max := rate
+	if max < 1 {
+		max = 1
+	}
+	return &rateLimitingSampler{rate: rate, balance: max, max: max, last: time.Now(), now: time.Now}
+}
+
+func (s *rateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.mu.Lock()
+	now := s.now()
+	s.balance += now.Sub(s.last).Seconds() * s.rate
+	if s.balance > s.max {
+		s.balance = s.max
+	}
+	s.last = now
+	sampled := s.balance >= 1
+	if sampled {
+		s.balance--
+	}
+	s.mu.Unlock()
+
+	if sampled {
+		return sdktrace.AlwaysSample().ShouldSample(p)
+	}
+	return sdktrace.NeverSample().ShouldSample(p)
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.rate)
+}
+
+// samplerHolder atomic.Value 要求存储类型一致，用结构体包装接口
+type samplerHolder struct {
+	sampler sdktrace.Sampler
+}
+
+// reloadableSampler 可在运行时替换的采样器，替换不影响已创建的 Span 与导出器
+type reloadableSampler struct {
+	current atomic.Value
+}
+
+func newReloadableSampler(sampler sdktrace.Sampler) *reloadableSampler {
+	s := &reloadableSampler{}
+	s.Store(sampler)
+	return s
+}
+
+// Store 替换当前采样器
+func (s *reloadableSampler) Store(sampler sdktrace.Sampler) {
+	s.current.Store(samplerHolder{sampler: sampler})
+}
+
+// Load 返回当前采样器
+func (s *reloadableSampler) Load() sdktrace.Sampler {
+	return s.current.Load().(samplerHolder).sampler
+}
+
+func (s *reloadableSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.Load().ShouldSample(p)
+}
+
+func (s *reloadableSampler) Description() string {
+	return "Reloadable{" + s.Load().Description() + "}"
+}
+
+// normalizeName 统一大小写并去掉 -、_，用于类型名称比较
+func normalizeName(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}